@@ -28,6 +28,7 @@ import (
 
 	"github.com/spf13/cobra"
 	cmdec2 "github.com/tchiunam/axolgo-cli/pkg/cmd/aws/ec2"
+	cmdeks "github.com/tchiunam/axolgo-cli/pkg/cmd/aws/eks"
 	cmdrds "github.com/tchiunam/axolgo-cli/pkg/cmd/aws/rds"
 )
 
@@ -45,6 +46,7 @@ func NewAWSCmd(ctx *context.Context) *cobra.Command {
 
 	cmd.AddCommand(
 		cmdec2.NewEc2Cmd(ctx),
+		cmdeks.NewEksCmd(ctx),
 		cmdrds.NewRdsCmd(ctx),
 	)
 
@@ -40,7 +40,7 @@ func TestNewAWSCmd(t *testing.T) {
 			use:      "aws",
 			short:    "A set of AWS commands",
 			long:     "A set of AWS commands",
-			commands: 2,
+			commands: 3,
 		},
 	}
 
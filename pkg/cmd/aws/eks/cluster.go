@@ -0,0 +1,105 @@
+/*
+Copyright © 2022 tchiunam
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package eks
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awseks "github.com/aws/aws-sdk-go-v2/service/eks"
+)
+
+// ClusterInfo is an EKS cluster
+type ClusterInfo struct {
+	Name            string
+	Arn             string
+	Version         string
+	PlatformVersion string
+	Status          string
+	Endpoint        string
+	// CACertificate is the base64 encoded CA certificate of the cluster
+	CACertificate string
+	Created       time.Time
+}
+
+// DescribeClusterAPIClient is the client to describe EKS clusters
+type DescribeClusterAPIClient interface {
+	DescribeCluster(context.Context, *awseks.DescribeClusterInput, ...func(*awseks.Options)) (*awseks.DescribeClusterOutput, error)
+}
+
+// ListClusters lists the names of the EKS clusters
+func ListClusters(ctx context.Context, client awseks.ListClustersAPIClient) ([]string, error) {
+	var names []string
+	p := awseks.NewListClustersPaginator(client, &awseks.ListClustersInput{})
+	for p.HasMorePages() {
+		output, err := p.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		names = append(names, output.Clusters...)
+	}
+
+	return names, nil
+}
+
+// DescribeCluster describes an EKS cluster
+func DescribeCluster(ctx context.Context, client DescribeClusterAPIClient, name string) (ClusterInfo, error) {
+	output, err := client.DescribeCluster(ctx, &awseks.DescribeClusterInput{Name: aws.String(name)})
+	if err != nil {
+		return ClusterInfo{}, fmt.Errorf("failed to describe cluster %s: %w", name, err)
+	}
+	c := output.Cluster
+	info := ClusterInfo{
+		Name:            aws.ToString(c.Name),
+		Arn:             aws.ToString(c.Arn),
+		Version:         aws.ToString(c.Version),
+		PlatformVersion: aws.ToString(c.PlatformVersion),
+		Status:          string(c.Status),
+		Endpoint:        aws.ToString(c.Endpoint),
+		Created:         aws.ToTime(c.CreatedAt),
+	}
+	if c.CertificateAuthority != nil {
+		info.CACertificate = aws.ToString(c.CertificateAuthority.Data)
+	}
+
+	return info, nil
+}
+
+// clusterRows formats the columns of the clusters
+func clusterRows(clusters []ClusterInfo) [][]string {
+	rows := make([][]string, 0, len(clusters))
+	for _, c := range clusters {
+		endpoint := c.Endpoint
+		if endpoint == "" {
+			endpoint = "-"
+		}
+		rows = append(rows, []string{c.Name, c.Version, c.PlatformVersion, c.Status, endpoint, c.Created.Format(time.RFC3339)})
+	}
+
+	return rows
+}
+
+// The header of the cluster rows
+var clusterHeader = []string{"NAME", "VERSION", "PLATFORM VERSION", "STATUS", "ENDPOINT", "CREATED"}
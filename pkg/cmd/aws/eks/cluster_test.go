@@ -0,0 +1,131 @@
+/*
+Copyright © 2022 tchiunam
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package eks
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awseks "github.com/aws/aws-sdk-go-v2/service/eks"
+	awsekstypes "github.com/aws/aws-sdk-go-v2/service/eks/types"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeClusterClient lists clusters in pages and describes them
+type fakeClusterClient struct {
+	pages    [][]string
+	clusters map[string]*awsekstypes.Cluster
+}
+
+func (f *fakeClusterClient) ListClusters(_ context.Context, input *awseks.ListClustersInput, _ ...func(*awseks.Options)) (*awseks.ListClustersOutput, error) {
+	page := 0
+	if input.NextToken != nil {
+		page, _ = strconv.Atoi(aws.ToString(input.NextToken))
+	}
+	output := &awseks.ListClustersOutput{Clusters: f.pages[page]}
+	if page+1 < len(f.pages) {
+		output.NextToken = aws.String(strconv.Itoa(page + 1))
+	}
+
+	return output, nil
+}
+
+func (f *fakeClusterClient) DescribeCluster(_ context.Context, input *awseks.DescribeClusterInput, _ ...func(*awseks.Options)) (*awseks.DescribeClusterOutput, error) {
+	cluster, ok := f.clusters[aws.ToString(input.Name)]
+	if !ok {
+		return nil, errors.New("ResourceNotFoundException")
+	}
+
+	return &awseks.DescribeClusterOutput{Cluster: cluster}, nil
+}
+
+// TestListClusters tests the ListClusters function
+func TestListClusters(t *testing.T) {
+	client := &fakeClusterClient{pages: [][]string{{"web", "batch"}, {"api"}}}
+	names, err := ListClusters(context.TODO(), client)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"web", "batch", "api"}, names)
+}
+
+// TestDescribeCluster tests the DescribeCluster function and the
+// cluster rows
+func TestDescribeCluster(t *testing.T) {
+	created := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	client := &fakeClusterClient{clusters: map[string]*awsekstypes.Cluster{
+		"web": {
+			Name:                 aws.String("web"),
+			Arn:                  aws.String("arn:aws:eks:ap-east-1:123456789012:cluster/web"),
+			Version:              aws.String("1.24"),
+			PlatformVersion:      aws.String("eks.4"),
+			Status:               awsekstypes.ClusterStatusActive,
+			Endpoint:             aws.String("https://ABCDEF.gr7.ap-east-1.eks.amazonaws.com"),
+			CertificateAuthority: &awsekstypes.Certificate{Data: aws.String("Q0E=")},
+			CreatedAt:            aws.Time(created),
+		},
+		"batch": {
+			Name:      aws.String("batch"),
+			Arn:       aws.String("arn:aws:eks:ap-east-1:123456789012:cluster/batch"),
+			Version:   aws.String("1.24"),
+			Status:    awsekstypes.ClusterStatusCreating,
+			CreatedAt: aws.Time(created),
+		},
+	}}
+
+	cases := map[string]struct {
+		name  string
+		row   []string
+		valid bool
+	}{
+		"active": {
+			name:  "web",
+			row:   []string{"web", "1.24", "eks.4", "ACTIVE", "https://ABCDEF.gr7.ap-east-1.eks.amazonaws.com", "2023-01-01T00:00:00Z"},
+			valid: true,
+		},
+		"creating": {
+			name:  "batch",
+			row:   []string{"batch", "1.24", "", "CREATING", "-", "2023-01-01T00:00:00Z"},
+			valid: true,
+		},
+		"not found": {
+			name: "api",
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			cluster, err := DescribeCluster(context.TODO(), client, c.name)
+			if !c.valid {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			rows := clusterRows([]ClusterInfo{cluster})
+			assert.Equal(t, [][]string{c.row}, rows)
+			assert.Len(t, rows[0], len(clusterHeader))
+		})
+	}
+}
@@ -0,0 +1,90 @@
+/*
+Copyright © 2022 tchiunam
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package eks
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/tchiunam/axolgo-cli/pkg/util"
+)
+
+var (
+	getTokenLong = `Print an ExecCredential with an EKS token for kubectl. The token is
+signed locally with the region and credentials of the profile, and no
+request is sent to AWS. It is the exec credential plugin of the
+kubeconfig users which are written by updateKubeconfig.
+`
+	getTokenExample = `  # Print an ExecCredential of a cluster
+  axolgo aws eks getToken --cluster-name web --region ap-east-1
+`
+)
+
+// GetTokenOptions defines flags and other configuration parameters for the `getToken` command
+type GetTokenOptions struct {
+	ClusterName string
+	Region      string
+	Profile     string
+}
+
+// NewCmdGetToken creates the `getToken` command
+func NewCmdGetToken(ctx *context.Context) *cobra.Command {
+	o := GetTokenOptions{}
+
+	cmd := &cobra.Command{
+		Use:                   "getToken --cluster-name CLUSTER [--region] [--profile]",
+		DisableFlagsInUseLine: true,
+		Short:                 "Print an ExecCredential of an EKS cluster for kubectl.",
+		Long:                  getTokenLong,
+		Example:               getTokenExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := o.complete(ctx, cmd, args); err != nil {
+				panic(err)
+			}
+		},
+	}
+
+	cmd.Flags().StringVar(&o.ClusterName, "cluster-name", "", "Name of the cluster.")
+	cmd.Flags().StringVar(&o.Region, "region", "", "Region of the cluster. Default is the region in axolgo configuration.")
+	cmd.Flags().StringVar(&o.Profile, "profile", "", "Profile to sign the token. Default is the profile in axolgo configuration.")
+
+	cmd.MarkFlagRequired("cluster-name")
+
+	return cmd
+}
+
+// Complete takes the command arguments and execute.
+func (o *GetTokenOptions) complete(ctx *context.Context, _ *cobra.Command, args []string) error {
+	cfg, err := util.LoadAWSConfig(context.TODO(), o.Region, o.Profile)
+	if err != nil {
+		return err
+	}
+	token, expiry, err := BuildToken(context.TODO(), cfg, o.ClusterName, time.Now())
+	if err != nil {
+		return err
+	}
+
+	return util.PrintExecCredential(os.Stdout, token, expiry)
+}
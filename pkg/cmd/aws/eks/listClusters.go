@@ -0,0 +1,100 @@
+/*
+Copyright © 2022 tchiunam
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package eks
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	awseks "github.com/aws/aws-sdk-go-v2/service/eks"
+	"github.com/spf13/cobra"
+	"github.com/tchiunam/axolgo-cli/pkg/util"
+)
+
+var (
+	listClustersLong = `List the EKS clusters of a region with their Kubernetes version,
+platform version, status and endpoint.
+`
+	listClustersExample = `  # List the clusters of the region in axolgo configuration
+  axolgo aws eks listClusters
+
+  # List the clusters of a region with a profile
+  axolgo aws eks listClusters --region ap-east-1 --profile prod
+`
+)
+
+// ListClustersOptions defines flags and other configuration parameters for the `listClusters` command
+type ListClustersOptions struct {
+	Region  string
+	Profile string
+	Output  string
+}
+
+// NewCmdListClusters creates the `listClusters` command
+func NewCmdListClusters(ctx *context.Context) *cobra.Command {
+	o := ListClustersOptions{}
+
+	cmd := &cobra.Command{
+		Use:                   "listClusters [--region] [--profile] [-o]",
+		DisableFlagsInUseLine: true,
+		Short:                 "List EKS clusters.",
+		Long:                  listClustersLong,
+		Example:               listClustersExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := o.complete(ctx, cmd, args); err != nil {
+				panic(err)
+			}
+		},
+	}
+
+	cmd.Flags().StringVar(&o.Region, "region", "", "Region of the clusters. Default is the region in axolgo configuration.")
+	cmd.Flags().StringVar(&o.Profile, "profile", "", "Profile to list the clusters. Default is the profile in axolgo configuration.")
+	cmd.Flags().StringVarP(&o.Output, "output", "o", util.OutputTable, fmt.Sprintf("Output format. One of %v.", util.OutputFormats))
+
+	return cmd
+}
+
+// Complete takes the command arguments and execute.
+func (o *ListClustersOptions) complete(ctx *context.Context, _ *cobra.Command, args []string) error {
+	cfg, err := util.LoadAWSConfig(context.TODO(), o.Region, o.Profile)
+	if err != nil {
+		return err
+	}
+	client := awseks.NewFromConfig(cfg)
+
+	names, err := ListClusters(context.TODO(), client)
+	if err != nil {
+		return fmt.Errorf("failed to list clusters: %w", err)
+	}
+	clusters := make([]ClusterInfo, 0, len(names))
+	for _, name := range names {
+		cluster, err := DescribeCluster(context.TODO(), client, name)
+		if err != nil {
+			return err
+		}
+		clusters = append(clusters, cluster)
+	}
+
+	return util.PrintRecords(os.Stdout, o.Output, clusterHeader, clusterRows(clusters))
+}
@@ -0,0 +1,87 @@
+/*
+Copyright © 2022 tchiunam
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package eks
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+)
+
+// The prefix of an EKS token
+const tokenPrefix = "k8s-aws-v1."
+
+// The header of the cluster name which is signed into the token
+const clusterIDHeader = "x-k8s-aws-id"
+
+// The SHA-256 hash of the empty payload of the presigned request
+const emptyPayloadHash = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+
+// The presigned request is valid for 15 minutes regardless of
+// X-Amz-Expires. The token expires a minute earlier so that kubectl
+// gets a new one before it is rejected.
+const tokenLifetime = 14 * time.Minute
+
+// BuildToken signs an EKS token locally with the region and credentials
+// of the configuration. It is a presigned STS GetCallerIdentity request
+// with the cluster name, which the cluster sends to STS to identify the
+// caller. The token and its expiry are returned.
+func BuildToken(ctx context.Context, cfg aws.Config, cluster string, now time.Time) (string, time.Time, error) {
+	if cfg.Region == "" {
+		return "", time.Time{}, fmt.Errorf("region is required to sign a token")
+	}
+	if cluster == "" {
+		return "", time.Time{}, fmt.Errorf("cluster name is required to sign a token")
+	}
+	creds, err := cfg.Credentials.Retrieve(ctx)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("failed to retrieve credentials: %w", err)
+	}
+
+	req, err := http.NewRequest(http.MethodGet, stsEndpoint(cfg.Region)+"/?Action=GetCallerIdentity&Version=2011-06-15&X-Amz-Expires=60", nil)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	req.Header.Set(clusterIDHeader, cluster)
+	url, _, err := v4.NewSigner().PresignHTTP(ctx, creds, req, emptyPayloadHash, "sts", cfg.Region, now)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("failed to sign token: %w", err)
+	}
+
+	return tokenPrefix + base64.RawURLEncoding.EncodeToString([]byte(url)), now.Add(tokenLifetime), nil
+}
+
+// stsEndpoint returns the regional STS endpoint
+func stsEndpoint(region string) string {
+	if strings.HasPrefix(region, "cn-") {
+		return "https://sts." + region + ".amazonaws.com.cn"
+	}
+
+	return "https://sts." + region + ".amazonaws.com"
+}
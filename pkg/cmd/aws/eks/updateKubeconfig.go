@@ -0,0 +1,146 @@
+/*
+Copyright © 2022 tchiunam
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package eks
+
+import (
+	"context"
+	"fmt"
+
+	awseks "github.com/aws/aws-sdk-go-v2/service/eks"
+	"github.com/spf13/cobra"
+	"github.com/tchiunam/axolgo-cli/pkg/util"
+	"k8s.io/klog/v2"
+)
+
+var (
+	updateKubeconfigLong = `Add or update the cluster, user and context of an EKS cluster in a
+kubeconfig file and make it the current context. The other entries of
+the file are kept. The context is named by the ARN of the cluster unless
+an alias is given. The user gets tokens by running this axolgo binary as
+the exec credential plugin with the same region and profile, so the aws
+CLI is not required.
+`
+	updateKubeconfigExample = `  # Add a cluster to the kubeconfig file
+  axolgo aws eks updateKubeconfig --name web --region ap-east-1
+
+  # Add a cluster with a profile and a context alias to a kubeconfig file
+  axolgo aws eks updateKubeconfig --name web --profile prod --alias web-prod --kubeconfig ~/.kube/eks
+`
+)
+
+// UpdateKubeconfigOptions defines flags and other configuration parameters for the `updateKubeconfig` command
+type UpdateKubeconfigOptions struct {
+	Name       string
+	Region     string
+	Profile    string
+	Kubeconfig string
+	Alias      string
+}
+
+// NewCmdUpdateKubeconfig creates the `updateKubeconfig` command
+func NewCmdUpdateKubeconfig(ctx *context.Context) *cobra.Command {
+	o := UpdateKubeconfigOptions{}
+
+	cmd := &cobra.Command{
+		Use:                   "updateKubeconfig --name CLUSTER [--region] [--profile] [--kubeconfig] [--alias]",
+		DisableFlagsInUseLine: true,
+		Short:                 "Write the credentials of an EKS cluster to a kubeconfig file.",
+		Long:                  updateKubeconfigLong,
+		Example:               updateKubeconfigExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := o.complete(ctx, cmd, args); err != nil {
+				panic(err)
+			}
+		},
+	}
+
+	cmd.Flags().StringVar(&o.Name, "name", "", "Name of the cluster.")
+	cmd.Flags().StringVar(&o.Region, "region", "", "Region of the cluster. Default is the region in axolgo configuration.")
+	cmd.Flags().StringVar(&o.Profile, "profile", "", "Profile to get tokens. Default is the profile in axolgo configuration.")
+	cmd.Flags().StringVar(&o.Kubeconfig, "kubeconfig", "", "Kubeconfig file. Default is the first file in KUBECONFIG or ~/.kube/config.")
+	cmd.Flags().StringVar(&o.Alias, "alias", "", "Name of the context. Default is the ARN of the cluster.")
+
+	cmd.MarkFlagRequired("name")
+
+	return cmd
+}
+
+// Complete takes the command arguments and execute.
+func (o *UpdateKubeconfigOptions) complete(ctx *context.Context, _ *cobra.Command, args []string) error {
+	cfg, err := util.LoadAWSConfig(context.TODO(), o.Region, o.Profile)
+	if err != nil {
+		return err
+	}
+	cluster, err := DescribeCluster(context.TODO(), awseks.NewFromConfig(cfg), o.Name)
+	if err != nil {
+		return err
+	}
+
+	// The plugin signs tokens with the same region and profile
+	execArgs := []string{"aws", "eks", "getToken", "--cluster-name", cluster.Name, "--region", cfg.Region}
+	if o.Profile != "" {
+		execArgs = append(execArgs, "--profile", o.Profile)
+	}
+	exec, err := util.AxolgoExec(execArgs...)
+	if err != nil {
+		return err
+	}
+
+	path := util.KubeconfigPath(o.Kubeconfig)
+	name, err := UpdateKubeconfig(path, cluster, o.Alias, exec)
+	if err != nil {
+		return err
+	}
+	klog.Infof("Added context %s to kubeconfig %s", name, path)
+
+	return nil
+}
+
+// UpdateKubeconfig adds or updates the cluster, user and context of an
+// EKS cluster in a kubeconfig file and makes the context current. The
+// cluster and user are named by the ARN of the cluster and the context
+// is named by the alias if it is given. It returns the name of the
+// context.
+func UpdateKubeconfig(path string, cluster ClusterInfo, alias string, exec util.KubeExec) (string, error) {
+	if cluster.Endpoint == "" {
+		return "", fmt.Errorf("cluster %s has no endpoint, its status is %s", cluster.Name, cluster.Status)
+	}
+
+	k, err := util.LoadKubeconfig(path)
+	if err != nil {
+		return "", err
+	}
+	name := alias
+	if name == "" {
+		name = cluster.Arn
+	}
+	k.SetCluster(cluster.Arn, cluster.Endpoint, cluster.CACertificate)
+	k.SetUser(cluster.Arn, exec)
+	k.SetContext(name, cluster.Arn, cluster.Arn)
+	k.CurrentContext = name
+	if err := k.Save(path); err != nil {
+		return "", fmt.Errorf("failed to write kubeconfig %s: %w", path, err)
+	}
+
+	return name, nil
+}
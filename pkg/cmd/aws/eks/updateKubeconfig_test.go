@@ -0,0 +1,111 @@
+/*
+Copyright © 2022 tchiunam
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package eks
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tchiunam/axolgo-cli/pkg/util"
+)
+
+// TestNewCmdUpdateKubeconfig tests the NewCmdUpdateKubeconfig function
+// to make sure it returns a valid command.
+func TestNewCmdUpdateKubeconfig(t *testing.T) {
+	cases := map[string]struct {
+		use      string
+		short    string
+		hasFlags bool
+	}{
+		"valid command": {
+			use:      "updateKubeconfig --name CLUSTER [--region] [--profile] [--kubeconfig] [--alias]",
+			short:    "Write the credentials of an EKS cluster to a kubeconfig file.",
+			hasFlags: true,
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			cmd := NewCmdUpdateKubeconfig(nil)
+			assert.Equal(t, c.use, cmd.Use)
+			assert.Equal(t, c.short, cmd.Short)
+			assert.Equal(t, c.hasFlags, cmd.Flags().HasFlags())
+		})
+	}
+}
+
+// TestUpdateKubeconfig tests the UpdateKubeconfig function
+func TestUpdateKubeconfig(t *testing.T) {
+	cluster := ClusterInfo{
+		Name:          "web",
+		Arn:           "arn:aws:eks:ap-east-1:123456789012:cluster/web",
+		Status:        "ACTIVE",
+		Endpoint:      "https://ABCDEF.gr7.ap-east-1.eks.amazonaws.com",
+		CACertificate: "Q0E=",
+	}
+	exec := util.KubeExec{Command: "/usr/local/bin/axolgo", Args: []string{"aws", "eks", "getToken", "--cluster-name", "web", "--region", "ap-east-1"}}
+	cases := map[string]struct {
+		cluster ClusterInfo
+		alias   string
+		context string
+		valid   bool
+	}{
+		"ARN": {
+			cluster: cluster,
+			context: "arn:aws:eks:ap-east-1:123456789012:cluster/web",
+			valid:   true,
+		},
+		"alias": {
+			cluster: cluster,
+			alias:   "web-prod",
+			context: "web-prod",
+			valid:   true,
+		},
+		"creating": {
+			cluster: ClusterInfo{Name: "web", Arn: "arn:aws:eks:ap-east-1:123456789012:cluster/web", Status: "CREATING"},
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "config")
+			contextName, err := UpdateKubeconfig(path, c.cluster, c.alias, exec)
+			if !c.valid {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, c.context, contextName)
+
+			k, err := util.LoadKubeconfig(path)
+			assert.NoError(t, err)
+			assert.Equal(t, c.context, k.CurrentContext)
+			assert.Equal(t, c.context, k.Contexts[0].Name)
+			assert.Equal(t, cluster.Arn, k.Contexts[0].Context["cluster"])
+			assert.Equal(t, cluster.Endpoint, k.Clusters[0].Cluster["server"])
+			assert.Equal(t, "Q0E=", k.Clusters[0].Cluster["certificate-authority-data"])
+			assert.Equal(t, cluster.Arn, k.Users[0].Name)
+		})
+	}
+}
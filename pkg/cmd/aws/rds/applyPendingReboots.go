@@ -0,0 +1,495 @@
+/*
+Copyright © 2022 tchiunam
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package rds
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsrds "github.com/aws/aws-sdk-go-v2/service/rds"
+	awsrdstypes "github.com/aws/aws-sdk-go-v2/service/rds/types"
+	"github.com/spf13/cobra"
+	"github.com/tchiunam/axolgo-cli/pkg/util"
+	"k8s.io/klog/v2"
+)
+
+// The parameter apply statuses of an instance which has to be rebooted
+// and which has applied the changes
+const (
+	pendingRebootStatus = "pending-reboot"
+	inSyncStatus        = "in-sync"
+)
+
+// The interval to poll an instance until its reboot starts
+var rebootPollInterval = 10 * time.Second
+
+// The days of week used in maintenance windows
+var maintenanceWindowDays = []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}
+
+var (
+	applyPendingRebootsLong = `Reboot the DB instances which have pending-reboot parameter changes
+of a DB Parameter Group or a DB Cluster Parameter Group.
+
+Reader instances are rebooted before writer instances. Instances are
+rebooted one step at a time, either a batch of instances or all the
+instances in an availability zone, and the next step starts only when
+all instances of a step have started rebooting and are available
+again.
+
+A step is started only if the current time is in the maintenance
+window of every instance of the step, unless
+--ignore-maintenance-window is given. The rollout stops at the first
+step whose windows are not open, so instances with staggered windows
+are rebooted by running the command again in their windows.
+`
+	applyPendingRebootsExample = `  # Show the reboot plan of the instances using a DB Parameter Group
+  axolgo aws rds applyPendingReboots --group standard_group --dry-run
+
+  # Reboot the instances of a DB Cluster Parameter Group one availability zone at a time
+  axolgo aws rds applyPendingReboots --group standard_group --cluster --by az --concurrency 2
+`
+)
+
+// RebootTarget is a DB instance to be rebooted
+type RebootTarget struct {
+	ID                string
+	AvailabilityZone  string
+	Reader            bool
+	MaintenanceWindow string
+}
+
+// ApplyPendingRebootsOptions defines flags and other configuration parameters for the `applyPendingReboots` command
+type ApplyPendingRebootsOptions struct {
+	Group                   string
+	Cluster                 bool
+	By                      string
+	Concurrency             int
+	Timeout                 time.Duration
+	IgnoreMaintenanceWindow bool
+	DryRun                  bool
+	Region                  string
+	Profile                 string
+}
+
+// NewCmdApplyPendingReboots creates the `applyPendingReboots` command
+func NewCmdApplyPendingReboots(ctx *context.Context) *cobra.Command {
+	o := ApplyPendingRebootsOptions{}
+
+	cmd := &cobra.Command{
+		Use:                   "applyPendingReboots -g NAME [-c] [--by] [--concurrency] [--timeout] [--ignore-maintenance-window] [--dry-run] [--region] [--profile]",
+		DisableFlagsInUseLine: true,
+		Short:                 "Reboot instances with pending-reboot parameter changes.",
+		Long:                  applyPendingRebootsLong,
+		Example:               applyPendingRebootsExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := o.complete(ctx, cmd, args); err != nil {
+				panic(err)
+			}
+		},
+	}
+
+	cmd.Flags().StringVarP(&o.Group, "group", "g", "", "Parameter Group Name.")
+	cmd.Flags().BoolVarP(&o.Cluster, "cluster", "c", false, "The group is a DB Cluster Parameter Group.")
+	cmd.Flags().StringVar(&o.By, "by", "instance", "Reboot one batch of instances (instance) or one availability zone (az) at a time.")
+	cmd.Flags().IntVar(&o.Concurrency, "concurrency", 1, "Max. no. of instances rebooted at the same time.")
+	cmd.Flags().DurationVar(&o.Timeout, "timeout", 30*time.Minute, "Max. time to wait for the instances of a step to be available.")
+	cmd.Flags().BoolVar(&o.IgnoreMaintenanceWindow, "ignore-maintenance-window", false, "Reboot even if the current time is not in the maintenance window.")
+	cmd.Flags().BoolVar(&o.DryRun, "dry-run", false, "Print the reboot plan without rebooting.")
+	cmd.Flags().StringVar(&o.Region, "region", "", "Region of the instances. Default is the region in axolgo configuration.")
+	cmd.Flags().StringVar(&o.Profile, "profile", "", "Profile for the instances. Default is the profile in axolgo configuration.")
+
+	cmd.MarkFlagRequired("group")
+
+	return cmd
+}
+
+// Complete takes the command arguments and execute.
+func (o *ApplyPendingRebootsOptions) complete(ctx *context.Context, _ *cobra.Command, args []string) error {
+	if o.By != "instance" && o.By != "az" {
+		return fmt.Errorf("invalid value of --by: %s", o.By)
+	}
+	if o.Concurrency < 1 {
+		return fmt.Errorf("invalid value of --concurrency: %d", o.Concurrency)
+	}
+
+	cfg, err := util.LoadAWSConfig(context.TODO(), o.Region, o.Profile)
+	if err != nil {
+		return err
+	}
+	client := awsrds.NewFromConfig(cfg)
+
+	targets, err := FindRebootTargets(context.TODO(), client, o.Group, o.Cluster)
+	if err != nil {
+		return err
+	}
+	if len(targets) == 0 {
+		klog.Infof("No instance using %s is pending reboot.", o.Group)
+		return nil
+	}
+
+	steps := PlanReboots(targets, o.By == "az", o.Concurrency)
+	if err := printRebootPlan(steps); err != nil {
+		return err
+	}
+	if o.DryRun {
+		return nil
+	}
+
+	for i, step := range steps {
+		if !o.IgnoreMaintenanceWindow {
+			if err := CheckMaintenanceWindows(step, time.Now()); err != nil {
+				return fmt.Errorf("step %d is not started: %w", i+1, err)
+			}
+		}
+		klog.Infof("Step %d/%d: rebooting %d instance(s)", i+1, len(steps), len(step))
+		if err := o.reboot(client, step); err != nil {
+			return fmt.Errorf("step %d failed: %w", i+1, err)
+		}
+	}
+	klog.Infof("Rebooted %d instance(s).", len(targets))
+
+	return nil
+}
+
+// reboot reboots the instances of a step and waits for them to start
+// rebooting and then to be available
+func (o *ApplyPendingRebootsOptions) reboot(client *awsrds.Client, step []RebootTarget) error {
+	waiter := awsrds.NewDBInstanceAvailableWaiter(client)
+	sem := make(chan struct{}, o.Concurrency)
+	errs := make([]error, len(step))
+
+	var wg sync.WaitGroup
+	for i, t := range step {
+		wg.Add(1)
+		go func(i int, t RebootTarget) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			klog.Infof("Rebooting instance %s", t.ID)
+			if _, err := client.RebootDBInstance(context.TODO(), &awsrds.RebootDBInstanceInput{
+				DBInstanceIdentifier: aws.String(t.ID),
+			}); err != nil {
+				errs[i] = fmt.Errorf("failed to reboot instance %s: %w", t.ID, err)
+				return
+			}
+			// The instance may still be available right after the request
+			if err := WaitRebootStarted(context.TODO(), client, t.ID, o.Group, o.Cluster, o.Timeout); err != nil {
+				errs[i] = err
+				return
+			}
+			if err := waiter.Wait(context.TODO(), &awsrds.DescribeDBInstancesInput{
+				DBInstanceIdentifier: aws.String(t.ID),
+			}, o.Timeout); err != nil {
+				errs[i] = fmt.Errorf("instance %s is not available: %w", t.ID, err)
+				return
+			}
+			klog.Infof("Instance %s is available", t.ID)
+		}(i, t)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// RebootStatusAPIClient is a client to describe the DB instances and the
+// DB clusters which they are members of
+type RebootStatusAPIClient interface {
+	awsrds.DescribeDBInstancesAPIClient
+	awsrds.DescribeDBClustersAPIClient
+}
+
+// RebootStarted checks if the reboot of an instance has started, i.e.
+// its status is no longer available or, for a DB Parameter Group, the
+// changes of the group are applied
+func RebootStarted(i awsrdstypes.DBInstance, group string, cluster bool) bool {
+	if aws.ToString(i.DBInstanceStatus) != "available" {
+		return true
+	}
+	if !cluster {
+		for _, g := range i.DBParameterGroups {
+			if aws.ToString(g.DBParameterGroupName) == group &&
+				aws.ToString(g.ParameterApplyStatus) == inSyncStatus {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// WaitRebootStarted polls an instance until its reboot has started. For
+// a DB Cluster Parameter Group, a reboot which has finished between two
+// polls is detected by the changes of the group being applied to the
+// cluster member.
+func WaitRebootStarted(ctx context.Context, client RebootStatusAPIClient, id string, group string, cluster bool, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	for {
+		output, err := client.DescribeDBInstances(ctx, &awsrds.DescribeDBInstancesInput{
+			DBInstanceIdentifier: aws.String(id),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to describe instance %s: %w", id, err)
+		}
+		if len(output.DBInstances) > 0 {
+			i := output.DBInstances[0]
+			if RebootStarted(i, group, cluster) {
+				return nil
+			}
+			if cluster && i.DBClusterIdentifier != nil {
+				status, err := clusterParameterApplyStatus(ctx, client, aws.ToString(i.DBClusterIdentifier), id)
+				if err != nil {
+					return err
+				}
+				if status == inSyncStatus {
+					return nil
+				}
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("reboot of instance %s is not started: %w", id, ctx.Err())
+		case <-time.After(rebootPollInterval):
+		}
+	}
+}
+
+// clusterParameterApplyStatus returns the status of the DB Cluster
+// Parameter Group of a member of a cluster
+func clusterParameterApplyStatus(ctx context.Context, client awsrds.DescribeDBClustersAPIClient, clusterID string, id string) (string, error) {
+	output, err := client.DescribeDBClusters(ctx, &awsrds.DescribeDBClustersInput{
+		DBClusterIdentifier: aws.String(clusterID),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to describe cluster %s: %w", clusterID, err)
+	}
+	for _, c := range output.DBClusters {
+		for _, m := range c.DBClusterMembers {
+			if aws.ToString(m.DBInstanceIdentifier) == id {
+				return aws.ToString(m.DBClusterParameterGroupStatus), nil
+			}
+		}
+	}
+
+	return "", nil
+}
+
+// FindRebootTargets returns the DB instances which are pending reboot
+// to apply the parameter changes of a group.
+func FindRebootTargets(ctx context.Context, client *awsrds.Client, group string, cluster bool) ([]RebootTarget, error) {
+	instances := make(map[string]awsrdstypes.DBInstance)
+	ip := awsrds.NewDescribeDBInstancesPaginator(client, &awsrds.DescribeDBInstancesInput{})
+	for ip.HasMorePages() {
+		output, err := ip.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, i := range output.DBInstances {
+			instances[aws.ToString(i.DBInstanceIdentifier)] = i
+		}
+	}
+
+	// Writers of the clusters and the cluster members which are pending reboot
+	writers := make(map[string]bool)
+	pending := make(map[string]bool)
+	cp := awsrds.NewDescribeDBClustersPaginator(client, &awsrds.DescribeDBClustersInput{})
+	for cp.HasMorePages() {
+		output, err := cp.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, c := range output.DBClusters {
+			for _, m := range c.DBClusterMembers {
+				id := aws.ToString(m.DBInstanceIdentifier)
+				writers[id] = m.IsClusterWriter
+				if cluster && aws.ToString(c.DBClusterParameterGroup) == group &&
+					aws.ToString(m.DBClusterParameterGroupStatus) == pendingRebootStatus {
+					pending[id] = true
+				}
+			}
+		}
+	}
+
+	if !cluster {
+		for id, i := range instances {
+			for _, g := range i.DBParameterGroups {
+				if aws.ToString(g.DBParameterGroupName) == group &&
+					aws.ToString(g.ParameterApplyStatus) == pendingRebootStatus {
+					pending[id] = true
+				}
+			}
+		}
+	}
+
+	targets := make([]RebootTarget, 0, len(pending))
+	for id := range pending {
+		i, ok := instances[id]
+		if !ok {
+			klog.Warningf("Instance %s is pending reboot but not found", id)
+			continue
+		}
+		isWriter, inCluster := writers[id]
+		targets = append(targets, RebootTarget{
+			ID:                id,
+			AvailabilityZone:  aws.ToString(i.AvailabilityZone),
+			Reader:            i.ReadReplicaSourceDBInstanceIdentifier != nil || (inCluster && !isWriter),
+			MaintenanceWindow: aws.ToString(i.PreferredMaintenanceWindow),
+		})
+	}
+	klog.V(3).InfoS("Found reboot targets", "group", group, "cluster", cluster, "len(targets)", len(targets))
+
+	return targets, nil
+}
+
+// PlanReboots arranges the targets into steps. Readers are rebooted
+// before writers. A step is either a batch of no more than concurrency
+// instances or all the instances in an availability zone.
+func PlanReboots(targets []RebootTarget, byAZ bool, concurrency int) [][]RebootTarget {
+	var readers, writers []RebootTarget
+	for _, t := range targets {
+		if t.Reader {
+			readers = append(readers, t)
+		} else {
+			writers = append(writers, t)
+		}
+	}
+
+	steps := make([][]RebootTarget, 0)
+	for _, group := range [][]RebootTarget{readers, writers} {
+		sort.Slice(group, func(i, j int) bool {
+			if group[i].AvailabilityZone != group[j].AvailabilityZone {
+				return group[i].AvailabilityZone < group[j].AvailabilityZone
+			}
+			return group[i].ID < group[j].ID
+		})
+		for len(group) > 0 {
+			n := 1
+			if byAZ {
+				for n < len(group) && group[n].AvailabilityZone == group[0].AvailabilityZone {
+					n++
+				}
+			} else if n = concurrency; n > len(group) {
+				n = len(group)
+			}
+			steps = append(steps, group[:n])
+			group = group[n:]
+		}
+	}
+
+	return steps
+}
+
+// CheckMaintenanceWindows returns an error if the time is not in the
+// maintenance window of every target
+func CheckMaintenanceWindows(targets []RebootTarget, t time.Time) error {
+	for _, target := range targets {
+		in, err := InMaintenanceWindow(target.MaintenanceWindow, t)
+		if err != nil {
+			return fmt.Errorf("instance %s: %w", target.ID, err)
+		}
+		if !in {
+			return fmt.Errorf("instance %s is not in its maintenance window %s", target.ID, target.MaintenanceWindow)
+		}
+	}
+
+	return nil
+}
+
+// InMaintenanceWindow checks if the time is in a maintenance window
+// in the format of ddd:hh24:mi-ddd:hh24:mi in UTC
+func InMaintenanceWindow(window string, t time.Time) (bool, error) {
+	start, end, found := strings.Cut(window, "-")
+	if !found {
+		return false, fmt.Errorf("invalid maintenance window: %s", window)
+	}
+	startMinute, err := minuteOfWeek(start)
+	if err != nil {
+		return false, fmt.Errorf("invalid maintenance window: %s", window)
+	}
+	endMinute, err := minuteOfWeek(end)
+	if err != nil {
+		return false, fmt.Errorf("invalid maintenance window: %s", window)
+	}
+
+	t = t.UTC()
+	now := int(t.Weekday())*24*60 + t.Hour()*60 + t.Minute()
+	if startMinute <= endMinute {
+		return now >= startMinute && now < endMinute, nil
+	}
+	// The window wraps around the end of the week
+	return now >= startMinute || now < endMinute, nil
+}
+
+// minuteOfWeek converts ddd:hh24:mi to the minutes since Sunday 00:00
+func minuteOfWeek(s string) (int, error) {
+	parts := strings.Split(strings.ToLower(s), ":")
+	if len(parts) != 3 {
+		return 0, fmt.Errorf("invalid time: %s", s)
+	}
+	day := -1
+	for i, d := range maintenanceWindowDays {
+		if parts[0] == d {
+			day = i
+		}
+	}
+	hour, err := strconv.Atoi(parts[1])
+	if err != nil || day < 0 || hour < 0 || hour > 23 {
+		return 0, fmt.Errorf("invalid time: %s", s)
+	}
+	minute, err := strconv.Atoi(parts[2])
+	if err != nil || minute < 0 || minute > 59 {
+		return 0, fmt.Errorf("invalid time: %s", s)
+	}
+
+	return day*24*60 + hour*60 + minute, nil
+}
+
+// printRebootPlan prints the steps of the reboot
+func printRebootPlan(steps [][]RebootTarget) error {
+	rows := make([][]string, 0)
+	for i, step := range steps {
+		for _, t := range step {
+			role := "writer"
+			if t.Reader {
+				role = "reader"
+			}
+			rows = append(rows, []string{strconv.Itoa(i + 1), t.ID, t.AvailabilityZone, role, displayValue(t.MaintenanceWindow)})
+		}
+	}
+
+	return util.PrintTable(os.Stdout, []string{"STEP", "INSTANCE", "AVAILABILITY ZONE", "ROLE", "MAINTENANCE WINDOW"}, rows)
+}
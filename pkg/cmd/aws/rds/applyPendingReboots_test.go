@@ -0,0 +1,336 @@
+/*
+Copyright © 2022 tchiunam
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package rds
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsrds "github.com/aws/aws-sdk-go-v2/service/rds"
+	awsrdstypes "github.com/aws/aws-sdk-go-v2/service/rds/types"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestNewCmdApplyPendingReboots tests the NewCmdApplyPendingReboots function
+// to make sure it returns a valid command.
+func TestNewCmdApplyPendingReboots(t *testing.T) {
+	cases := map[string]struct {
+		use      string
+		short    string
+		hasFlags bool
+	}{
+		"valid command": {
+			use:      "applyPendingReboots -g NAME [-c] [--by] [--concurrency] [--timeout] [--ignore-maintenance-window] [--dry-run] [--region] [--profile]",
+			short:    "Reboot instances with pending-reboot parameter changes.",
+			hasFlags: true,
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			cmd := NewCmdApplyPendingReboots(nil)
+			assert.Equal(t, c.use, cmd.Use)
+			assert.Equal(t, c.short, cmd.Short)
+			assert.Equal(t, c.hasFlags, cmd.Flags().HasFlags())
+		})
+	}
+}
+
+// TestNewCmdApplyPendingRebootsInvalid calls the NewCmdApplyPendingReboots function
+// with invalid input and makes sure it returns an error.
+func TestNewCmdApplyPendingRebootsInvalid(t *testing.T) {
+	cases := map[string]struct {
+		args []string
+	}{
+		"invalid by": {
+			args: []string{"--group", "standard-group", "--by", "region"},
+		},
+		"invalid concurrency": {
+			args: []string{"--group", "standard-group", "--concurrency", "0"},
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			oldArgs := os.Args
+			defer func() { os.Args = oldArgs }()
+			os.Args = append([]string{"cmd"}, c.args...)
+
+			cmd := NewCmdApplyPendingReboots(nil)
+			assert.Panics(t, func() { cmd.Execute() })
+		})
+	}
+}
+
+// TestPlanReboots tests the PlanReboots function
+func TestPlanReboots(t *testing.T) {
+	targets := []RebootTarget{
+		{ID: "writer-1", AvailabilityZone: "ap-east-1a"},
+		{ID: "reader-3", AvailabilityZone: "ap-east-1b", Reader: true},
+		{ID: "reader-1", AvailabilityZone: "ap-east-1a", Reader: true},
+		{ID: "reader-2", AvailabilityZone: "ap-east-1a", Reader: true},
+	}
+
+	cases := map[string]struct {
+		byAZ        bool
+		concurrency int
+		steps       [][]string
+	}{
+		"one at a time": {
+			concurrency: 1,
+			steps:       [][]string{{"reader-1"}, {"reader-2"}, {"reader-3"}, {"writer-1"}},
+		},
+		"two at a time": {
+			concurrency: 2,
+			steps:       [][]string{{"reader-1", "reader-2"}, {"reader-3"}, {"writer-1"}},
+		},
+		"one availability zone at a time": {
+			byAZ:        true,
+			concurrency: 1,
+			steps:       [][]string{{"reader-1", "reader-2"}, {"reader-3"}, {"writer-1"}},
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			steps := PlanReboots(targets, c.byAZ, c.concurrency)
+			ids := make([][]string, 0, len(steps))
+			for _, step := range steps {
+				stepIDs := make([]string, 0, len(step))
+				for _, target := range step {
+					stepIDs = append(stepIDs, target.ID)
+				}
+				ids = append(ids, stepIDs)
+			}
+			assert.Equal(t, c.steps, ids)
+		})
+	}
+}
+
+// TestInMaintenanceWindow tests the InMaintenanceWindow function
+func TestInMaintenanceWindow(t *testing.T) {
+	// 2022-12-25 is a Sunday
+	sunday := time.Date(2022, 12, 25, 5, 30, 0, 0, time.UTC)
+
+	cases := map[string]struct {
+		window  string
+		time    time.Time
+		in      bool
+		isError bool
+	}{
+		"in window": {
+			window: "sun:05:00-sun:06:00",
+			time:   sunday,
+			in:     true,
+		},
+		"before window": {
+			window: "sun:06:00-sun:07:00",
+			time:   sunday,
+			in:     false,
+		},
+		"window across days": {
+			window: "sat:23:00-sun:06:00",
+			time:   sunday,
+			in:     true,
+		},
+		"window across weeks": {
+			window: "sat:23:00-sun:01:00",
+			time:   sunday.Add(-5 * time.Hour),
+			in:     true,
+		},
+		"other time zone": {
+			window: "sun:05:00-sun:06:00",
+			time:   sunday.In(time.FixedZone("HKT", 8*60*60)),
+			in:     true,
+		},
+		"invalid day": {
+			window:  "abc:05:00-sun:06:00",
+			time:    sunday,
+			isError: true,
+		},
+		"invalid format": {
+			window:  "sun:05:00",
+			time:    sunday,
+			isError: true,
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			in, err := InMaintenanceWindow(c.window, c.time)
+			if c.isError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, c.in, in)
+			}
+		})
+	}
+}
+
+// TestCheckMaintenanceWindows tests the CheckMaintenanceWindows function
+func TestCheckMaintenanceWindows(t *testing.T) {
+	// 2022-12-25 is a Sunday
+	sunday := time.Date(2022, 12, 25, 5, 30, 0, 0, time.UTC)
+	targets := []RebootTarget{
+		{ID: "db-1", MaintenanceWindow: "sun:05:00-sun:06:00"},
+		{ID: "db-2", MaintenanceWindow: "sat:23:00-sun:06:00"},
+	}
+
+	assert.NoError(t, CheckMaintenanceWindows(targets, sunday))
+	assert.Error(t, CheckMaintenanceWindows(targets, sunday.Add(time.Hour)))
+	assert.Error(t, CheckMaintenanceWindows([]RebootTarget{{ID: "db-3", MaintenanceWindow: "sun"}}, sunday))
+}
+
+// testRebootStatusClient returns the instances, and the statuses of the
+// DB Cluster Parameter Group of db-1, one call at a time
+type testRebootStatusClient struct {
+	instances       []awsrdstypes.DBInstance
+	calls           int
+	clusterStatuses []string
+	clusterCalls    int
+}
+
+func (c *testRebootStatusClient) DescribeDBInstances(_ context.Context, _ *awsrds.DescribeDBInstancesInput, _ ...func(*awsrds.Options)) (*awsrds.DescribeDBInstancesOutput, error) {
+	if c.calls >= len(c.instances) {
+		return nil, errors.New("no more instances")
+	}
+	c.calls++
+
+	return &awsrds.DescribeDBInstancesOutput{DBInstances: c.instances[c.calls-1 : c.calls]}, nil
+}
+
+func (c *testRebootStatusClient) DescribeDBClusters(_ context.Context, _ *awsrds.DescribeDBClustersInput, _ ...func(*awsrds.Options)) (*awsrds.DescribeDBClustersOutput, error) {
+	status := pendingRebootStatus
+	if c.clusterCalls < len(c.clusterStatuses) {
+		status = c.clusterStatuses[c.clusterCalls]
+	}
+	c.clusterCalls++
+
+	return &awsrds.DescribeDBClustersOutput{DBClusters: []awsrdstypes.DBCluster{{
+		DBClusterIdentifier: aws.String("cluster-1"),
+		DBClusterMembers: []awsrdstypes.DBClusterMember{{
+			DBInstanceIdentifier:          aws.String("db-1"),
+			DBClusterParameterGroupStatus: aws.String(status),
+		}},
+	}}}, nil
+}
+
+// testDBInstance returns an instance of a status and a parameter apply status
+func testDBInstance(status string, applyStatus string) awsrdstypes.DBInstance {
+	return awsrdstypes.DBInstance{
+		DBInstanceIdentifier: aws.String("db-1"),
+		DBInstanceStatus:     aws.String(status),
+		DBClusterIdentifier:  aws.String("cluster-1"),
+		DBParameterGroups: []awsrdstypes.DBParameterGroupStatus{{
+			DBParameterGroupName: aws.String("standard_group"),
+			ParameterApplyStatus: aws.String(applyStatus),
+		}},
+	}
+}
+
+// TestWaitRebootStarted tests that WaitRebootStarted waits while the
+// instance is still available with pending changes
+func TestWaitRebootStarted(t *testing.T) {
+	oldInterval := rebootPollInterval
+	defer func() { rebootPollInterval = oldInterval }()
+	rebootPollInterval = time.Millisecond
+
+	cases := map[string]struct {
+		instances       []awsrdstypes.DBInstance
+		clusterStatuses []string
+		cluster         bool
+		calls           int
+		isError         bool
+	}{
+		"rebooting": {
+			instances: []awsrdstypes.DBInstance{
+				testDBInstance("available", pendingRebootStatus),
+				testDBInstance("available", pendingRebootStatus),
+				testDBInstance("rebooting", pendingRebootStatus),
+			},
+			calls: 3,
+		},
+		"in sync": {
+			instances: []awsrdstypes.DBInstance{
+				testDBInstance("available", pendingRebootStatus),
+				testDBInstance("available", inSyncStatus),
+			},
+			calls: 2,
+		},
+		"cluster waits for the status": {
+			instances: []awsrdstypes.DBInstance{
+				testDBInstance("available", inSyncStatus),
+				testDBInstance("rebooting", inSyncStatus),
+			},
+			cluster: true,
+			calls:   2,
+		},
+		"cluster reboot finished between polls": {
+			instances: []awsrdstypes.DBInstance{
+				testDBInstance("available", inSyncStatus),
+				testDBInstance("available", inSyncStatus),
+			},
+			clusterStatuses: []string{pendingRebootStatus, inSyncStatus},
+			cluster:         true,
+			calls:           2,
+		},
+		"describe error": {
+			instances: []awsrdstypes.DBInstance{
+				testDBInstance("available", pendingRebootStatus),
+			},
+			calls:   1,
+			isError: true,
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			client := &testRebootStatusClient{instances: c.instances, clusterStatuses: c.clusterStatuses}
+			err := WaitRebootStarted(context.TODO(), client, "db-1", "standard_group", c.cluster, time.Minute)
+			if c.isError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, c.calls, client.calls)
+		})
+	}
+}
+
+// TestWaitRebootStartedTimeout tests that WaitRebootStarted gives up after the timeout
+func TestWaitRebootStartedTimeout(t *testing.T) {
+	oldInterval := rebootPollInterval
+	defer func() { rebootPollInterval = oldInterval }()
+	rebootPollInterval = 50 * time.Millisecond
+
+	client := &testRebootStatusClient{instances: []awsrdstypes.DBInstance{
+		testDBInstance("available", pendingRebootStatus),
+		testDBInstance("available", pendingRebootStatus),
+	}}
+	assert.Error(t, WaitRebootStarted(context.TODO(), client, "db-1", "standard_group", false, 10*time.Millisecond))
+}
@@ -0,0 +1,156 @@
+/*
+Copyright © 2022 tchiunam
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package rds
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/rds/auth"
+)
+
+// The database engines supported by the `connect` command
+const (
+	EnginePostgres = "postgres"
+	EngineMySQL    = "mysql"
+)
+
+// The default ports of the database engines
+var enginePorts = map[string]int{
+	EnginePostgres: 5432,
+	EngineMySQL:    3306,
+}
+
+// ParseEndpoint splits an endpoint in the format of HOST:PORT. The
+// default port is used if the endpoint has no port. A port is required
+// if the default port is 0.
+func ParseEndpoint(endpoint string, defaultPort int) (string, int, error) {
+	if endpoint == "" {
+		return "", 0, fmt.Errorf("endpoint is empty")
+	}
+	if !strings.Contains(endpoint, ":") {
+		if defaultPort == 0 {
+			return "", 0, fmt.Errorf("invalid endpoint %s: port is missing", endpoint)
+		}
+		return endpoint, defaultPort, nil
+	}
+
+	host, portString, err := net.SplitHostPort(endpoint)
+	if err != nil {
+		return "", 0, fmt.Errorf("invalid endpoint %s: %w", endpoint, err)
+	}
+	port, err := strconv.Atoi(portString)
+	if err != nil || port <= 0 || port > 65535 {
+		return "", 0, fmt.Errorf("invalid port in endpoint %s", endpoint)
+	}
+	if host == "" {
+		return "", 0, fmt.Errorf("invalid endpoint %s: host is missing", endpoint)
+	}
+
+	return host, port, nil
+}
+
+// GuessEngine returns the engine of a port if it is a default port
+func GuessEngine(port int) string {
+	for engine, p := range enginePorts {
+		if p == port {
+			return engine
+		}
+	}
+
+	return ""
+}
+
+// BuildAuthToken signs an IAM database authentication token locally
+// with the region and credentials of the configuration. No request is
+// sent to AWS. The token is valid for 15 minutes.
+func BuildAuthToken(ctx context.Context, cfg aws.Config, host string, port int, user string) (string, error) {
+	if cfg.Region == "" {
+		return "", fmt.Errorf("region is required to sign an authentication token")
+	}
+	if user == "" {
+		return "", fmt.Errorf("database user is required to sign an authentication token")
+	}
+
+	return auth.BuildAuthToken(ctx, net.JoinHostPort(host, strconv.Itoa(port)), cfg.Region, user, cfg.Credentials)
+}
+
+// ClientCommand returns the client program, its arguments and the
+// environment variable of the password to connect to a database with
+// an authentication token. SSL is required for IAM authentication.
+func ClientCommand(engine string, host string, port int, user string, database string) (string, []string, string, error) {
+	switch engine {
+	case EnginePostgres:
+		conninfo := []string{
+			"host=" + host,
+			"port=" + strconv.Itoa(port),
+			"user=" + user,
+			"sslmode=require",
+		}
+		if database != "" {
+			conninfo = append(conninfo, "dbname="+database)
+		}
+		return "psql", []string{strings.Join(conninfo, " ")}, "PGPASSWORD", nil
+	case EngineMySQL:
+		args := []string{
+			"--host=" + host,
+			"--port=" + strconv.Itoa(port),
+			"--user=" + user,
+			"--ssl-mode=REQUIRED",
+			"--enable-cleartext-plugin",
+		}
+		if database != "" {
+			args = append(args, database)
+		}
+		return "mysql", args, "MYSQL_PWD", nil
+	}
+
+	return "", nil, "", fmt.Errorf("invalid engine %s, must be one of %v", engine, engines())
+}
+
+// engines returns the supported engines in order
+func engines() []string {
+	names := make([]string, 0, len(enginePorts))
+	for engine := range enginePorts {
+		names = append(names, engine)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// shellQuote quotes a word for a POSIX shell if it is necessary
+func shellQuote(s string) string {
+	if s != "" && strings.IndexFunc(s, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || strings.ContainsRune("-_./:=@%+,", r))
+	}) < 0 {
+		return s
+	}
+
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
@@ -0,0 +1,211 @@
+/*
+Copyright © 2022 tchiunam
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package rds
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestParseEndpoint tests the ParseEndpoint function
+func TestParseEndpoint(t *testing.T) {
+	cases := map[string]struct {
+		endpoint    string
+		defaultPort int
+		host        string
+		port        int
+		valid       bool
+	}{
+		"host and port": {
+			endpoint: "db.example.com:5432",
+			host:     "db.example.com",
+			port:     5432,
+			valid:    true,
+		},
+		"default port": {
+			endpoint:    "db.example.com",
+			defaultPort: 3306,
+			host:        "db.example.com",
+			port:        3306,
+			valid:       true,
+		},
+		"missing port": {
+			endpoint: "db.example.com",
+		},
+		"invalid port": {
+			endpoint: "db.example.com:port",
+		},
+		"port out of range": {
+			endpoint: "db.example.com:70000",
+		},
+		"missing host": {
+			endpoint: ":5432",
+		},
+		"empty": {
+			endpoint:    "",
+			defaultPort: 5432,
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			host, port, err := ParseEndpoint(c.endpoint, c.defaultPort)
+			if c.valid {
+				assert.NoError(t, err)
+				assert.Equal(t, c.host, host)
+				assert.Equal(t, c.port, port)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
+
+// TestGuessEngine tests the GuessEngine function
+func TestGuessEngine(t *testing.T) {
+	cases := map[string]struct {
+		port   int
+		engine string
+	}{
+		"postgres": {port: 5432, engine: EnginePostgres},
+		"mysql":    {port: 3306, engine: EngineMySQL},
+		"unknown":  {port: 6432, engine: ""},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, c.engine, GuessEngine(c.port))
+		})
+	}
+}
+
+// TestBuildAuthToken tests the BuildAuthToken function
+func TestBuildAuthToken(t *testing.T) {
+	cfg := aws.Config{
+		Region:      "ap-east-1",
+		Credentials: credentials.NewStaticCredentialsProvider("AKIDEXAMPLE", "SECRET", ""),
+	}
+	cases := map[string]struct {
+		cfg   aws.Config
+		user  string
+		valid bool
+	}{
+		"valid token": {
+			cfg:   cfg,
+			user:  "app_user",
+			valid: true,
+		},
+		"missing region": {
+			cfg:  aws.Config{Credentials: cfg.Credentials},
+			user: "app_user",
+		},
+		"missing user": {
+			cfg: cfg,
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			token, err := BuildAuthToken(context.TODO(), c.cfg, "db.example.com", 5432, c.user)
+			if c.valid {
+				assert.NoError(t, err)
+				assert.True(t, strings.HasPrefix(token, "db.example.com:5432?Action=connect&DBUser=app_user&"))
+				assert.Contains(t, token, "X-Amz-Credential=AKIDEXAMPLE%2F")
+				assert.Contains(t, token, "%2Fap-east-1%2Frds-db%2Faws4_request")
+				assert.Contains(t, token, "X-Amz-Expires=900")
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
+
+// TestClientCommand tests the ClientCommand function
+func TestClientCommand(t *testing.T) {
+	cases := map[string]struct {
+		engine      string
+		database    string
+		name        string
+		args        []string
+		passwordEnv string
+		valid       bool
+	}{
+		"postgres": {
+			engine:      EnginePostgres,
+			database:    "app",
+			name:        "psql",
+			args:        []string{"host=db.example.com port=5432 user=app_user sslmode=require dbname=app"},
+			passwordEnv: "PGPASSWORD",
+			valid:       true,
+		},
+		"mysql": {
+			engine:      EngineMySQL,
+			name:        "mysql",
+			args:        []string{"--host=db.example.com", "--port=5432", "--user=app_user", "--ssl-mode=REQUIRED", "--enable-cleartext-plugin"},
+			passwordEnv: "MYSQL_PWD",
+			valid:       true,
+		},
+		"invalid engine": {
+			engine: "oracle",
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			clientName, args, passwordEnv, err := ClientCommand(c.engine, "db.example.com", 5432, "app_user", c.database)
+			if c.valid {
+				assert.NoError(t, err)
+				assert.Equal(t, c.name, clientName)
+				assert.Equal(t, c.args, args)
+				assert.Equal(t, c.passwordEnv, passwordEnv)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
+
+// TestShellQuote tests the shellQuote function
+func TestShellQuote(t *testing.T) {
+	cases := map[string]struct {
+		word   string
+		quoted string
+	}{
+		"plain":        {word: "--host=db.example.com", quoted: "--host=db.example.com"},
+		"space":        {word: "host=db port=5432", quoted: "'host=db port=5432'"},
+		"ampersand":    {word: "a?b&c", quoted: "'a?b&c'"},
+		"single quote": {word: "it's", quoted: `'it'\''s'`},
+		"empty":        {word: "", quoted: "''"},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, c.quoted, shellQuote(c.word))
+		})
+	}
+}
@@ -0,0 +1,135 @@
+/*
+Copyright © 2022 tchiunam
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package rds
+
+import (
+	"context"
+	"os"
+
+	awsrds "github.com/aws/aws-sdk-go-v2/service/rds"
+	"github.com/spf13/cobra"
+	"github.com/tchiunam/axolgo-cli/pkg/util"
+	"k8s.io/klog/v2"
+)
+
+var (
+	compareParameterGroupsLong = `Compare the parameters of two DB Parameter Groups or two DB Cluster
+Parameter Groups. The groups can be in different regions or be accessed
+with different profiles. Only the parameters which have different values
+are printed.
+`
+	compareParameterGroupsExample = `  # Compare two DB Parameter Groups
+  axolgo aws rds compareParameterGroups standard_group new_group
+
+  # Compare DB Cluster Parameter Groups in two regions
+  axolgo aws rds compareParameterGroups standard_group standard_group --cluster --region ap-east-1 --other-region ap-southeast-1
+`
+)
+
+// CompareParameterGroupsOptions defines flags and other configuration parameters for the `compareParameterGroups` command
+type CompareParameterGroupsOptions struct {
+	Cluster      bool
+	Region       string
+	Profile      string
+	OtherRegion  string
+	OtherProfile string
+}
+
+// NewCmdCompareParameterGroups creates the `compareParameterGroups` command
+func NewCmdCompareParameterGroups(ctx *context.Context) *cobra.Command {
+	o := CompareParameterGroupsOptions{}
+
+	cmd := &cobra.Command{
+		Use:                   "compareParameterGroups NAME OTHER_NAME [-c] [--region] [--profile] [--other-region] [--other-profile]",
+		DisableFlagsInUseLine: true,
+		Short:                 "Compare two Parameter Groups.",
+		Long:                  compareParameterGroupsLong,
+		Example:               compareParameterGroupsExample,
+		Args:                  cobra.ExactArgs(2),
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := o.complete(ctx, cmd, args); err != nil {
+				panic(err)
+			}
+		},
+	}
+
+	cmd.Flags().BoolVarP(&o.Cluster, "cluster", "c", false, "Compare DB Cluster Parameter Groups.")
+	cmd.Flags().StringVar(&o.Region, "region", "", "Region of the first group. Default is the region in axolgo configuration.")
+	cmd.Flags().StringVar(&o.Profile, "profile", "", "Profile for the first group. Default is the profile in axolgo configuration.")
+	cmd.Flags().StringVar(&o.OtherRegion, "other-region", "", "Region of the second group. Default is the region of the first group.")
+	cmd.Flags().StringVar(&o.OtherProfile, "other-profile", "", "Profile for the second group. Default is the profile of the first group.")
+
+	return cmd
+}
+
+// Complete takes the command arguments and execute.
+func (o *CompareParameterGroupsOptions) complete(ctx *context.Context, _ *cobra.Command, args []string) error {
+	otherRegion, otherProfile := o.OtherRegion, o.OtherProfile
+	if otherRegion == "" {
+		otherRegion = o.Region
+	}
+	if otherProfile == "" {
+		otherProfile = o.Profile
+	}
+
+	values, err := o.describe(args[0], o.Region, o.Profile)
+	if err != nil {
+		return err
+	}
+	others, err := o.describe(args[1], otherRegion, otherProfile)
+	if err != nil {
+		return err
+	}
+
+	diffs := CompareParameters(values, others)
+	if len(diffs) == 0 {
+		klog.Infof("Parameter groups %s and %s have the same parameters.", args[0], args[1])
+		return nil
+	}
+
+	rows := make([][]string, 0, len(diffs))
+	for _, d := range diffs {
+		rows = append(rows, []string{d.Name, displayValue(d.Value), displayValue(d.Other)})
+	}
+
+	return util.PrintTable(os.Stdout, []string{"PARAMETER", args[0], args[1]}, rows)
+}
+
+// describe returns the parameter values of a group in the region with the profile
+func (o *CompareParameterGroupsOptions) describe(name string, region string, profile string) (map[string]string, error) {
+	cfg, err := util.LoadAWSConfig(context.TODO(), region, profile)
+	if err != nil {
+		return nil, err
+	}
+
+	return DescribeParameterGroup(context.TODO(), awsrds.NewFromConfig(cfg), name, o.Cluster)
+}
+
+// displayValue returns a placeholder for an empty value in the output
+func displayValue(value string) string {
+	if value == "" {
+		return "-"
+	}
+
+	return value
+}
@@ -0,0 +1,148 @@
+/*
+Copyright © 2022 tchiunam
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package rds
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/tchiunam/axolgo-cli/pkg/util"
+	"k8s.io/klog/v2"
+)
+
+var (
+	connectLong = `Connect to a database with IAM database authentication. An
+authentication token is signed locally and used as the password.
+
+By default, the command of the database client is printed with the
+token in the environment so that it can be copied or evaluated. With
+--exec, the client, psql or mysql, is executed directly.
+
+The engine is guessed from the port if it is not given.
+`
+	connectExample = `  # Print the psql command to connect to a database
+  axolgo aws rds connect --endpoint standard-instance.abcdefghijkl.ap-east-1.rds.amazonaws.com:5432 --user app_user --database app
+
+  # Execute the mysql client
+  axolgo aws rds connect -e standard-instance.abcdefghijkl.ap-east-1.rds.amazonaws.com -u app_user --engine mysql --exec
+`
+)
+
+// ConnectOptions defines flags and other configuration parameters for the `connect` command
+type ConnectOptions struct {
+	Endpoint string
+	User     string
+	Database string
+	Engine   string
+	Exec     bool
+	Region   string
+	Profile  string
+}
+
+// NewCmdConnect creates the `connect` command
+func NewCmdConnect(ctx *context.Context) *cobra.Command {
+	o := ConnectOptions{}
+
+	cmd := &cobra.Command{
+		Use:                   "connect -e HOST[:PORT] -u USER [-d] [--engine] [--exec] [--region] [--profile]",
+		DisableFlagsInUseLine: true,
+		Short:                 "Connect to a database with IAM authentication.",
+		Long:                  connectLong,
+		Example:               connectExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := o.complete(ctx, cmd, args); err != nil {
+				panic(err)
+			}
+		},
+	}
+
+	cmd.Flags().StringVarP(&o.Endpoint, "endpoint", "e", "", "Endpoint of the database in the format of HOST:PORT. Default port is the one of the engine.")
+	cmd.Flags().StringVarP(&o.User, "user", "u", "", "Database user.")
+	cmd.Flags().StringVarP(&o.Database, "database", "d", "", "Database name.")
+	cmd.Flags().StringVar(&o.Engine, "engine", "", fmt.Sprintf("Database engine. One of %v.", engines()))
+	cmd.Flags().BoolVar(&o.Exec, "exec", false, "Execute the database client.")
+	cmd.Flags().StringVar(&o.Region, "region", "", "Region of the database. Default is the region in axolgo configuration.")
+	cmd.Flags().StringVar(&o.Profile, "profile", "", "Profile to sign the token. Default is the profile in axolgo configuration.")
+
+	cmd.MarkFlagRequired("endpoint")
+	cmd.MarkFlagRequired("user")
+
+	return cmd
+}
+
+// Complete takes the command arguments and execute.
+func (o *ConnectOptions) complete(ctx *context.Context, _ *cobra.Command, args []string) error {
+	host, port, err := ParseEndpoint(o.Endpoint, enginePorts[o.Engine])
+	if err != nil {
+		return err
+	}
+	engine := o.Engine
+	if engine == "" {
+		if engine = GuessEngine(port); engine == "" {
+			return fmt.Errorf("engine cannot be guessed from port %d, use --engine", port)
+		}
+	}
+	name, clientArgs, passwordEnv, err := ClientCommand(engine, host, port, o.User, o.Database)
+	if err != nil {
+		return err
+	}
+
+	cfg, err := util.LoadAWSConfig(context.TODO(), o.Region, o.Profile)
+	if err != nil {
+		return err
+	}
+	token, err := BuildAuthToken(context.TODO(), cfg, host, port, o.User)
+	if err != nil {
+		return err
+	}
+
+	if !o.Exec {
+		words := []string{passwordEnv + "=" + shellQuote(token), name}
+		for _, a := range clientArgs {
+			words = append(words, shellQuote(a))
+		}
+		fmt.Println(strings.Join(words, " "))
+		return nil
+	}
+
+	klog.V(2).InfoS("Execute database client", "client", name, "host", host, "port", port, "user", o.User)
+	c := exec.Command(name, clientArgs...)
+	c.Env = append(os.Environ(), passwordEnv+"="+token)
+	c.Stdin, c.Stdout, c.Stderr = os.Stdin, os.Stdout, os.Stderr
+
+	if err := c.Run(); err != nil {
+		// Exit with the status of the client as it has printed the error
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			os.Exit(exitErr.ExitCode())
+		}
+		return err
+	}
+
+	return nil
+}
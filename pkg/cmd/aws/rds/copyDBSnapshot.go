@@ -0,0 +1,207 @@
+/*
+Copyright © 2022 tchiunam
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package rds
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsrds "github.com/aws/aws-sdk-go-v2/service/rds"
+	"github.com/spf13/cobra"
+	"github.com/tchiunam/axolgo-cli/pkg/util"
+	"k8s.io/klog/v2"
+)
+
+var (
+	copyDBSnapshotLong = `Copy a DB snapshot or a DB cluster snapshot, usually to another region.
+The copy is encrypted with the given KMS key, which is required when an
+encrypted snapshot is copied to another region because KMS keys are
+regional.
+`
+	copyDBSnapshotExample = `  # Copy a DB snapshot to another region and re-encrypt it
+  axolgo aws rds copyDBSnapshot --source-snapshot standard-instance-20221225-050000 --source-region ap-east-1 --region ap-southeast-1 --kms-key-id alias/rds-backup
+
+  # Copy a DB cluster snapshot in the same region with a new name
+  axolgo aws rds copyDBSnapshot --cluster --source-snapshot standard-cluster-20221225-050000 --target-snapshot standard-cluster-keep
+`
+)
+
+// CopyDBSnapshotOptions defines flags and other configuration parameters for the `copyDBSnapshot` command
+type CopyDBSnapshotOptions struct {
+	SourceSnapshot string
+	TargetSnapshot string
+	SourceRegion   string
+	Cluster        bool
+	KmsKeyID       string
+	Wait           bool
+	Timeout        time.Duration
+	Region         string
+	Profile        string
+}
+
+// NewCmdCopyDBSnapshot creates the `copyDBSnapshot` command
+func NewCmdCopyDBSnapshot(ctx *context.Context) *cobra.Command {
+	o := CopyDBSnapshotOptions{}
+
+	cmd := &cobra.Command{
+		Use:                   "copyDBSnapshot -s SNAPSHOT [--target-snapshot] [--source-region] [--cluster] [--kms-key-id] [--wait] [--timeout] [--region] [--profile]",
+		DisableFlagsInUseLine: true,
+		Short:                 "Copy a DB snapshot.",
+		Long:                  copyDBSnapshotLong,
+		Example:               copyDBSnapshotExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := o.complete(ctx, cmd, args); err != nil {
+				panic(err)
+			}
+		},
+	}
+
+	cmd.Flags().StringVarP(&o.SourceSnapshot, "source-snapshot", "s", "", "Identifier or ARN of the source snapshot.")
+	cmd.Flags().StringVar(&o.TargetSnapshot, "target-snapshot", "", "Identifier of the copy. Default is the identifier of the source snapshot.")
+	cmd.Flags().StringVar(&o.SourceRegion, "source-region", "", "Region of the source snapshot. Default is the region of the copy.")
+	cmd.Flags().BoolVar(&o.Cluster, "cluster", false, "The source is a DB cluster snapshot.")
+	cmd.Flags().StringVar(&o.KmsKeyID, "kms-key-id", "", "KMS key to encrypt the copy with.")
+	cmd.Flags().BoolVar(&o.Wait, "wait", false, "Wait for the copy to be available.")
+	cmd.Flags().DurationVar(&o.Timeout, "timeout", 120*time.Minute, "Max. time to wait for the copy to be available.")
+	cmd.Flags().StringVar(&o.Region, "region", "", "Region of the copy. Default is the region in axolgo configuration.")
+	cmd.Flags().StringVar(&o.Profile, "profile", "", "Profile for the snapshots. Default is the profile in axolgo configuration.")
+
+	cmd.MarkFlagRequired("source-snapshot")
+
+	return cmd
+}
+
+// Complete takes the command arguments and execute.
+func (o *CopyDBSnapshotOptions) complete(ctx *context.Context, _ *cobra.Command, args []string) error {
+	cfg, err := util.LoadAWSConfig(context.TODO(), o.Region, o.Profile)
+	if err != nil {
+		return err
+	}
+	client := awsrds.NewFromConfig(cfg)
+
+	sourceRegion := o.SourceRegion
+	if sourceRegion == "" {
+		sourceRegion = cfg.Region
+	}
+	crossRegion := sourceRegion != cfg.Region
+
+	source := o.SourceSnapshot
+	target := o.TargetSnapshot
+	if target == "" {
+		// The identifier is the last part of an ARN
+		target = source[strings.LastIndex(source, ":")+1:]
+	}
+	// A snapshot in another region must be referred by its ARN
+	if crossRegion && !strings.HasPrefix(source, "arn:") {
+		if source, err = o.sourceARN(sourceRegion); err != nil {
+			return err
+		}
+	}
+	klog.V(3).InfoS("Copy snapshot", "source", source, "sourceRegion", sourceRegion, "target", target, "region", cfg.Region)
+
+	var kmsKeyID, sourceRegionInput *string
+	if o.KmsKeyID != "" {
+		kmsKeyID = aws.String(o.KmsKeyID)
+	}
+	if crossRegion {
+		// The SDK presigns the request in the source region with it
+		sourceRegionInput = aws.String(sourceRegion)
+	}
+
+	if o.Cluster {
+		_, err = client.CopyDBClusterSnapshot(context.TODO(), &awsrds.CopyDBClusterSnapshotInput{
+			SourceDBClusterSnapshotIdentifier: aws.String(source),
+			TargetDBClusterSnapshotIdentifier: aws.String(target),
+			KmsKeyId:                          kmsKeyID,
+			SourceRegion:                      sourceRegionInput,
+			CopyTags:                          aws.Bool(true),
+		})
+	} else {
+		_, err = client.CopyDBSnapshot(context.TODO(), &awsrds.CopyDBSnapshotInput{
+			SourceDBSnapshotIdentifier: aws.String(source),
+			TargetDBSnapshotIdentifier: aws.String(target),
+			KmsKeyId:                   kmsKeyID,
+			SourceRegion:               sourceRegionInput,
+			CopyTags:                   aws.Bool(true),
+		})
+	}
+	if err != nil {
+		return fmt.Errorf("failed to copy snapshot %s: %w", o.SourceSnapshot, err)
+	}
+	klog.Infof("Copying snapshot %s to %s in %s", o.SourceSnapshot, target, cfg.Region)
+
+	if o.Wait {
+		if o.Cluster {
+			err = awsrds.NewDBClusterSnapshotAvailableWaiter(client).Wait(context.TODO(), &awsrds.DescribeDBClusterSnapshotsInput{
+				DBClusterSnapshotIdentifier: aws.String(target),
+			}, o.Timeout)
+		} else {
+			err = awsrds.NewDBSnapshotAvailableWaiter(client).Wait(context.TODO(), &awsrds.DescribeDBSnapshotsInput{
+				DBSnapshotIdentifier: aws.String(target),
+			}, o.Timeout)
+		}
+		if err != nil {
+			return fmt.Errorf("snapshot %s is not available: %w", target, err)
+		}
+		klog.Infof("Snapshot %s is available", target)
+	}
+
+	return nil
+}
+
+// sourceARN looks up the ARN of the source snapshot in its region
+func (o *CopyDBSnapshotOptions) sourceARN(region string) (string, error) {
+	cfg, err := util.LoadAWSConfig(context.TODO(), region, o.Profile)
+	if err != nil {
+		return "", err
+	}
+	client := awsrds.NewFromConfig(cfg)
+
+	if o.Cluster {
+		output, err := client.DescribeDBClusterSnapshots(context.TODO(), &awsrds.DescribeDBClusterSnapshotsInput{
+			DBClusterSnapshotIdentifier: aws.String(o.SourceSnapshot),
+		})
+		if err != nil {
+			return "", err
+		}
+		if len(output.DBClusterSnapshots) == 0 {
+			return "", fmt.Errorf("snapshot %s is not found in %s", o.SourceSnapshot, region)
+		}
+		return aws.ToString(output.DBClusterSnapshots[0].DBClusterSnapshotArn), nil
+	}
+
+	output, err := client.DescribeDBSnapshots(context.TODO(), &awsrds.DescribeDBSnapshotsInput{
+		DBSnapshotIdentifier: aws.String(o.SourceSnapshot),
+	})
+	if err != nil {
+		return "", err
+	}
+	if len(output.DBSnapshots) == 0 {
+		return "", fmt.Errorf("snapshot %s is not found in %s", o.SourceSnapshot, region)
+	}
+
+	return aws.ToString(output.DBSnapshots[0].DBSnapshotArn), nil
+}
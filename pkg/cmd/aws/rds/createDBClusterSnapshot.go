@@ -0,0 +1,140 @@
+/*
+Copyright © 2022 tchiunam
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package rds
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsrds "github.com/aws/aws-sdk-go-v2/service/rds"
+	"github.com/spf13/cobra"
+	"github.com/tchiunam/axolgo-cli/pkg/util"
+	"k8s.io/klog/v2"
+)
+
+var (
+	createDBClusterSnapshotLong = `Create a DB cluster snapshot of each given DB cluster. The snapshot
+identifier is rendered from a Go template. The identifier of the
+DB cluster is available as .ID and the creation time in UTC as .Time.
+The default template is:
+
+  {{ .ID }}-{{ .Time.Format "20060102-150405" }}
+`
+	createDBClusterSnapshotExample = `  # Create a DB cluster snapshot with the default name
+  axolgo aws rds createDBClusterSnapshot --cluster-id standard-cluster
+
+  # Create a DB cluster snapshot with a name for a parameter group change and wait for it
+  axolgo aws rds createDBClusterSnapshot --cluster-id standard-cluster --name-template '{{ .ID }}-before-pg-change-{{ .Time.Format "20060102" }}' --wait
+`
+)
+
+// CreateDBClusterSnapshotOptions defines flags and other configuration parameters for the `createDBClusterSnapshot` command
+type CreateDBClusterSnapshotOptions struct {
+	IDs          []string
+	NameTemplate string
+	Tags         []string
+	Wait         bool
+	Timeout      time.Duration
+	Region       string
+	Profile      string
+}
+
+// NewCmdCreateDBClusterSnapshot creates the `createDBClusterSnapshot` command
+func NewCmdCreateDBClusterSnapshot(ctx *context.Context) *cobra.Command {
+	o := CreateDBClusterSnapshotOptions{}
+
+	cmd := &cobra.Command{
+		Use:                   "createDBClusterSnapshot -c ID [-c ID] [--name-template] [-t] [--wait] [--timeout] [--region] [--profile]",
+		DisableFlagsInUseLine: true,
+		Short:                 "Create DB cluster snapshots.",
+		Long:                  createDBClusterSnapshotLong,
+		Example:               createDBClusterSnapshotExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := o.complete(ctx, cmd, args); err != nil {
+				panic(err)
+			}
+		},
+	}
+
+	cmd.Flags().StringArrayVarP(&o.IDs, "cluster-id", "c", nil, "DB cluster identifier.")
+	cmd.Flags().StringVar(&o.NameTemplate, "name-template", defaultSnapshotNameTemplate, "Template of the snapshot identifier.")
+	cmd.Flags().StringArrayVarP(&o.Tags, "tag", "t", nil, "Tag of the snapshot in the format of KEY=VALUE.")
+	cmd.Flags().BoolVar(&o.Wait, "wait", false, "Wait for the snapshots to be available.")
+	cmd.Flags().DurationVar(&o.Timeout, "timeout", 60*time.Minute, "Max. time to wait for a snapshot to be available.")
+	cmd.Flags().StringVar(&o.Region, "region", "", "Region of the DB clusters. Default is the region in axolgo configuration.")
+	cmd.Flags().StringVar(&o.Profile, "profile", "", "Profile for the DB clusters. Default is the profile in axolgo configuration.")
+
+	cmd.MarkFlagRequired("cluster-id")
+
+	return cmd
+}
+
+// Complete takes the command arguments and execute.
+func (o *CreateDBClusterSnapshotOptions) complete(ctx *context.Context, _ *cobra.Command, args []string) error {
+	tags, err := util.ParseKeyValues(o.Tags)
+	if err != nil {
+		return err
+	}
+
+	// Render all the names before creating any snapshot
+	now := time.Now().UTC()
+	names := make([]string, len(o.IDs))
+	for i, id := range o.IDs {
+		if names[i], err = RenderSnapshotName(o.NameTemplate, SnapshotNameData{ID: id, Time: now}); err != nil {
+			return err
+		}
+	}
+
+	cfg, err := util.LoadAWSConfig(context.TODO(), o.Region, o.Profile)
+	if err != nil {
+		return err
+	}
+	client := awsrds.NewFromConfig(cfg)
+
+	for i, id := range o.IDs {
+		if _, err := client.CreateDBClusterSnapshot(context.TODO(), &awsrds.CreateDBClusterSnapshotInput{
+			DBClusterIdentifier:         aws.String(id),
+			DBClusterSnapshotIdentifier: aws.String(names[i]),
+			Tags:                        buildTags(tags),
+		}); err != nil {
+			return fmt.Errorf("failed to create snapshot of %s: %w", id, err)
+		}
+		klog.Infof("Creating snapshot %s of %s", names[i], id)
+	}
+
+	if o.Wait {
+		waiter := awsrds.NewDBClusterSnapshotAvailableWaiter(client)
+		for _, name := range names {
+			if err := waiter.Wait(context.TODO(), &awsrds.DescribeDBClusterSnapshotsInput{
+				DBClusterSnapshotIdentifier: aws.String(name),
+			}, o.Timeout); err != nil {
+				return fmt.Errorf("snapshot %s is not available: %w", name, err)
+			}
+			klog.Infof("Snapshot %s is available", name)
+		}
+	}
+
+	return nil
+}
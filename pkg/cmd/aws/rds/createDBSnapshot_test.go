@@ -0,0 +1,84 @@
+/*
+Copyright © 2022 tchiunam
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package rds
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestNewCmdCreateDBSnapshot tests the NewCmdCreateDBSnapshot function
+// to make sure it returns a valid command.
+func TestNewCmdCreateDBSnapshot(t *testing.T) {
+	cases := map[string]struct {
+		use      string
+		short    string
+		hasFlags bool
+	}{
+		"valid command": {
+			use:      "createDBSnapshot -i ID [-i ID] [--name-template] [-t] [--wait] [--timeout] [--region] [--profile]",
+			short:    "Create DB snapshots.",
+			hasFlags: true,
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			cmd := NewCmdCreateDBSnapshot(nil)
+			assert.Equal(t, c.use, cmd.Use)
+			assert.Equal(t, c.short, cmd.Short)
+			assert.Equal(t, c.hasFlags, cmd.Flags().HasFlags())
+		})
+	}
+}
+
+// TestNewCmdCreateDBSnapshotInvalid calls the NewCmdCreateDBSnapshot function
+// with invalid input and makes sure it returns an error.
+func TestNewCmdCreateDBSnapshotInvalid(t *testing.T) {
+	cases := map[string]struct {
+		args []string
+	}{
+		"invalid name template": {
+			args: []string{"--instance-id", "standard-instance", "--name-template", "{{ .Name }}"},
+		},
+		"invalid snapshot identifier": {
+			args: []string{"--instance-id", "standard-instance", "--name-template", "{{ .ID }}_manual"},
+		},
+		"invalid tag": {
+			args: []string{"--instance-id", "standard-instance", "--tag", "owner"},
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			oldArgs := os.Args
+			defer func() { os.Args = oldArgs }()
+			os.Args = append([]string{"cmd"}, c.args...)
+
+			cmd := NewCmdCreateDBSnapshot(nil)
+			assert.Panics(t, func() { cmd.Execute() })
+		})
+	}
+}
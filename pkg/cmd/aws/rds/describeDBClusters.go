@@ -0,0 +1,197 @@
+/*
+Copyright © 2022 tchiunam
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package rds
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsrds "github.com/aws/aws-sdk-go-v2/service/rds"
+	awsrdstypes "github.com/aws/aws-sdk-go-v2/service/rds/types"
+	"github.com/spf13/cobra"
+	"github.com/tchiunam/axolgo-cli/pkg/util"
+	"k8s.io/klog/v2"
+)
+
+var (
+	describeDBClustersLong = `Describe DB clusters which are filtered by given criteria.
+Clusters which match any of the values of a filter are included.
+Tags are matched after the clusters are described and a cluster
+must have all the given tags.
+
+The parameter group status is the DB cluster parameter group status
+of the cluster members. Members are listed by their status if they
+are not all in-sync.
+`
+	describeDBClustersExample = `  # Describe a DB cluster
+  axolgo aws rds describeDBClusters --cluster-id standard-cluster
+
+  # Describe the MySQL clusters with a tag in YAML
+  axolgo aws rds describeDBClusters --engine aurora-mysql --tag env=prod --output yaml
+`
+)
+
+// DescribeDBClustersOptions defines flags and other configuration parameters for the `describeDBClusters` command
+type DescribeDBClustersOptions struct {
+	ClusterIDs []string
+	Engines    []string
+	Tags       []string
+	Output     string
+	Region     string
+	Profile    string
+}
+
+// NewCmdDescribeDBClusters creates the `describeDBClusters` command
+func NewCmdDescribeDBClusters(ctx *context.Context) *cobra.Command {
+	o := DescribeDBClustersOptions{}
+
+	cmd := &cobra.Command{
+		Use:                   "describeDBClusters [-c] [-e] [-t] [-o] [--region] [--profile]",
+		DisableFlagsInUseLine: true,
+		Short:                 "Describe DB clusters.",
+		Long:                  describeDBClustersLong,
+		Example:               describeDBClustersExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := o.complete(ctx, cmd, args); err != nil {
+				panic(err)
+			}
+		},
+	}
+
+	cmd.Flags().StringArrayVarP(&o.ClusterIDs, "cluster-id", "c", nil, "DB cluster identifier.")
+	cmd.Flags().StringArrayVarP(&o.Engines, "engine", "e", nil, "Database engine.")
+	cmd.Flags().StringArrayVarP(&o.Tags, "tag", "t", nil, "Tag in the format of KEY=VALUE.")
+	cmd.Flags().StringVarP(&o.Output, "output", "o", util.OutputTable, fmt.Sprintf("Output format. One of %v.", util.OutputFormats))
+	cmd.Flags().StringVar(&o.Region, "region", "", "Region of the clusters. Default is the region in axolgo configuration.")
+	cmd.Flags().StringVar(&o.Profile, "profile", "", "Profile for the clusters. Default is the profile in axolgo configuration.")
+
+	return cmd
+}
+
+// Complete takes the command arguments and execute.
+func (o *DescribeDBClustersOptions) complete(ctx *context.Context, _ *cobra.Command, args []string) error {
+	tags, err := util.ParseKeyValues(o.Tags)
+	if err != nil {
+		return err
+	}
+
+	cfg, err := util.LoadAWSConfig(context.TODO(), o.Region, o.Profile)
+	if err != nil {
+		return err
+	}
+	client := awsrds.NewFromConfig(cfg)
+
+	input := &awsrds.DescribeDBClustersInput{
+		Filters: buildFilters(map[string][]string{
+			"db-cluster-id": o.ClusterIDs,
+			"engine":        o.Engines,
+		}),
+	}
+	var clusters []awsrdstypes.DBCluster
+	p := awsrds.NewDescribeDBClustersPaginator(client, input)
+	for p.HasMorePages() {
+		output, err := p.NextPage(context.TODO())
+		if err != nil {
+			return err
+		}
+		for _, c := range output.DBClusters {
+			if MatchTags(c.TagList, tags) {
+				clusters = append(clusters, c)
+			}
+		}
+	}
+	klog.V(2).InfoS("Described DB clusters", "len(clusters)", len(clusters))
+
+	actions, err := DescribePendingMaintenanceActions(context.TODO(), client)
+	if err != nil {
+		return err
+	}
+
+	rows := make([][]string, 0, len(clusters))
+	for _, c := range clusters {
+		rows = append(rows, dbClusterRow(c, actions[aws.ToString(c.DBClusterArn)]))
+	}
+
+	return util.PrintRecords(
+		os.Stdout,
+		o.Output,
+		[]string{"IDENTIFIER", "STATUS", "ENDPOINT", "READER ENDPOINT", "ENGINE", "CLASS", "MULTI AZ", "STORAGE", "MEMBERS", "PARAMETER GROUP", "PENDING MAINTENANCE"},
+		rows,
+	)
+}
+
+// dbClusterRow formats the columns of a DB cluster
+func dbClusterRow(c awsrdstypes.DBCluster, actions []string) []string {
+	port := aws.ToInt32(c.Port)
+	endpoint, readerEndpoint := "-", "-"
+	if c.Endpoint != nil {
+		endpoint = fmt.Sprintf("%s:%d", *c.Endpoint, port)
+	}
+	if c.ReaderEndpoint != nil {
+		readerEndpoint = fmt.Sprintf("%s:%d", *c.ReaderEndpoint, port)
+	}
+
+	return []string{
+		aws.ToString(c.DBClusterIdentifier),
+		aws.ToString(c.Status),
+		endpoint,
+		readerEndpoint,
+		formatEngine(c.Engine, c.EngineVersion),
+		displayValue(aws.ToString(c.DBClusterInstanceClass)),
+		strconv.FormatBool(aws.ToBool(c.MultiAZ)),
+		formatStorage(aws.ToInt32(c.AllocatedStorage), c.StorageType),
+		strconv.Itoa(len(c.DBClusterMembers)),
+		fmt.Sprintf("%s (%s)", aws.ToString(c.DBClusterParameterGroup), clusterParameterGroupStatus(c.DBClusterMembers)),
+		formatList(actions),
+	}
+}
+
+// clusterParameterGroupStatus summarizes the DB cluster parameter group
+// status of the cluster members
+func clusterParameterGroupStatus(members []awsrdstypes.DBClusterMember) string {
+	var statuses []string
+	counts := make(map[string]int)
+	for _, m := range members {
+		s := aws.ToString(m.DBClusterParameterGroupStatus)
+		if counts[s] == 0 {
+			statuses = append(statuses, s)
+		}
+		counts[s]++
+	}
+
+	switch len(statuses) {
+	case 0:
+		return "-"
+	case 1:
+		return statuses[0]
+	}
+	summary := make([]string, 0, len(statuses))
+	for _, s := range statuses {
+		summary = append(summary, fmt.Sprintf("%d %s", counts[s], s))
+	}
+
+	return formatList(summary)
+}
@@ -0,0 +1,86 @@
+/*
+Copyright © 2022 tchiunam
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package rds
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsrdstypes "github.com/aws/aws-sdk-go-v2/service/rds/types"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestNewCmdDescribeDBClusters tests the NewCmdDescribeDBClusters function
+// to make sure it returns a valid command.
+func TestNewCmdDescribeDBClusters(t *testing.T) {
+	cases := map[string]struct {
+		use      string
+		short    string
+		hasFlags bool
+	}{
+		"valid command": {
+			use:      "describeDBClusters [-c] [-e] [-t] [-o] [--region] [--profile]",
+			short:    "Describe DB clusters.",
+			hasFlags: true,
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			cmd := NewCmdDescribeDBClusters(nil)
+			assert.Equal(t, c.use, cmd.Use)
+			assert.Equal(t, c.short, cmd.Short)
+			assert.Equal(t, c.hasFlags, cmd.Flags().HasFlags())
+		})
+	}
+}
+
+// TestClusterParameterGroupStatus tests the clusterParameterGroupStatus function
+func TestClusterParameterGroupStatus(t *testing.T) {
+	cases := map[string]struct {
+		statuses []string
+		status   string
+	}{
+		"no member": {
+			status: "-",
+		},
+		"all in-sync": {
+			statuses: []string{"in-sync", "in-sync"},
+			status:   "in-sync",
+		},
+		"pending reboot": {
+			statuses: []string{"in-sync", "pending-reboot", "pending-reboot"},
+			status:   "1 in-sync,2 pending-reboot",
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			members := make([]awsrdstypes.DBClusterMember, 0, len(c.statuses))
+			for _, s := range c.statuses {
+				members = append(members, awsrdstypes.DBClusterMember{DBClusterParameterGroupStatus: aws.String(s)})
+			}
+			assert.Equal(t, c.status, clusterParameterGroupStatus(members))
+		})
+	}
+}
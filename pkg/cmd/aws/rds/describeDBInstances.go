@@ -0,0 +1,168 @@
+/*
+Copyright © 2022 tchiunam
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package rds
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsrds "github.com/aws/aws-sdk-go-v2/service/rds"
+	awsrdstypes "github.com/aws/aws-sdk-go-v2/service/rds/types"
+	"github.com/spf13/cobra"
+	"github.com/tchiunam/axolgo-cli/pkg/util"
+	"k8s.io/klog/v2"
+)
+
+var (
+	describeDBInstancesLong = `Describe DB instances which are filtered by given criteria.
+Instances which match any of the values of a filter are included.
+Tags are matched after the instances are described and an instance
+must have all the given tags.
+`
+	describeDBInstancesExample = `  # Describe the PostgreSQL instances of a cluster
+  axolgo aws rds describeDBInstances --cluster-id standard-cluster --engine aurora-postgresql
+
+  # Describe the instances with a tag in JSON
+  axolgo aws rds describeDBInstances --tag env=prod --output json
+`
+)
+
+// DescribeDBInstancesOptions defines flags and other configuration parameters for the `describeDBInstances` command
+type DescribeDBInstancesOptions struct {
+	InstanceIDs []string
+	ClusterIDs  []string
+	Engines     []string
+	Tags        []string
+	Output      string
+	Region      string
+	Profile     string
+}
+
+// NewCmdDescribeDBInstances creates the `describeDBInstances` command
+func NewCmdDescribeDBInstances(ctx *context.Context) *cobra.Command {
+	o := DescribeDBInstancesOptions{}
+
+	cmd := &cobra.Command{
+		Use:                   "describeDBInstances [-i] [-c] [-e] [-t] [-o] [--region] [--profile]",
+		DisableFlagsInUseLine: true,
+		Short:                 "Describe DB instances.",
+		Long:                  describeDBInstancesLong,
+		Example:               describeDBInstancesExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := o.complete(ctx, cmd, args); err != nil {
+				panic(err)
+			}
+		},
+	}
+
+	cmd.Flags().StringArrayVarP(&o.InstanceIDs, "instance-id", "i", nil, "DB instance identifier.")
+	cmd.Flags().StringArrayVarP(&o.ClusterIDs, "cluster-id", "c", nil, "DB cluster identifier.")
+	cmd.Flags().StringArrayVarP(&o.Engines, "engine", "e", nil, "Database engine.")
+	cmd.Flags().StringArrayVarP(&o.Tags, "tag", "t", nil, "Tag in the format of KEY=VALUE.")
+	cmd.Flags().StringVarP(&o.Output, "output", "o", util.OutputTable, fmt.Sprintf("Output format. One of %v.", util.OutputFormats))
+	cmd.Flags().StringVar(&o.Region, "region", "", "Region of the instances. Default is the region in axolgo configuration.")
+	cmd.Flags().StringVar(&o.Profile, "profile", "", "Profile for the instances. Default is the profile in axolgo configuration.")
+
+	return cmd
+}
+
+// Complete takes the command arguments and execute.
+func (o *DescribeDBInstancesOptions) complete(ctx *context.Context, _ *cobra.Command, args []string) error {
+	tags, err := util.ParseKeyValues(o.Tags)
+	if err != nil {
+		return err
+	}
+
+	cfg, err := util.LoadAWSConfig(context.TODO(), o.Region, o.Profile)
+	if err != nil {
+		return err
+	}
+	client := awsrds.NewFromConfig(cfg)
+
+	input := &awsrds.DescribeDBInstancesInput{
+		Filters: buildFilters(map[string][]string{
+			"db-instance-id": o.InstanceIDs,
+			"db-cluster-id":  o.ClusterIDs,
+			"engine":         o.Engines,
+		}),
+	}
+	var instances []awsrdstypes.DBInstance
+	p := awsrds.NewDescribeDBInstancesPaginator(client, input)
+	for p.HasMorePages() {
+		output, err := p.NextPage(context.TODO())
+		if err != nil {
+			return err
+		}
+		for _, i := range output.DBInstances {
+			if MatchTags(i.TagList, tags) {
+				instances = append(instances, i)
+			}
+		}
+	}
+	klog.V(2).InfoS("Described DB instances", "len(instances)", len(instances))
+
+	actions, err := DescribePendingMaintenanceActions(context.TODO(), client)
+	if err != nil {
+		return err
+	}
+
+	rows := make([][]string, 0, len(instances))
+	for _, i := range instances {
+		rows = append(rows, dbInstanceRow(i, actions[aws.ToString(i.DBInstanceArn)]))
+	}
+
+	return util.PrintRecords(
+		os.Stdout,
+		o.Output,
+		[]string{"IDENTIFIER", "STATUS", "ENDPOINT", "ENGINE", "CLASS", "MULTI AZ", "STORAGE", "PARAMETER GROUP", "PENDING MAINTENANCE"},
+		rows,
+	)
+}
+
+// dbInstanceRow formats the columns of a DB instance
+func dbInstanceRow(i awsrdstypes.DBInstance, actions []string) []string {
+	endpoint := "-"
+	if i.Endpoint != nil {
+		endpoint = fmt.Sprintf("%s:%d", aws.ToString(i.Endpoint.Address), i.Endpoint.Port)
+	}
+
+	parameterGroups := make([]string, 0, len(i.DBParameterGroups))
+	for _, g := range i.DBParameterGroups {
+		parameterGroups = append(parameterGroups, fmt.Sprintf("%s (%s)", aws.ToString(g.DBParameterGroupName), aws.ToString(g.ParameterApplyStatus)))
+	}
+
+	return []string{
+		aws.ToString(i.DBInstanceIdentifier),
+		aws.ToString(i.DBInstanceStatus),
+		endpoint,
+		formatEngine(i.Engine, i.EngineVersion),
+		aws.ToString(i.DBInstanceClass),
+		strconv.FormatBool(i.MultiAZ),
+		formatStorage(i.AllocatedStorage, i.StorageType),
+		formatList(parameterGroups),
+		formatList(actions),
+	}
+}
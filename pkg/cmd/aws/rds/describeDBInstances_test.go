@@ -0,0 +1,97 @@
+/*
+Copyright © 2022 tchiunam
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package rds
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsrdstypes "github.com/aws/aws-sdk-go-v2/service/rds/types"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestNewCmdDescribeDBInstances tests the NewCmdDescribeDBInstances function
+// to make sure it returns a valid command.
+func TestNewCmdDescribeDBInstances(t *testing.T) {
+	cases := map[string]struct {
+		use      string
+		short    string
+		hasFlags bool
+	}{
+		"valid command": {
+			use:      "describeDBInstances [-i] [-c] [-e] [-t] [-o] [--region] [--profile]",
+			short:    "Describe DB instances.",
+			hasFlags: true,
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			cmd := NewCmdDescribeDBInstances(nil)
+			assert.Equal(t, c.use, cmd.Use)
+			assert.Equal(t, c.short, cmd.Short)
+			assert.Equal(t, c.hasFlags, cmd.Flags().HasFlags())
+		})
+	}
+}
+
+// TestDBInstanceRow tests the dbInstanceRow function
+func TestDBInstanceRow(t *testing.T) {
+	cases := map[string]struct {
+		instance awsrdstypes.DBInstance
+		actions  []string
+		row      []string
+	}{
+		"available instance": {
+			instance: awsrdstypes.DBInstance{
+				DBInstanceIdentifier: aws.String("db-1"),
+				DBInstanceStatus:     aws.String("available"),
+				Endpoint:             &awsrdstypes.Endpoint{Address: aws.String("db-1.example.com"), Port: 5432},
+				Engine:               aws.String("postgres"),
+				EngineVersion:        aws.String("14.5"),
+				DBInstanceClass:      aws.String("db.r6g.large"),
+				MultiAZ:              true,
+				AllocatedStorage:     100,
+				StorageType:          aws.String("gp3"),
+				DBParameterGroups: []awsrdstypes.DBParameterGroupStatus{
+					{DBParameterGroupName: aws.String("standard-group"), ParameterApplyStatus: aws.String("pending-reboot")},
+				},
+			},
+			actions: []string{"system-update"},
+			row:     []string{"db-1", "available", "db-1.example.com:5432", "postgres 14.5", "db.r6g.large", "true", "100 GiB gp3", "standard-group (pending-reboot)", "system-update"},
+		},
+		"creating instance": {
+			instance: awsrdstypes.DBInstance{
+				DBInstanceIdentifier: aws.String("db-2"),
+				DBInstanceStatus:     aws.String("creating"),
+			},
+			row: []string{"db-2", "creating", "-", "", "", "false", "-", "-", "-"},
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, c.row, dbInstanceRow(c.instance, c.actions))
+		})
+	}
+}
@@ -0,0 +1,113 @@
+/*
+Copyright © 2022 tchiunam
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package rds
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	awsrds "github.com/aws/aws-sdk-go-v2/service/rds"
+	"github.com/spf13/cobra"
+	"github.com/tchiunam/axolgo-cli/pkg/util"
+)
+
+var (
+	describeDBLogFilesLong = `Describe the log files of a DB instance with their size and the time
+when they were last written.
+`
+	describeDBLogFilesExample = `  # Describe the log files of a DB instance
+  axolgo aws rds describeDBLogFiles --instance-id standard-instance
+
+  # Describe the error log files written in the last 2 hours
+  axolgo aws rds describeDBLogFiles --instance-id standard-instance --filename-contains error --since 2h
+`
+)
+
+// DescribeDBLogFilesOptions defines flags and other configuration parameters for the `describeDBLogFiles` command
+type DescribeDBLogFilesOptions struct {
+	InstanceID       string
+	FilenameContains string
+	Since            string
+	Output           string
+	Region           string
+	Profile          string
+}
+
+// NewCmdDescribeDBLogFiles creates the `describeDBLogFiles` command
+func NewCmdDescribeDBLogFiles(ctx *context.Context) *cobra.Command {
+	o := DescribeDBLogFilesOptions{}
+
+	cmd := &cobra.Command{
+		Use:                   "describeDBLogFiles -i ID [--filename-contains] [--since] [-o] [--region] [--profile]",
+		DisableFlagsInUseLine: true,
+		Short:                 "Describe DB log files.",
+		Long:                  describeDBLogFilesLong,
+		Example:               describeDBLogFilesExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := o.complete(ctx, cmd, args); err != nil {
+				panic(err)
+			}
+		},
+	}
+
+	cmd.Flags().StringVarP(&o.InstanceID, "instance-id", "i", "", "DB instance identifier.")
+	cmd.Flags().StringVar(&o.FilenameContains, "filename-contains", "", "Only describe the log files whose names contain this string.")
+	cmd.Flags().StringVar(&o.Since, "since", "", "Only describe the log files written within this age, e.g. 1d or 2h.")
+	cmd.Flags().StringVarP(&o.Output, "output", "o", util.OutputTable, fmt.Sprintf("Output format. One of %v.", util.OutputFormats))
+	cmd.Flags().StringVar(&o.Region, "region", "", "Region of the DB instance. Default is the region in axolgo configuration.")
+	cmd.Flags().StringVar(&o.Profile, "profile", "", "Profile for the DB instance. Default is the profile in axolgo configuration.")
+
+	cmd.MarkFlagRequired("instance-id")
+
+	return cmd
+}
+
+// Complete takes the command arguments and execute.
+func (o *DescribeDBLogFilesOptions) complete(ctx *context.Context, _ *cobra.Command, args []string) error {
+	age, err := util.ParseAge(o.Since)
+	if err != nil {
+		return err
+	}
+	var since time.Time
+	if age > 0 {
+		since = time.Now().Add(-age)
+	}
+
+	cfg, err := util.LoadAWSConfig(context.TODO(), o.Region, o.Profile)
+	if err != nil {
+		return err
+	}
+	files, err := DescribeLogFiles(context.TODO(), awsrds.NewFromConfig(cfg), o.InstanceID, o.FilenameContains, since)
+	if err != nil {
+		return err
+	}
+
+	rows := make([][]string, 0, len(files))
+	for _, f := range files {
+		rows = append(rows, []string{f.Name, FormatBytes(f.Size), f.LastWritten.UTC().Format(time.RFC3339)})
+	}
+
+	return util.PrintRecords(os.Stdout, o.Output, []string{"LOG FILE", "SIZE", "LAST WRITTEN"}, rows)
+}
@@ -0,0 +1,100 @@
+/*
+Copyright © 2022 tchiunam
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package rds
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	awsrds "github.com/aws/aws-sdk-go-v2/service/rds"
+	"github.com/spf13/cobra"
+	"github.com/tchiunam/axolgo-cli/pkg/util"
+	"k8s.io/klog/v2"
+)
+
+var (
+	describeDBSnapshotsLong = `Describe DB snapshots or DB cluster snapshots with their age and
+size, sorted by creation time with the newest first.
+`
+	describeDBSnapshotsExample = `  # Describe the manual snapshots of a DB instance
+  axolgo aws rds describeDBSnapshots --id standard-instance --type manual
+
+  # Describe the snapshots of a DB cluster in JSON
+  axolgo aws rds describeDBSnapshots --id standard-cluster --cluster --output json
+`
+)
+
+// DescribeDBSnapshotsOptions defines flags and other configuration parameters for the `describeDBSnapshots` command
+type DescribeDBSnapshotsOptions struct {
+	ID      string
+	Cluster bool
+	Type    string
+	Output  string
+	Region  string
+	Profile string
+}
+
+// NewCmdDescribeDBSnapshots creates the `describeDBSnapshots` command
+func NewCmdDescribeDBSnapshots(ctx *context.Context) *cobra.Command {
+	o := DescribeDBSnapshotsOptions{}
+
+	cmd := &cobra.Command{
+		Use:                   "describeDBSnapshots [-i] [--cluster] [--type] [-o] [--region] [--profile]",
+		DisableFlagsInUseLine: true,
+		Short:                 "Describe DB snapshots.",
+		Long:                  describeDBSnapshotsLong,
+		Example:               describeDBSnapshotsExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := o.complete(ctx, cmd, args); err != nil {
+				panic(err)
+			}
+		},
+	}
+
+	cmd.Flags().StringVarP(&o.ID, "id", "i", "", "Identifier of the DB instance or DB cluster. Default is all.")
+	cmd.Flags().BoolVar(&o.Cluster, "cluster", false, "Describe DB cluster snapshots.")
+	cmd.Flags().StringVar(&o.Type, "type", "", "Snapshot type, e.g. manual or automated. Default is all.")
+	cmd.Flags().StringVarP(&o.Output, "output", "o", util.OutputTable, fmt.Sprintf("Output format. One of %v.", util.OutputFormats))
+	cmd.Flags().StringVar(&o.Region, "region", "", "Region of the snapshots. Default is the region in axolgo configuration.")
+	cmd.Flags().StringVar(&o.Profile, "profile", "", "Profile for the snapshots. Default is the profile in axolgo configuration.")
+
+	return cmd
+}
+
+// Complete takes the command arguments and execute.
+func (o *DescribeDBSnapshotsOptions) complete(ctx *context.Context, _ *cobra.Command, args []string) error {
+	cfg, err := util.LoadAWSConfig(context.TODO(), o.Region, o.Profile)
+	if err != nil {
+		return err
+	}
+
+	snapshots, err := DescribeSnapshots(context.TODO(), awsrds.NewFromConfig(cfg), o.ID, o.Cluster, o.Type)
+	if err != nil {
+		return err
+	}
+	klog.V(2).InfoS("Described snapshots", "len(snapshots)", len(snapshots))
+
+	return util.PrintRecords(os.Stdout, o.Output, snapshotHeader, snapshotRows(snapshots, time.Now()))
+}
@@ -0,0 +1,194 @@
+/*
+Copyright © 2022 tchiunam
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package rds
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"regexp"
+	"syscall"
+	"time"
+
+	awsrds "github.com/aws/aws-sdk-go-v2/service/rds"
+	"github.com/spf13/cobra"
+	"github.com/tchiunam/axolgo-cli/pkg/util"
+	"k8s.io/klog/v2"
+)
+
+var (
+	downloadDBLogFilePortionLong = `Download a log file of a DB instance. The whole log file is downloaded
+portion by portion and written to a local file or to the standard output.
+
+With --follow, the last lines of the log file are printed and the new
+portions are polled and streamed to the standard output until the
+command is interrupted. Only the lines which match --grep are written.
+`
+	downloadDBLogFilePortionExample = `  # Download a log file
+  axolgo aws rds downloadDBLogFilePortion --instance-id standard-instance --log-file error/postgresql.log.2022-12-25-05 --output-file postgresql.log
+
+  # Follow the slow queries in a log file
+  axolgo aws rds downloadDBLogFilePortion -i standard-instance -l error/postgresql.log.2022-12-25-05 --follow --grep 'duration: [0-9]{4,}'
+`
+)
+
+// DownloadDBLogFilePortionOptions defines flags and other configuration parameters for the `downloadDBLogFilePortion` command
+type DownloadDBLogFilePortionOptions struct {
+	InstanceID  string
+	LogFile     string
+	OutputFile  string
+	Follow      bool
+	Tail        int32
+	Interval    time.Duration
+	Grep        string
+	InvertMatch bool
+	IgnoreCase  bool
+	Region      string
+	Profile     string
+}
+
+// NewCmdDownloadDBLogFilePortion creates the `downloadDBLogFilePortion` command
+func NewCmdDownloadDBLogFilePortion(ctx *context.Context) *cobra.Command {
+	o := DownloadDBLogFilePortionOptions{}
+
+	cmd := &cobra.Command{
+		Use:                   "downloadDBLogFilePortion -i ID -l LOG_FILE [--output-file] [-f] [--tail] [--interval] [--grep] [--invert-match] [--ignore-case] [--region] [--profile]",
+		DisableFlagsInUseLine: true,
+		Short:                 "Download or follow a DB log file.",
+		Long:                  downloadDBLogFilePortionLong,
+		Example:               downloadDBLogFilePortionExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := o.complete(ctx, cmd, args); err != nil {
+				panic(err)
+			}
+		},
+	}
+
+	cmd.Flags().StringVarP(&o.InstanceID, "instance-id", "i", "", "DB instance identifier.")
+	cmd.Flags().StringVarP(&o.LogFile, "log-file", "l", "", "Name of the log file.")
+	cmd.Flags().StringVar(&o.OutputFile, "output-file", "", "File to write the log to. Default is the standard output.")
+	cmd.Flags().BoolVarP(&o.Follow, "follow", "f", false, "Poll and stream the new portions of the log file.")
+	cmd.Flags().Int32Var(&o.Tail, "tail", 10, "Number of the last lines to print before following.")
+	cmd.Flags().DurationVar(&o.Interval, "interval", 5*time.Second, "Interval to poll the new portions when following.")
+	cmd.Flags().StringVar(&o.Grep, "grep", "", "Only write the lines which match this regular expression.")
+	cmd.Flags().BoolVar(&o.InvertMatch, "invert-match", false, "Only write the lines which do not match --grep.")
+	cmd.Flags().BoolVar(&o.IgnoreCase, "ignore-case", false, "Ignore case when matching --grep.")
+	cmd.Flags().StringVar(&o.Region, "region", "", "Region of the DB instance. Default is the region in axolgo configuration.")
+	cmd.Flags().StringVar(&o.Profile, "profile", "", "Profile for the DB instance. Default is the profile in axolgo configuration.")
+
+	cmd.MarkFlagRequired("instance-id")
+	cmd.MarkFlagRequired("log-file")
+
+	return cmd
+}
+
+// Complete takes the command arguments and execute.
+func (o *DownloadDBLogFilePortionOptions) complete(ctx *context.Context, _ *cobra.Command, args []string) error {
+	pattern, err := o.pattern()
+	if err != nil {
+		return err
+	}
+	if o.Follow && o.OutputFile != "" {
+		return fmt.Errorf("--follow writes to the standard output and cannot be used with --output-file")
+	}
+	if o.Follow && o.Interval <= 0 {
+		return fmt.Errorf("invalid interval: %s", o.Interval)
+	}
+
+	cfg, err := util.LoadAWSConfig(context.TODO(), o.Region, o.Profile)
+	if err != nil {
+		return err
+	}
+	client := awsrds.NewFromConfig(cfg)
+
+	var w io.Writer = os.Stdout
+	if o.OutputFile != "" {
+		file, err := os.Create(o.OutputFile)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		w = file
+	}
+	filter := NewLineFilter(w, pattern, o.InvertMatch)
+	defer filter.Flush()
+
+	if !o.Follow {
+		_, err := DownloadLogFile(context.TODO(), client, o.InstanceID, o.LogFile, "0", filter)
+		return err
+	}
+
+	return o.follow(client, filter)
+}
+
+// follow streams the new portions of the log file until interrupted
+func (o *DownloadDBLogFilePortionOptions) follow(client DownloadDBLogFilePortionAPIClient, w io.Writer) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	data, marker, err := TailLogFile(ctx, client, o.InstanceID, o.LogFile, o.Tail)
+	if err != nil {
+		return err
+	}
+	if _, err := io.WriteString(w, data); err != nil {
+		return err
+	}
+
+	ticker := time.NewTicker(o.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		klog.V(4).InfoS("Poll log file", "logFile", o.LogFile, "marker", marker)
+		if marker, err = DownloadLogFile(ctx, client, o.InstanceID, o.LogFile, marker, w); err != nil {
+			if errors.Is(err, context.Canceled) {
+				return nil
+			}
+			return err
+		}
+	}
+}
+
+// pattern compiles the regular expression to filter the lines
+func (o *DownloadDBLogFilePortionOptions) pattern() (*regexp.Regexp, error) {
+	if o.Grep == "" {
+		return nil, nil
+	}
+	expr := o.Grep
+	if o.IgnoreCase {
+		expr = "(?i)" + expr
+	}
+	pattern, err := regexp.Compile(expr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pattern %s: %w", o.Grep, err)
+	}
+
+	return pattern, nil
+}
@@ -0,0 +1,171 @@
+/*
+Copyright © 2022 tchiunam
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package rds
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+
+	awsrds "github.com/aws/aws-sdk-go-v2/service/rds"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/tchiunam/axolgo-cli/pkg/types"
+	"github.com/tchiunam/axolgo-cli/pkg/util"
+	"k8s.io/klog/v2"
+)
+
+// ErrDriftDetected is returned when a parameter group has drifted
+// from the desired parameters
+var ErrDriftDetected = errors.New("drift detected")
+
+var (
+	driftLong = `Detect drift of DB Parameter Groups or DB Cluster Parameter Groups
+from the desired parameters. The desired parameters are read from
+parameter files in the same format as modifyDBParameterGroup, including
+overlays and templating.
+
+Every parameter in the parameter files is compared with the live value
+of each group. Parameters which are not in the parameter files are
+ignored. A summary is printed and the command exits with code 1 if any
+group has drifted.
+`
+	driftExample = `  # Detect drift of two DB Parameter Groups
+  axolgo aws rds drift -f desired.yaml -n group1 -n group2
+
+  # Detect drift of a DB Cluster Parameter Group with an overlay for production
+  axolgo aws rds drift -f base.yaml -f prod.yaml -n standard_group --cluster
+`
+)
+
+// DriftOptions defines flags and other configuration parameters for the `drift` command
+type DriftOptions struct {
+	Names          []string
+	ParameterFiles []string
+	Values         []string
+	Cluster        bool
+	Region         string
+	Profile        string
+}
+
+// NewCmdDrift creates the `drift` command
+func NewCmdDrift(ctx *context.Context) *cobra.Command {
+	o := DriftOptions{}
+
+	cmd := &cobra.Command{
+		Use:                   "drift -f FILENAME -n NAME [-n NAME] [-c] [--set KEY=VALUE] [--region] [--profile]",
+		DisableFlagsInUseLine: true,
+		Short:                 "Detect drift of Parameter Groups.",
+		Long:                  driftLong,
+		Example:               driftExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := o.complete(ctx, cmd, args); err != nil {
+				if errors.Is(err, ErrDriftDetected) {
+					klog.Error(err)
+					os.Exit(1)
+				}
+				panic(err)
+			}
+		},
+	}
+
+	cmd.Flags().StringArrayVarP(&o.Names, "name", "n", nil, "Parameter Group Name.")
+	cmd.Flags().StringArrayVarP(&o.ParameterFiles, "parameter-file", "f", nil, "The file that contains the desired parameters. Files are merged in the given order.")
+	cmd.Flags().StringArrayVar(&o.Values, "set", nil, "Template value in the format of KEY=VALUE.")
+	cmd.Flags().BoolVarP(&o.Cluster, "cluster", "c", false, "The groups are DB Cluster Parameter Groups.")
+	cmd.Flags().StringVar(&o.Region, "region", "", "Region of the groups. Default is the region in axolgo configuration.")
+	cmd.Flags().StringVar(&o.Profile, "profile", "", "Profile for the groups. Default is the profile in axolgo configuration.")
+
+	cmd.MarkFlagRequired("name")
+	cmd.MarkFlagRequired("parameter-file")
+
+	return cmd
+}
+
+// Complete takes the command arguments and execute.
+func (o *DriftOptions) complete(ctx *context.Context, _ *cobra.Command, args []string) error {
+	values, err := util.ParseKeyValues(o.Values)
+	if err != nil {
+		return err
+	}
+
+	axolgoConfig := viper.Get("axolgo-config").(types.AxolgoConfig)
+	staticParameters, dynamicParameters, err := util.ReadParameterFiles(
+		o.ParameterFiles,
+		util.ParameterFileData{Values: values, Config: axolgoConfig},
+	)
+	if err != nil {
+		return err
+	}
+	desired := util.ParameterValues(staticParameters, dynamicParameters)
+
+	cfg, err := util.LoadAWSConfig(context.TODO(), o.Region, o.Profile)
+	if err != nil {
+		return err
+	}
+	client := awsrds.NewFromConfig(cfg)
+
+	var driftRows, summaryRows [][]string
+	drifted, failed := 0, 0
+	for _, name := range o.Names {
+		live, err := DescribeParameterGroup(context.TODO(), client, name, o.Cluster)
+		if err != nil {
+			klog.Errorf("Failed to describe parameter group %s: %v", name, err)
+			summaryRows = append(summaryRows, []string{name, "ERROR", "-"})
+			failed++
+			continue
+		}
+
+		diffs := DriftParameters(desired, live)
+		for _, d := range diffs {
+			driftRows = append(driftRows, []string{name, d.Name, displayValue(d.Value), displayValue(d.Other)})
+		}
+		status := "OK"
+		if len(diffs) > 0 {
+			status = "DRIFTED"
+			drifted++
+		}
+		summaryRows = append(summaryRows, []string{name, status, strconv.Itoa(len(diffs))})
+	}
+
+	if len(driftRows) > 0 {
+		if err := util.PrintTable(os.Stdout, []string{"GROUP", "PARAMETER", "DESIRED", "ACTUAL"}, driftRows); err != nil {
+			return err
+		}
+		fmt.Println()
+	}
+	if err := util.PrintTable(os.Stdout, []string{"GROUP", "STATUS", "DRIFTED PARAMETERS"}, summaryRows); err != nil {
+		return err
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("failed to describe %d parameter group(s)", failed)
+	}
+	if drifted > 0 {
+		return fmt.Errorf("%w in %d of %d parameter group(s)", ErrDriftDetected, drifted, len(o.Names))
+	}
+
+	return nil
+}
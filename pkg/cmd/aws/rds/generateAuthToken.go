@@ -0,0 +1,101 @@
+/*
+Copyright © 2022 tchiunam
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package rds
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/tchiunam/axolgo-cli/pkg/util"
+)
+
+var (
+	generateAuthTokenLong = `Generate an IAM database authentication token to be used as the
+password of a database user. The token is signed locally with the
+region and credentials of the profile, and no request is sent to AWS.
+It is valid for 15 minutes.
+`
+	generateAuthTokenExample = `  # Generate a token for a database user
+  axolgo aws rds generateAuthToken --endpoint standard-instance.abcdefghijkl.ap-east-1.rds.amazonaws.com:5432 --user app_user
+
+  # Use the token as the password of psql
+  PGPASSWORD="$(axolgo aws rds generateAuthToken -e standard-instance.abcdefghijkl.ap-east-1.rds.amazonaws.com:5432 -u app_user)" psql "host=standard-instance.abcdefghijkl.ap-east-1.rds.amazonaws.com user=app_user sslmode=require"
+`
+)
+
+// GenerateAuthTokenOptions defines flags and other configuration parameters for the `generateAuthToken` command
+type GenerateAuthTokenOptions struct {
+	Endpoint string
+	User     string
+	Region   string
+	Profile  string
+}
+
+// NewCmdGenerateAuthToken creates the `generateAuthToken` command
+func NewCmdGenerateAuthToken(ctx *context.Context) *cobra.Command {
+	o := GenerateAuthTokenOptions{}
+
+	cmd := &cobra.Command{
+		Use:                   "generateAuthToken -e HOST:PORT -u USER [--region] [--profile]",
+		DisableFlagsInUseLine: true,
+		Short:                 "Generate an IAM database authentication token.",
+		Long:                  generateAuthTokenLong,
+		Example:               generateAuthTokenExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := o.complete(ctx, cmd, args); err != nil {
+				panic(err)
+			}
+		},
+	}
+
+	cmd.Flags().StringVarP(&o.Endpoint, "endpoint", "e", "", "Endpoint of the database in the format of HOST:PORT.")
+	cmd.Flags().StringVarP(&o.User, "user", "u", "", "Database user.")
+	cmd.Flags().StringVar(&o.Region, "region", "", "Region of the database. Default is the region in axolgo configuration.")
+	cmd.Flags().StringVar(&o.Profile, "profile", "", "Profile to sign the token. Default is the profile in axolgo configuration.")
+
+	cmd.MarkFlagRequired("endpoint")
+	cmd.MarkFlagRequired("user")
+
+	return cmd
+}
+
+// Complete takes the command arguments and execute.
+func (o *GenerateAuthTokenOptions) complete(ctx *context.Context, _ *cobra.Command, args []string) error {
+	host, port, err := ParseEndpoint(o.Endpoint, 0)
+	if err != nil {
+		return err
+	}
+
+	cfg, err := util.LoadAWSConfig(context.TODO(), o.Region, o.Profile)
+	if err != nil {
+		return err
+	}
+	token, err := BuildAuthToken(context.TODO(), cfg, host, port, o.User)
+	if err != nil {
+		return err
+	}
+	fmt.Println(token)
+
+	return nil
+}
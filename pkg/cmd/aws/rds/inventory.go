@@ -0,0 +1,111 @@
+/*
+Copyright © 2022 tchiunam
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package rds
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsrds "github.com/aws/aws-sdk-go-v2/service/rds"
+	awsrdstypes "github.com/aws/aws-sdk-go-v2/service/rds/types"
+)
+
+// DescribePendingMaintenanceActions returns the names of the pending
+// maintenance actions keyed by the ARN of the resource
+func DescribePendingMaintenanceActions(ctx context.Context, client *awsrds.Client) (map[string][]string, error) {
+	actions := make(map[string][]string)
+	p := awsrds.NewDescribePendingMaintenanceActionsPaginator(client, &awsrds.DescribePendingMaintenanceActionsInput{})
+	for p.HasMorePages() {
+		output, err := p.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range output.PendingMaintenanceActions {
+			arn := aws.ToString(r.ResourceIdentifier)
+			for _, a := range r.PendingMaintenanceActionDetails {
+				actions[arn] = append(actions[arn], aws.ToString(a.Action))
+			}
+		}
+	}
+
+	return actions, nil
+}
+
+// MatchTags checks if the tags contain all the wanted key and value pairs
+func MatchTags(tags []awsrdstypes.Tag, want map[string]string) bool {
+	have := make(map[string]string, len(tags))
+	for _, t := range tags {
+		have[aws.ToString(t.Key)] = aws.ToString(t.Value)
+	}
+	for k, v := range want {
+		if hv, ok := have[k]; !ok || hv != v {
+			return false
+		}
+	}
+
+	return true
+}
+
+// buildFilters creates the filters of a describe call from the
+// filter names and values. Filters without value are skipped.
+func buildFilters(filterNVs map[string][]string) []awsrdstypes.Filter {
+	names := make([]string, 0, len(filterNVs))
+	for k := range filterNVs {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+
+	var filters []awsrdstypes.Filter
+	for _, name := range names {
+		if values := filterNVs[name]; len(values) != 0 {
+			filters = append(filters, awsrdstypes.Filter{
+				Name:   aws.String(name),
+				Values: values,
+			})
+		}
+	}
+
+	return filters
+}
+
+// formatEngine formats the engine and its version
+func formatEngine(engine *string, version *string) string {
+	return strings.TrimSpace(aws.ToString(engine) + " " + aws.ToString(version))
+}
+
+// formatStorage formats the allocated storage and the storage type
+func formatStorage(allocated int32, storageType *string) string {
+	if allocated == 0 {
+		return displayValue(aws.ToString(storageType))
+	}
+
+	return strings.TrimSpace(fmt.Sprintf("%d GiB %s", allocated, aws.ToString(storageType)))
+}
+
+// formatList joins the values or returns a placeholder if there is none
+func formatList(values []string) string {
+	return displayValue(strings.Join(values, ","))
+}
@@ -0,0 +1,100 @@
+/*
+Copyright © 2022 tchiunam
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package rds
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsrdstypes "github.com/aws/aws-sdk-go-v2/service/rds/types"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestMatchTags tests the MatchTags function
+func TestMatchTags(t *testing.T) {
+	tags := []awsrdstypes.Tag{
+		{Key: aws.String("env"), Value: aws.String("prod")},
+		{Key: aws.String("team"), Value: aws.String("db")},
+	}
+
+	cases := map[string]struct {
+		want  map[string]string
+		match bool
+	}{
+		"no wanted tag": {
+			want:  map[string]string{},
+			match: true,
+		},
+		"all tags match": {
+			want:  map[string]string{"env": "prod", "team": "db"},
+			match: true,
+		},
+		"different value": {
+			want:  map[string]string{"env": "staging"},
+			match: false,
+		},
+		"missing tag": {
+			want:  map[string]string{"owner": "alice"},
+			match: false,
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, c.match, MatchTags(tags, c.want))
+		})
+	}
+}
+
+// TestBuildFilters tests the buildFilters function
+func TestBuildFilters(t *testing.T) {
+	filters := buildFilters(map[string][]string{
+		"engine":         {"postgres"},
+		"db-cluster-id":  {"cluster-1", "cluster-2"},
+		"db-instance-id": nil,
+	})
+
+	assert.Equal(t, []awsrdstypes.Filter{
+		{Name: aws.String("db-cluster-id"), Values: []string{"cluster-1", "cluster-2"}},
+		{Name: aws.String("engine"), Values: []string{"postgres"}},
+	}, filters)
+}
+
+// TestFormatStorage tests the formatStorage function
+func TestFormatStorage(t *testing.T) {
+	cases := map[string]struct {
+		allocated   int32
+		storageType *string
+		output      string
+	}{
+		"allocated storage": {allocated: 100, storageType: aws.String("gp3"), output: "100 GiB gp3"},
+		"storage type only": {storageType: aws.String("aurora"), output: "aurora"},
+		"no storage":        {output: "-"},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, c.output, formatStorage(c.allocated, c.storageType))
+		})
+	}
+}
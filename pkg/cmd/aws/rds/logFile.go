@@ -0,0 +1,202 @@
+/*
+Copyright © 2022 tchiunam
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package rds
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsrds "github.com/aws/aws-sdk-go-v2/service/rds"
+)
+
+// LogFileInfo is a log file of a DB instance
+type LogFileInfo struct {
+	Name        string
+	Size        int64
+	LastWritten time.Time
+}
+
+// DownloadDBLogFilePortionAPIClient is the client to download portions of a log file
+type DownloadDBLogFilePortionAPIClient interface {
+	DownloadDBLogFilePortion(context.Context, *awsrds.DownloadDBLogFilePortionInput, ...func(*awsrds.Options)) (*awsrds.DownloadDBLogFilePortionOutput, error)
+}
+
+// DescribeLogFiles returns the log files of a DB instance whose names
+// contain the given string and which are written since the given time
+func DescribeLogFiles(ctx context.Context, client *awsrds.Client, instance string, contains string, since time.Time) ([]LogFileInfo, error) {
+	input := &awsrds.DescribeDBLogFilesInput{
+		DBInstanceIdentifier: aws.String(instance),
+	}
+	if contains != "" {
+		input.FilenameContains = aws.String(contains)
+	}
+	if !since.IsZero() {
+		input.FileLastWritten = since.UnixMilli()
+	}
+
+	var files []LogFileInfo
+	p := awsrds.NewDescribeDBLogFilesPaginator(client, input)
+	for p.HasMorePages() {
+		output, err := p.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, f := range output.DescribeDBLogFiles {
+			files = append(files, LogFileInfo{
+				Name:        aws.ToString(f.LogFileName),
+				Size:        f.Size,
+				LastWritten: time.UnixMilli(f.LastWritten),
+			})
+		}
+	}
+
+	return files, nil
+}
+
+// DownloadLogFile downloads the portions of a log file from the marker
+// until no more data is pending, and writes them to the writer. The
+// whole log file is downloaded if the marker is "0". The marker to
+// continue with is returned.
+func DownloadLogFile(ctx context.Context, client DownloadDBLogFilePortionAPIClient, instance string, file string, marker string, w io.Writer) (string, error) {
+	for {
+		output, err := client.DownloadDBLogFilePortion(ctx, &awsrds.DownloadDBLogFilePortionInput{
+			DBInstanceIdentifier: aws.String(instance),
+			LogFileName:          aws.String(file),
+			Marker:               aws.String(marker),
+		})
+		if err != nil {
+			return marker, fmt.Errorf("failed to download log file %s: %w", file, err)
+		}
+		if _, err := io.WriteString(w, aws.ToString(output.LogFileData)); err != nil {
+			return marker, err
+		}
+		if output.Marker != nil {
+			marker = aws.ToString(output.Marker)
+		}
+		if !output.AdditionalDataPending {
+			return marker, nil
+		}
+	}
+}
+
+// TailLogFile returns the last lines of a log file and the marker to
+// continue with. Only the marker is returned if lines is 0.
+func TailLogFile(ctx context.Context, client DownloadDBLogFilePortionAPIClient, instance string, file string, lines int32) (string, string, error) {
+	// The marker of the end is returned with at least a line
+	input := &awsrds.DownloadDBLogFilePortionInput{
+		DBInstanceIdentifier: aws.String(instance),
+		LogFileName:          aws.String(file),
+		NumberOfLines:        1,
+	}
+	if lines > 0 {
+		input.NumberOfLines = lines
+	}
+	output, err := client.DownloadDBLogFilePortion(ctx, input)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to download log file %s: %w", file, err)
+	}
+
+	data := aws.ToString(output.LogFileData)
+	if lines == 0 {
+		data = ""
+	}
+
+	return data, aws.ToString(output.Marker), nil
+}
+
+// LineFilter writes only the lines which match a pattern. A line split
+// across two writes is held until it is complete.
+type LineFilter struct {
+	w       io.Writer
+	pattern *regexp.Regexp
+	invert  bool
+	pending string
+}
+
+// NewLineFilter creates a LineFilter. All lines are written if the
+// pattern is nil.
+func NewLineFilter(w io.Writer, pattern *regexp.Regexp, invert bool) *LineFilter {
+	return &LineFilter{w: w, pattern: pattern, invert: invert}
+}
+
+// Write writes the complete lines which match the pattern
+func (f *LineFilter) Write(p []byte) (int, error) {
+	data := f.pending + string(p)
+	last := strings.LastIndexByte(data, '\n')
+	f.pending = data[last+1:]
+	if last < 0 {
+		return len(p), nil
+	}
+
+	for _, line := range strings.SplitAfter(data[:last+1], "\n") {
+		if line == "" || !f.match(line) {
+			continue
+		}
+		if _, err := io.WriteString(f.w, line); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(p), nil
+}
+
+// Flush writes the pending line without a line break
+func (f *LineFilter) Flush() error {
+	line := f.pending
+	f.pending = ""
+	if line == "" || !f.match(line) {
+		return nil
+	}
+	_, err := io.WriteString(f.w, line+"\n")
+
+	return err
+}
+
+// match tells whether a line is to be written
+func (f *LineFilter) match(line string) bool {
+	if f.pattern == nil {
+		return true
+	}
+
+	return f.pattern.MatchString(strings.TrimSuffix(line, "\n")) != f.invert
+}
+
+// FormatBytes formats a size in bytes with a binary unit
+func FormatBytes(size int64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+	div, exp := int64(unit), 0
+	for n := size / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+
+	return fmt.Sprintf("%.1f %ciB", float64(size)/float64(div), "KMGTPE"[exp])
+}
@@ -0,0 +1,201 @@
+/*
+Copyright © 2022 tchiunam
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package rds
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsrds "github.com/aws/aws-sdk-go-v2/service/rds"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeLogFileClient returns the portions of a log file by marker
+type fakeLogFileClient struct {
+	portions map[string]*awsrds.DownloadDBLogFilePortionOutput
+	inputs   []*awsrds.DownloadDBLogFilePortionInput
+}
+
+func (c *fakeLogFileClient) DownloadDBLogFilePortion(_ context.Context, input *awsrds.DownloadDBLogFilePortionInput, _ ...func(*awsrds.Options)) (*awsrds.DownloadDBLogFilePortionOutput, error) {
+	c.inputs = append(c.inputs, input)
+	output, ok := c.portions[aws.ToString(input.Marker)]
+	if !ok {
+		return nil, fmt.Errorf("invalid marker: %s", aws.ToString(input.Marker))
+	}
+
+	return output, nil
+}
+
+// TestDownloadLogFile tests the DownloadLogFile function
+func TestDownloadLogFile(t *testing.T) {
+	client := &fakeLogFileClient{
+		portions: map[string]*awsrds.DownloadDBLogFilePortionOutput{
+			"0":    {LogFileData: aws.String("line 1\n"), Marker: aws.String("1:10"), AdditionalDataPending: true},
+			"1:10": {LogFileData: aws.String("line 2\n"), Marker: aws.String("1:20"), AdditionalDataPending: false},
+			"1:20": {LogFileData: aws.String(""), Marker: aws.String("1:20"), AdditionalDataPending: false},
+		},
+	}
+	cases := map[string]struct {
+		marker     string
+		data       string
+		nextMarker string
+		valid      bool
+	}{
+		"from the beginning": {
+			marker:     "0",
+			data:       "line 1\nline 2\n",
+			nextMarker: "1:20",
+			valid:      true,
+		},
+		"no new data": {
+			marker:     "1:20",
+			data:       "",
+			nextMarker: "1:20",
+			valid:      true,
+		},
+		"invalid marker": {
+			marker: "2:0",
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			var buf bytes.Buffer
+			marker, err := DownloadLogFile(context.TODO(), client, "standard-instance", "error/postgresql.log", c.marker, &buf)
+			if c.valid {
+				assert.NoError(t, err)
+				assert.Equal(t, c.data, buf.String())
+				assert.Equal(t, c.nextMarker, marker)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
+
+// TestTailLogFile tests the TailLogFile function
+func TestTailLogFile(t *testing.T) {
+	cases := map[string]struct {
+		lines         int32
+		data          string
+		numberOfLines int32
+	}{
+		"last lines": {
+			lines:         10,
+			data:          "line 9\nline 10\n",
+			numberOfLines: 10,
+		},
+		"marker only": {
+			lines:         0,
+			data:          "",
+			numberOfLines: 1,
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			client := &fakeLogFileClient{
+				portions: map[string]*awsrds.DownloadDBLogFilePortionOutput{
+					"": {LogFileData: aws.String("line 9\nline 10\n"), Marker: aws.String("1:100")},
+				},
+			}
+			data, marker, err := TailLogFile(context.TODO(), client, "standard-instance", "error/postgresql.log", c.lines)
+			assert.NoError(t, err)
+			assert.Equal(t, c.data, data)
+			assert.Equal(t, "1:100", marker)
+			assert.Equal(t, c.numberOfLines, client.inputs[0].NumberOfLines)
+		})
+	}
+}
+
+// TestLineFilter tests the LineFilter type
+func TestLineFilter(t *testing.T) {
+	cases := map[string]struct {
+		pattern *regexp.Regexp
+		invert  bool
+		writes  []string
+		output  string
+	}{
+		"no pattern": {
+			writes: []string{"a\nb\n"},
+			output: "a\nb\n",
+		},
+		"match": {
+			pattern: regexp.MustCompile(`duration: \d{4,}`),
+			writes:  []string{"duration: 12 ms\nduration: 1234 ms\n"},
+			output:  "duration: 1234 ms\n",
+		},
+		"invert match": {
+			pattern: regexp.MustCompile(`^LOG`),
+			invert:  true,
+			writes:  []string{"LOG: a\nERROR: b\n"},
+			output:  "ERROR: b\n",
+		},
+		"line across writes": {
+			pattern: regexp.MustCompile(`ERROR`),
+			writes:  []string{"LOG: a\nERR", "OR: b\nLOG: c"},
+			output:  "ERROR: b\n",
+		},
+		"pending line is flushed": {
+			writes: []string{"a\nb"},
+			output: "a\nb\n",
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			var buf bytes.Buffer
+			f := NewLineFilter(&buf, c.pattern, c.invert)
+			for _, w := range c.writes {
+				n, err := f.Write([]byte(w))
+				assert.NoError(t, err)
+				assert.Equal(t, len(w), n)
+			}
+			assert.NoError(t, f.Flush())
+			assert.Equal(t, c.output, buf.String())
+		})
+	}
+}
+
+// TestFormatBytes tests the FormatBytes function
+func TestFormatBytes(t *testing.T) {
+	cases := map[string]struct {
+		size   int64
+		result string
+	}{
+		"bytes":     {size: 512, result: "512 B"},
+		"kibibytes": {size: 1536, result: "1.5 KiB"},
+		"mebibytes": {size: 10 * 1024 * 1024, result: "10.0 MiB"},
+		"gibibytes": {size: 3 * 1024 * 1024 * 1024, result: "3.0 GiB"},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, c.result, FormatBytes(c.size))
+		})
+	}
+}
@@ -24,16 +24,13 @@ package rds
 
 import (
 	"context"
-	"fmt"
 
-	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 	"github.com/tchiunam/axolgo-cli/pkg/types"
+	"github.com/tchiunam/axolgo-cli/pkg/util"
 	"github.com/tchiunam/axolgo-cloud/aws/rds"
-	"github.com/tchiunam/axolgo-cloud/aws/util"
-	"github.com/tchiunam/axolgo-lib/io/ioutil"
-	axolgolibtypes "github.com/tchiunam/axolgo-lib/types"
+	axolgocloudutil "github.com/tchiunam/axolgo-cloud/aws/util"
 )
 
 var (
@@ -51,16 +48,35 @@ dynamic:
   param2: value2
   ...
 ==============================
+
+More than one parameter file can be given. They are merged in the
+given order so that a file, e.g. for an environment, overrides the
+values of a base file.
+
+A parameter file can contain Go template expressions. Values given
+with --set are available as .Values and the axolgo configuration as
+.Config. The function dbInstanceClassMemory returns the memory in
+bytes of a DB instance class. The functions add, sub, mul and div
+do integer arithmetic. Example:
+
+==============================
+static:
+  shared_buffers: {{ div (dbInstanceClassMemory .Values.instanceClass) 32768 }}
+==============================
 `
 	modifyDBClusterParameterGroupExample = `  # Modify by providing a parameter file
   axolgo aws rds modifyDBClusterParameterGroup -n standard_group -f parameters.yaml
+
+  # Modify by providing a base parameter file and an overlay for production
+  axolgo aws rds modifyDBClusterParameterGroup -n standard_group -f base.yaml -f prod.yaml --set instanceClass=db.r6g.xlarge
 `
 )
 
 // ModifyDBClusterParameterGroupOptions defines flags and other configuration parameters for the `modifyDBClusterParameterGroup` command
 type ModifyDBClusterParameterGroupOptions struct {
-	Name          string
-	ParameterFile string
+	Name           string
+	ParameterFiles []string
+	Values         []string
 }
 
 // NewCmdModifyDBClusterParameterGroup creates the `modifyDBClusterParameterGroup` command
@@ -68,7 +84,7 @@ func NewCmdModifyDBClusterParameterGroup(ctx *context.Context) *cobra.Command {
 	o := ModifyDBClusterParameterGroupOptions{}
 
 	cmd := &cobra.Command{
-		Use:                   "modifyDBClusterParameterGroup -n NAME -f FILENAME",
+		Use:                   "modifyDBClusterParameterGroup -n NAME -f FILENAME [-f FILENAME] [--set KEY=VALUE]",
 		DisableFlagsInUseLine: true,
 		Short:                 "Modify DB Cluster Parameter Group.",
 		Long:                  modifyDBClusterParameterGroupLong,
@@ -81,7 +97,8 @@ func NewCmdModifyDBClusterParameterGroup(ctx *context.Context) *cobra.Command {
 	}
 
 	cmd.Flags().StringVarP(&o.Name, "name", "n", "", "DB Cluster Group Name.")
-	cmd.Flags().StringVarP(&o.ParameterFile, "parameter-file", "f", "", "The file that contains parameters.")
+	cmd.Flags().StringArrayVarP(&o.ParameterFiles, "parameter-file", "f", nil, "The file that contains parameters. Files are merged in the given order.")
+	cmd.Flags().StringArrayVar(&o.Values, "set", nil, "Template value in the format of KEY=VALUE.")
 
 	cmd.MarkFlagRequired("name")
 	cmd.MarkFlagRequired("parameter-file")
@@ -92,28 +109,21 @@ func NewCmdModifyDBClusterParameterGroup(ctx *context.Context) *cobra.Command {
 
 // Complete takes the command arguments and execute
 func (o *ModifyDBClusterParameterGroupOptions) complete(ctx *context.Context, _ *cobra.Command, args []string) error {
-	yamlFile, err := ioutil.ReadYamlFile(o.ParameterFile)
+	values, err := util.ParseKeyValues(o.Values)
 	if err != nil {
 		return err
 	}
-	yamlParameters := yamlFile.(*viper.Viper)
-
-	parameters := [][]axolgolibtypes.Parameter{make([]axolgolibtypes.Parameter, 0), make([]axolgolibtypes.Parameter, 0)}
-	for i, t := range []string{"static", "dynamic"} {
-		if section := yamlParameters.Get(t); section != nil {
-			for k, v := range section.(map[string]interface{}) {
-				parameters[i] = append(
-					parameters[i],
-					axolgolibtypes.Parameter{
-						Name:  aws.String(k),
-						Value: aws.String(fmt.Sprintf("%v", v)),
-					})
-			}
-		}
-	}
 
 	axolgoConfig := viper.Get("axolgo-config").(types.AxolgoConfig)
-	_, err = rds.RunModifyDBClusterParameterGroup(o.Name, parameters[0], parameters[1], util.WithRegion(axolgoConfig.AWS.Region))
+	staticParameters, dynamicParameters, err := util.ReadParameterFiles(
+		o.ParameterFiles,
+		util.ParameterFileData{Values: values, Config: axolgoConfig},
+	)
+	if err != nil {
+		return err
+	}
+
+	_, err = rds.RunModifyDBClusterParameterGroup(o.Name, staticParameters, dynamicParameters, axolgocloudutil.WithRegion(axolgoConfig.AWS.Region))
 
 	return err
 }
@@ -0,0 +1,119 @@
+/*
+Copyright © 2022 tchiunam
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package rds
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsrds "github.com/aws/aws-sdk-go-v2/service/rds"
+	awsrdstypes "github.com/aws/aws-sdk-go-v2/service/rds/types"
+	"k8s.io/klog/v2"
+)
+
+// ParameterDiff is a parameter which has different values in two sources.
+// An empty value means the parameter has no value in that source.
+type ParameterDiff struct {
+	Name  string
+	Value string
+	Other string
+}
+
+// DescribeParameterGroup returns the values of the parameters in a DB
+// parameter group or a DB cluster parameter group. Parameters without
+// value are skipped. The names are in lower case.
+func DescribeParameterGroup(ctx context.Context, client *awsrds.Client, name string, cluster bool) (map[string]string, error) {
+	var parameters []awsrdstypes.Parameter
+	if cluster {
+		p := awsrds.NewDescribeDBClusterParametersPaginator(client, &awsrds.DescribeDBClusterParametersInput{
+			DBClusterParameterGroupName: aws.String(name),
+		})
+		for p.HasMorePages() {
+			output, err := p.NextPage(ctx)
+			if err != nil {
+				return nil, err
+			}
+			parameters = append(parameters, output.Parameters...)
+		}
+	} else {
+		p := awsrds.NewDescribeDBParametersPaginator(client, &awsrds.DescribeDBParametersInput{
+			DBParameterGroupName: aws.String(name),
+		})
+		for p.HasMorePages() {
+			output, err := p.NextPage(ctx)
+			if err != nil {
+				return nil, err
+			}
+			parameters = append(parameters, output.Parameters...)
+		}
+	}
+	klog.V(3).InfoS("Described parameter group", "name", name, "cluster", cluster, "len(parameters)", len(parameters))
+
+	values := make(map[string]string, len(parameters))
+	for _, p := range parameters {
+		if p.ParameterName != nil && p.ParameterValue != nil {
+			values[strings.ToLower(*p.ParameterName)] = *p.ParameterValue
+		}
+	}
+
+	return values, nil
+}
+
+// CompareParameters returns the parameters which have different values
+// in the two sets, sorted by name.
+func CompareParameters(values map[string]string, others map[string]string) []ParameterDiff {
+	names := make(map[string]struct{}, len(values)+len(others))
+	for k := range values {
+		names[k] = struct{}{}
+	}
+	for k := range others {
+		names[k] = struct{}{}
+	}
+
+	diffs := make([]ParameterDiff, 0)
+	for k := range names {
+		if v, o := values[k], others[k]; v != o {
+			diffs = append(diffs, ParameterDiff{Name: k, Value: v, Other: o})
+		}
+	}
+	sort.Slice(diffs, func(i, j int) bool { return diffs[i].Name < diffs[j].Name })
+
+	return diffs
+}
+
+// DriftParameters returns the desired parameters which have different
+// values in the live parameter group, sorted by name. Parameters which
+// are not desired are ignored.
+func DriftParameters(desired map[string]string, live map[string]string) []ParameterDiff {
+	diffs := make([]ParameterDiff, 0)
+	for k, v := range desired {
+		if l := live[k]; l != v {
+			diffs = append(diffs, ParameterDiff{Name: k, Value: v, Other: l})
+		}
+	}
+	sort.Slice(diffs, func(i, j int) bool { return diffs[i].Name < diffs[j].Name })
+
+	return diffs
+}
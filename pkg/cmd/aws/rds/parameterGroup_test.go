@@ -0,0 +1,88 @@
+/*
+Copyright © 2022 tchiunam
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package rds
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestCompareParameters tests the CompareParameters function
+func TestCompareParameters(t *testing.T) {
+	cases := map[string]struct {
+		values map[string]string
+		others map[string]string
+		diffs  []ParameterDiff
+	}{
+		"same values": {
+			values: map[string]string{"work_mem": "4096"},
+			others: map[string]string{"work_mem": "4096"},
+			diffs:  []ParameterDiff{},
+		},
+		"different values": {
+			values: map[string]string{"work_mem": "4096", "shared_buffers": "524288", "max_connections": "100"},
+			others: map[string]string{"work_mem": "8192", "max_connections": "100", "rds.force_ssl": "1"},
+			diffs: []ParameterDiff{
+				{Name: "rds.force_ssl", Value: "", Other: "1"},
+				{Name: "shared_buffers", Value: "524288", Other: ""},
+				{Name: "work_mem", Value: "4096", Other: "8192"},
+			},
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, c.diffs, CompareParameters(c.values, c.others))
+		})
+	}
+}
+
+// TestDriftParameters tests the DriftParameters function
+func TestDriftParameters(t *testing.T) {
+	cases := map[string]struct {
+		desired map[string]string
+		live    map[string]string
+		diffs   []ParameterDiff
+	}{
+		"no drift": {
+			desired: map[string]string{"work_mem": "4096"},
+			live:    map[string]string{"work_mem": "4096", "max_connections": "100"},
+			diffs:   []ParameterDiff{},
+		},
+		"drift": {
+			desired: map[string]string{"work_mem": "4096", "shared_buffers": "524288"},
+			live:    map[string]string{"work_mem": "8192", "max_connections": "100"},
+			diffs: []ParameterDiff{
+				{Name: "shared_buffers", Value: "524288", Other: ""},
+				{Name: "work_mem", Value: "4096", Other: "8192"},
+			},
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, c.diffs, DriftParameters(c.desired, c.live))
+		})
+	}
+}
@@ -0,0 +1,171 @@
+/*
+Copyright © 2022 tchiunam
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package rds
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsrds "github.com/aws/aws-sdk-go-v2/service/rds"
+	"github.com/spf13/cobra"
+	"github.com/tchiunam/axolgo-cli/pkg/util"
+	"k8s.io/klog/v2"
+)
+
+var (
+	pruneDBSnapshotsLong = `Prune the manual snapshots of a DB instance or a DB cluster with a
+retention policy. A snapshot is retained if it is one of the newest
+--keep snapshots or if it is younger than --max-age. Snapshots which
+are not available are always retained. Automated snapshots are managed
+by the backup retention period and are never pruned.
+
+The plan is printed without deleting anything unless --apply is given.
+`
+	pruneDBSnapshotsExample = `  # Show which snapshots would be removed to keep the newest 7
+  axolgo aws rds pruneDBSnapshots --id standard-instance --keep 7
+
+  # Remove the cluster snapshots older than 30 days but keep at least 3
+  axolgo aws rds pruneDBSnapshots --id standard-cluster --cluster --keep 3 --max-age 30d --apply
+`
+)
+
+// PruneDBSnapshotsOptions defines flags and other configuration parameters for the `pruneDBSnapshots` command
+type PruneDBSnapshotsOptions struct {
+	ID      string
+	Cluster bool
+	Keep    int
+	MaxAge  string
+	Prefix  string
+	Apply   bool
+	Region  string
+	Profile string
+}
+
+// NewCmdPruneDBSnapshots creates the `pruneDBSnapshots` command
+func NewCmdPruneDBSnapshots(ctx *context.Context) *cobra.Command {
+	o := PruneDBSnapshotsOptions{}
+
+	cmd := &cobra.Command{
+		Use:                   "pruneDBSnapshots -i ID [--cluster] [--keep] [--max-age] [--prefix] [--apply] [--region] [--profile]",
+		DisableFlagsInUseLine: true,
+		Short:                 "Prune DB snapshots with a retention policy.",
+		Long:                  pruneDBSnapshotsLong,
+		Example:               pruneDBSnapshotsExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := o.complete(ctx, cmd, args); err != nil {
+				panic(err)
+			}
+		},
+	}
+
+	cmd.Flags().StringVarP(&o.ID, "id", "i", "", "Identifier of the DB instance or DB cluster.")
+	cmd.Flags().BoolVar(&o.Cluster, "cluster", false, "Prune DB cluster snapshots.")
+	cmd.Flags().IntVar(&o.Keep, "keep", 0, "Number of the newest snapshots to retain.")
+	cmd.Flags().StringVar(&o.MaxAge, "max-age", "", "Retain the snapshots younger than this age, e.g. 30d or 12h.")
+	cmd.Flags().StringVar(&o.Prefix, "prefix", "", "Only prune the snapshots with this identifier prefix.")
+	cmd.Flags().BoolVar(&o.Apply, "apply", false, "Delete the snapshots. Default is to print the plan only.")
+	cmd.Flags().StringVar(&o.Region, "region", "", "Region of the snapshots. Default is the region in axolgo configuration.")
+	cmd.Flags().StringVar(&o.Profile, "profile", "", "Profile for the snapshots. Default is the profile in axolgo configuration.")
+
+	cmd.MarkFlagRequired("id")
+
+	return cmd
+}
+
+// Complete takes the command arguments and execute.
+func (o *PruneDBSnapshotsOptions) complete(ctx *context.Context, _ *cobra.Command, args []string) error {
+	maxAge, err := util.ParseAge(o.MaxAge)
+	if err != nil {
+		return err
+	}
+	if o.Keep < 0 {
+		return fmt.Errorf("invalid number of snapshots to keep: %d", o.Keep)
+	}
+	if o.Keep == 0 && maxAge == 0 {
+		return fmt.Errorf("a retention policy of --keep or --max-age is required")
+	}
+
+	cfg, err := util.LoadAWSConfig(context.TODO(), o.Region, o.Profile)
+	if err != nil {
+		return err
+	}
+	client := awsrds.NewFromConfig(cfg)
+
+	snapshots, err := DescribeSnapshots(context.TODO(), client, o.ID, o.Cluster, "manual")
+	if err != nil {
+		return err
+	}
+	candidates := make([]SnapshotInfo, 0, len(snapshots))
+	for _, s := range snapshots {
+		if strings.HasPrefix(s.ID, o.Prefix) {
+			candidates = append(candidates, s)
+		}
+	}
+
+	now := time.Now()
+	retain, remove := PlanSnapshotPrune(candidates, o.Keep, maxAge, now)
+	if err := printPrunePlan(retain, remove, now); err != nil {
+		return err
+	}
+
+	if !o.Apply {
+		klog.Infof("Dry run: %d snapshot(s) would be removed. Use --apply to delete them.", len(remove))
+		return nil
+	}
+
+	for _, s := range remove {
+		if o.Cluster {
+			_, err = client.DeleteDBClusterSnapshot(context.TODO(), &awsrds.DeleteDBClusterSnapshotInput{
+				DBClusterSnapshotIdentifier: aws.String(s.ID),
+			})
+		} else {
+			_, err = client.DeleteDBSnapshot(context.TODO(), &awsrds.DeleteDBSnapshotInput{
+				DBSnapshotIdentifier: aws.String(s.ID),
+			})
+		}
+		if err != nil {
+			return fmt.Errorf("failed to delete snapshot %s: %w", s.ID, err)
+		}
+		klog.Infof("Deleted snapshot %s", s.ID)
+	}
+
+	return nil
+}
+
+// printPrunePlan prints the snapshots with the action to take
+func printPrunePlan(retain []SnapshotInfo, remove []SnapshotInfo, now time.Time) error {
+	header := append([]string{"ACTION"}, snapshotHeader...)
+	rows := make([][]string, 0, len(retain)+len(remove))
+	for _, row := range snapshotRows(remove, now) {
+		rows = append(rows, append([]string{"remove"}, row...))
+	}
+	for _, row := range snapshotRows(retain, now) {
+		rows = append(rows, append([]string{"retain"}, row...))
+	}
+
+	return util.PrintTable(os.Stdout, header, rows)
+}
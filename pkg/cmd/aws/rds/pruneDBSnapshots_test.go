@@ -0,0 +1,84 @@
+/*
+Copyright © 2022 tchiunam
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package rds
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestNewCmdPruneDBSnapshots tests the NewCmdPruneDBSnapshots function
+// to make sure it returns a valid command.
+func TestNewCmdPruneDBSnapshots(t *testing.T) {
+	cases := map[string]struct {
+		use      string
+		short    string
+		hasFlags bool
+	}{
+		"valid command": {
+			use:      "pruneDBSnapshots -i ID [--cluster] [--keep] [--max-age] [--prefix] [--apply] [--region] [--profile]",
+			short:    "Prune DB snapshots with a retention policy.",
+			hasFlags: true,
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			cmd := NewCmdPruneDBSnapshots(nil)
+			assert.Equal(t, c.use, cmd.Use)
+			assert.Equal(t, c.short, cmd.Short)
+			assert.Equal(t, c.hasFlags, cmd.Flags().HasFlags())
+		})
+	}
+}
+
+// TestNewCmdPruneDBSnapshotsInvalid calls the NewCmdPruneDBSnapshots function
+// with invalid input and makes sure it returns an error.
+func TestNewCmdPruneDBSnapshotsInvalid(t *testing.T) {
+	cases := map[string]struct {
+		args []string
+	}{
+		"no retention policy": {
+			args: []string{"--id", "standard-instance"},
+		},
+		"invalid keep": {
+			args: []string{"--id", "standard-instance", "--keep", "-1"},
+		},
+		"invalid max age": {
+			args: []string{"--id", "standard-instance", "--max-age", "30days"},
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			oldArgs := os.Args
+			defer func() { os.Args = oldArgs }()
+			os.Args = append([]string{"cmd"}, c.args...)
+
+			cmd := NewCmdPruneDBSnapshots(nil)
+			assert.Panics(t, func() { cmd.Execute() })
+		})
+	}
+}
@@ -44,6 +44,20 @@ func NewRdsCmd(ctx *context.Context) *cobra.Command {
 	cmd.AddCommand(
 		NewCmdModifyDBParameterGroup(ctx),
 		NewCmdModifyDBClusterParameterGroup(ctx),
+		NewCmdCompareParameterGroups(ctx),
+		NewCmdDrift(ctx),
+		NewCmdApplyPendingReboots(ctx),
+		NewCmdDescribeDBInstances(ctx),
+		NewCmdDescribeDBClusters(ctx),
+		NewCmdCreateDBSnapshot(ctx),
+		NewCmdCreateDBClusterSnapshot(ctx),
+		NewCmdDescribeDBSnapshots(ctx),
+		NewCmdCopyDBSnapshot(ctx),
+		NewCmdPruneDBSnapshots(ctx),
+		NewCmdGenerateAuthToken(ctx),
+		NewCmdConnect(ctx),
+		NewCmdDescribeDBLogFiles(ctx),
+		NewCmdDownloadDBLogFilePortion(ctx),
 	)
 
 	return cmd
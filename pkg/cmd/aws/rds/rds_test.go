@@ -40,7 +40,7 @@ func TestNewRdsCmd(t *testing.T) {
 			use:      "rds",
 			short:    "A set of RDS commands.",
 			long:     "A set of RDS commands.",
-			commands: 2,
+			commands: 16,
 		},
 	}
 
@@ -0,0 +1,242 @@
+/*
+Copyright © 2022 tchiunam
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package rds
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsrds "github.com/aws/aws-sdk-go-v2/service/rds"
+	awsrdstypes "github.com/aws/aws-sdk-go-v2/service/rds/types"
+	"github.com/tchiunam/axolgo-cli/pkg/util"
+)
+
+// The default template of a snapshot identifier
+const defaultSnapshotNameTemplate = `{{ .ID }}-{{ .Time.Format "20060102-150405" }}`
+
+// A valid snapshot identifier starts with a letter and contains
+// letters, digits and hyphens only
+var snapshotIdentifierRegexp = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9-]{0,254}$`)
+
+// SnapshotInfo is a DB snapshot or a DB cluster snapshot
+type SnapshotInfo struct {
+	ID        string
+	ARN       string
+	Source    string
+	Type      string
+	Status    string
+	Created   time.Time
+	SizeGiB   int32
+	Encrypted bool
+}
+
+// SnapshotNameData is the data available to a snapshot name template
+type SnapshotNameData struct {
+	// Identifier of the DB instance or DB cluster
+	ID string
+	// Creation time in UTC
+	Time time.Time
+}
+
+// RenderSnapshotName renders a snapshot name template and validates
+// the result as a snapshot identifier
+func RenderSnapshotName(nameTemplate string, data SnapshotNameData) (string, error) {
+	tmpl, err := template.New("snapshot").Option("missingkey=error").Parse(nameTemplate)
+	if err != nil {
+		return "", fmt.Errorf("invalid snapshot name template: %w", err)
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("failed to render snapshot name: %w", err)
+	}
+
+	name := buf.String()
+	if !snapshotIdentifierRegexp.MatchString(name) || strings.Contains(name, "--") || strings.HasSuffix(name, "-") {
+		return "", fmt.Errorf("invalid snapshot identifier: %s", name)
+	}
+
+	return name, nil
+}
+
+// DescribeSnapshots returns the DB snapshots of a DB instance or the DB
+// cluster snapshots of a DB cluster, sorted by creation time with the
+// newest first. All snapshots are returned if the source is empty.
+func DescribeSnapshots(ctx context.Context, client *awsrds.Client, source string, cluster bool, snapshotType string) ([]SnapshotInfo, error) {
+	var snapshots []SnapshotInfo
+	if cluster {
+		input := &awsrds.DescribeDBClusterSnapshotsInput{}
+		if source != "" {
+			input.DBClusterIdentifier = aws.String(source)
+		}
+		if snapshotType != "" {
+			input.SnapshotType = aws.String(snapshotType)
+		}
+		p := awsrds.NewDescribeDBClusterSnapshotsPaginator(client, input)
+		for p.HasMorePages() {
+			output, err := p.NextPage(ctx)
+			if err != nil {
+				return nil, err
+			}
+			for _, s := range output.DBClusterSnapshots {
+				snapshots = append(snapshots, clusterSnapshotInfo(s))
+			}
+		}
+	} else {
+		input := &awsrds.DescribeDBSnapshotsInput{}
+		if source != "" {
+			input.DBInstanceIdentifier = aws.String(source)
+		}
+		if snapshotType != "" {
+			input.SnapshotType = aws.String(snapshotType)
+		}
+		p := awsrds.NewDescribeDBSnapshotsPaginator(client, input)
+		for p.HasMorePages() {
+			output, err := p.NextPage(ctx)
+			if err != nil {
+				return nil, err
+			}
+			for _, s := range output.DBSnapshots {
+				snapshots = append(snapshots, snapshotInfo(s))
+			}
+		}
+	}
+	sortSnapshots(snapshots)
+
+	return snapshots, nil
+}
+
+// snapshotInfo converts a DB snapshot
+func snapshotInfo(s awsrdstypes.DBSnapshot) SnapshotInfo {
+	return SnapshotInfo{
+		ID:        aws.ToString(s.DBSnapshotIdentifier),
+		ARN:       aws.ToString(s.DBSnapshotArn),
+		Source:    aws.ToString(s.DBInstanceIdentifier),
+		Type:      aws.ToString(s.SnapshotType),
+		Status:    aws.ToString(s.Status),
+		Created:   aws.ToTime(s.SnapshotCreateTime),
+		SizeGiB:   s.AllocatedStorage,
+		Encrypted: s.Encrypted,
+	}
+}
+
+// clusterSnapshotInfo converts a DB cluster snapshot
+func clusterSnapshotInfo(s awsrdstypes.DBClusterSnapshot) SnapshotInfo {
+	return SnapshotInfo{
+		ID:        aws.ToString(s.DBClusterSnapshotIdentifier),
+		ARN:       aws.ToString(s.DBClusterSnapshotArn),
+		Source:    aws.ToString(s.DBClusterIdentifier),
+		Type:      aws.ToString(s.SnapshotType),
+		Status:    aws.ToString(s.Status),
+		Created:   aws.ToTime(s.SnapshotCreateTime),
+		SizeGiB:   s.AllocatedStorage,
+		Encrypted: s.StorageEncrypted,
+	}
+}
+
+// sortSnapshots sorts the snapshots with the newest first
+func sortSnapshots(snapshots []SnapshotInfo) {
+	sort.SliceStable(snapshots, func(i, j int) bool {
+		return snapshots[i].Created.After(snapshots[j].Created)
+	})
+}
+
+// PlanSnapshotPrune decides which snapshots to remove under a retention
+// policy. A snapshot is retained if it is one of the newest keep
+// snapshots or if it is younger than maxAge. A policy of zero is not
+// applied. Snapshots which are not available are always retained.
+func PlanSnapshotPrune(snapshots []SnapshotInfo, keep int, maxAge time.Duration, now time.Time) ([]SnapshotInfo, []SnapshotInfo) {
+	sorted := make([]SnapshotInfo, len(snapshots))
+	copy(sorted, snapshots)
+	sortSnapshots(sorted)
+
+	retain := make([]SnapshotInfo, 0)
+	remove := make([]SnapshotInfo, 0)
+	available := 0
+	for _, s := range sorted {
+		if s.Status != "available" {
+			retain = append(retain, s)
+			continue
+		}
+		available++
+		withinCount := keep > 0 && available <= keep
+		withinAge := maxAge > 0 && now.Sub(s.Created) < maxAge
+		if withinCount || withinAge || (keep == 0 && maxAge == 0) {
+			retain = append(retain, s)
+		} else {
+			remove = append(remove, s)
+		}
+	}
+
+	return retain, remove
+}
+
+// buildTags converts key and value pairs to tags, sorted by key
+func buildTags(pairs map[string]string) []awsrdstypes.Tag {
+	keys := make([]string, 0, len(pairs))
+	for k := range pairs {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	tags := make([]awsrdstypes.Tag, 0, len(keys))
+	for _, k := range keys {
+		tags = append(tags, awsrdstypes.Tag{Key: aws.String(k), Value: aws.String(pairs[k])})
+	}
+
+	return tags
+}
+
+// snapshotRows formats the columns of the snapshots
+func snapshotRows(snapshots []SnapshotInfo, now time.Time) [][]string {
+	rows := make([][]string, 0, len(snapshots))
+	for _, s := range snapshots {
+		created, age := "-", "-"
+		if !s.Created.IsZero() {
+			created = s.Created.UTC().Format(time.RFC3339)
+			age = util.FormatAge(now.Sub(s.Created))
+		}
+		rows = append(rows, []string{
+			s.ID,
+			s.Source,
+			s.Type,
+			s.Status,
+			created,
+			age,
+			fmt.Sprintf("%d GiB", s.SizeGiB),
+			strconv.FormatBool(s.Encrypted),
+		})
+	}
+
+	return rows
+}
+
+// The header of the snapshot rows
+var snapshotHeader = []string{"IDENTIFIER", "SOURCE", "TYPE", "STATUS", "CREATED", "AGE", "SIZE", "ENCRYPTED"}
@@ -0,0 +1,135 @@
+/*
+Copyright © 2022 tchiunam
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package rds
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestRenderSnapshotName tests the RenderSnapshotName function
+func TestRenderSnapshotName(t *testing.T) {
+	data := SnapshotNameData{
+		ID:   "standard-instance",
+		Time: time.Date(2022, 12, 25, 5, 0, 0, 0, time.UTC),
+	}
+	cases := map[string]struct {
+		template string
+		name     string
+		valid    bool
+	}{
+		"default template": {
+			template: defaultSnapshotNameTemplate,
+			name:     "standard-instance-20221225-050000",
+			valid:    true,
+		},
+		"custom template": {
+			template: `{{ .ID }}-before-upgrade-{{ .Time.Format "20060102" }}`,
+			name:     "standard-instance-before-upgrade-20221225",
+			valid:    true,
+		},
+		"unknown field": {
+			template: "{{ .Name }}",
+		},
+		"invalid character": {
+			template: "{{ .ID }}_manual",
+		},
+		"consecutive hyphens": {
+			template: "{{ .ID }}--manual",
+		},
+		"trailing hyphen": {
+			template: "{{ .ID }}-",
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			result, err := RenderSnapshotName(c.template, data)
+			if c.valid {
+				assert.NoError(t, err)
+				assert.Equal(t, c.name, result)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
+
+// TestPlanSnapshotPrune tests the PlanSnapshotPrune function
+func TestPlanSnapshotPrune(t *testing.T) {
+	now := time.Date(2022, 12, 31, 0, 0, 0, 0, time.UTC)
+	snapshots := []SnapshotInfo{
+		{ID: "snap-3", Status: "available", Created: now.Add(-3 * 24 * time.Hour)},
+		{ID: "snap-1", Status: "available", Created: now.Add(-1 * 24 * time.Hour)},
+		{ID: "snap-10", Status: "available", Created: now.Add(-10 * 24 * time.Hour)},
+		{ID: "snap-5", Status: "creating", Created: now.Add(-5 * 24 * time.Hour)},
+		{ID: "snap-7", Status: "available", Created: now.Add(-7 * 24 * time.Hour)},
+	}
+	cases := map[string]struct {
+		keep   int
+		maxAge time.Duration
+		retain []string
+		remove []string
+	}{
+		"keep count": {
+			keep:   2,
+			retain: []string{"snap-1", "snap-3", "snap-5"},
+			remove: []string{"snap-7", "snap-10"},
+		},
+		"max age": {
+			maxAge: 4 * 24 * time.Hour,
+			retain: []string{"snap-1", "snap-3", "snap-5"},
+			remove: []string{"snap-7", "snap-10"},
+		},
+		"keep count or max age": {
+			keep:   1,
+			maxAge: 8 * 24 * time.Hour,
+			retain: []string{"snap-1", "snap-3", "snap-5", "snap-7"},
+			remove: []string{"snap-10"},
+		},
+		"no policy": {
+			retain: []string{"snap-1", "snap-3", "snap-5", "snap-7", "snap-10"},
+			remove: []string{},
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			retain, remove := PlanSnapshotPrune(snapshots, c.keep, c.maxAge, now)
+			assert.Equal(t, c.retain, snapshotIDs(retain))
+			assert.Equal(t, c.remove, snapshotIDs(remove))
+		})
+	}
+}
+
+// snapshotIDs returns the identifiers of the snapshots
+func snapshotIDs(snapshots []SnapshotInfo) []string {
+	ids := make([]string, 0, len(snapshots))
+	for _, s := range snapshots {
+		ids = append(ids, s.ID)
+	}
+
+	return ids
+}
@@ -0,0 +1,60 @@
+/*
+Copyright © 2022 tchiunam
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+)
+
+// _commands returns a command and all of its subcommands
+func _commands(c *cobra.Command) []*cobra.Command {
+	commands := []*cobra.Command{c}
+	for _, sub := range c.Commands() {
+		commands = append(commands, _commands(sub)...)
+	}
+	return commands
+}
+
+// TestHelp runs every command with --help under the root command to
+// make sure that their flags do not collide with the persistent flags,
+// e.g. -v of klog.
+func TestHelp(t *testing.T) {
+	for _, c := range _commands(rootCmd) {
+		args := append(strings.Fields(c.CommandPath())[1:], "--help")
+		t.Run(strings.Join(args, " "), func(t *testing.T) {
+			var buf bytes.Buffer
+			rootCmd.SetOut(&buf)
+			rootCmd.SetArgs(args)
+			defer rootCmd.SetArgs(nil)
+			assert.NotPanics(t, func() {
+				assert.NoError(t, rootCmd.Execute())
+			})
+			assert.Contains(t, buf.String(), "Usage:")
+		})
+	}
+}
@@ -47,6 +47,7 @@ func NewCryptographyCmd(ctx *context.Context) *cobra.Command {
 		NewCmdDecrypt(ctx),
 		NewCmdDecryptFile(ctx),
 		NewCmdGenPassphrase(ctx),
+		NewCmdGenKeyPair(ctx),
 	)
 
 	return cmd
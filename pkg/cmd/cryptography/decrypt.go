@@ -24,32 +24,43 @@ package cryptography
 
 import (
 	"bufio"
+	"bytes"
 	"context"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"os"
 	"strings"
 	"syscall"
 
+	"filippo.io/age"
 	"golang.org/x/term"
 	"k8s.io/klog/v2"
 
 	"github.com/spf13/cobra"
-	"github.com/tchiunam/axolgo-lib/cryptography"
 )
 
 var (
-	decryptLong = "Decrypt the provided string with a key."
+	decryptLong = `Decrypt the provided string with a key.
+
+The KDF and its costs are read from the header of the message. A
+message encrypted by a former version without a header is decrypted
+as well. A message encrypted to recipients is decrypted with the
+private key of any one of them.`
 
 	decryptExample = `  # Decrypt a message with a key file.
   axolgo crytography decrypt --key-file secret.key --message 382b7f6eff497363265885f017286cd7ae7a417526419e8779abe535059bb6fd8cf58f11ef7214
+
+  # Decrypt a message encrypted to recipients with a private key.
+  axolgo cryptography decrypt --identity private.key --message 6167652d656e6372797074696f6e2e6f72672f76310a...
 `
 )
 
 // DecryptOptions defines flags and other configuration parameters for the `decrypt` command
 type DecryptOptions struct {
-	KeyFile string
-	Message string
+	KeyFile      string
+	IdentityFile string
+	Message      string
 }
 
 // NewCmdDecrypt creates the `decrypt` command
@@ -57,7 +68,7 @@ func NewCmdDecrypt(ctx *context.Context) *cobra.Command {
 	o := DecryptOptions{}
 
 	cmd := &cobra.Command{
-		Use:                   "decrypt [-k] [-m]",
+		Use:                   "decrypt [-k] [-i] [-m]",
 		DisableFlagsInUseLine: true,
 		Short:                 "Decrypt a message.",
 		Long:                  decryptLong,
@@ -70,6 +81,7 @@ func NewCmdDecrypt(ctx *context.Context) *cobra.Command {
 	}
 
 	cmd.Flags().StringVarP(&o.KeyFile, "key-file", "k", "", "Key file.")
+	cmd.Flags().StringVarP(&o.IdentityFile, "identity", "i", "", "File of the private key to decrypt a message encrypted to recipients.")
 	cmd.Flags().StringVarP(&o.Message, "message", "m", "", "Message to be decrypted.")
 
 	return cmd
@@ -78,9 +90,18 @@ func NewCmdDecrypt(ctx *context.Context) *cobra.Command {
 
 // Complete takes the command arguments and execute.
 func (o *DecryptOptions) complete(_ *context.Context, _ *cobra.Command, args []string) error {
+	var identities []age.Identity
 	var passphrase []byte
 	var err error
-	if o.KeyFile == "" {
+	if o.IdentityFile != "" {
+		if o.KeyFile != "" {
+			return errors.New("--key-file cannot be used with --identity")
+		}
+		if identities, err = readIdentities(o.IdentityFile); err != nil {
+			klog.Errorf("Failed to read identity file: %s", o.IdentityFile)
+			return err
+		}
+	} else if o.KeyFile == "" {
 		fmt.Print("Enter passphrase: ")
 		if passphrase, err = term.ReadPassword(int(syscall.Stdin)); err != nil {
 			klog.Errorf("Failed to read passphrase from stdin: %v", err)
@@ -105,8 +126,14 @@ func (o *DecryptOptions) complete(_ *context.Context, _ *cobra.Command, args []s
 	}
 
 	if message, err := hex.DecodeString(o.Message); err == nil {
-		if data, err := cryptography.Decrypt([]byte(message), string(passphrase)); err == nil {
-			fmt.Println(string(data))
+		var data bytes.Buffer
+		if identities != nil {
+			err = DecryptWithIdentities(&data, bytes.NewReader(message), identities)
+		} else {
+			err = DecryptStream(&data, bytes.NewReader(message), passphrase)
+		}
+		if err == nil {
+			fmt.Println(data.String())
 		} else {
 			klog.Errorf("Failed to decrypt message: %s", o.Message)
 			return err
@@ -24,29 +24,44 @@ package cryptography
 
 import (
 	"context"
-	"fmt"
-	"os"
-	"syscall"
-
-	"golang.org/x/term"
-	"k8s.io/klog/v2"
+	"errors"
+	"io"
 
+	"filippo.io/age"
 	"github.com/spf13/cobra"
-	"github.com/tchiunam/axolgo-lib/cryptography"
 	"github.com/tchiunam/axolgo-lib/util"
 )
 
 var (
-	decryptFileLong = "Decrypt the provided file with a key."
+	decryptFileLong = `Decrypt the provided file with a key.
+
+A file which is encrypted as a stream is decrypted in constant memory
+with the KDF and the costs recorded in its header, and a file of the
+former format is detected and decrypted as a whole.
+Give - as the file to read from stdin and as the output file to write
+to stdout. A key file is required to read from stdin. The output file
+is written only if the decryption succeeds, while the data written to
+stdout before an error must be discarded.
+
+A file encrypted to recipients, by encryptFile or age, is decrypted
+with the private key of any one of them, which needs no key file to
+read from stdin.`
 
 	decryptFileExample = `  # Decrypt the file 'example.txt with a key file.
   axolgo crytography decryptFile --key-file secret.key --file example.txt
+
+  # Restore a database dump in a pipeline
+  axolgo cryptography decryptFile --key-file secret.key -f dump.enc -o - | psql mydb
+
+  # Decrypt a file encrypted to recipients with a private key
+  axolgo cryptography decryptFile --identity private.key --file example-encrypted.txt
 `
 )
 
 // DecryptFileOptions defines flags and other configuration parameters for the `decryptFile` command
 type DecryptFileOptions struct {
 	KeyFile        string
+	IdentityFile   string
 	FilePath       string
 	OutputFilePath string
 }
@@ -56,7 +71,7 @@ func NewCmdDecryptFile(ctx *context.Context) *cobra.Command {
 	o := DecryptFileOptions{}
 
 	cmd := &cobra.Command{
-		Use:                   "decryptFile [-k] -f FILENAME -o OUTPUT_FILENAME",
+		Use:                   "decryptFile [-k] [-i] -f FILENAME -o OUTPUT_FILENAME",
 		DisableFlagsInUseLine: true,
 		Short:                 "Decrypt a file.",
 		Long:                  decryptFileLong,
@@ -69,8 +84,9 @@ func NewCmdDecryptFile(ctx *context.Context) *cobra.Command {
 	}
 
 	cmd.Flags().StringVarP(&o.KeyFile, "key-file", "k", "", "Key file.")
-	cmd.Flags().StringVarP(&o.FilePath, "file", "f", "", "File to be decrypted.")
-	cmd.Flags().StringVarP(&o.OutputFilePath, "output-file", "o", "", "Output file.")
+	cmd.Flags().StringVarP(&o.IdentityFile, "identity", "i", "", "File of the private key to decrypt a file encrypted to recipients.")
+	cmd.Flags().StringVarP(&o.FilePath, "file", "f", "", "File to be decrypted. - is stdin.")
+	cmd.Flags().StringVarP(&o.OutputFilePath, "output-file", "o", "", "Output file. - is stdout. Default is the file name with suffix -decrypted, or stdout for stdin.")
 
 	cmd.MarkFlagRequired("file")
 
@@ -79,34 +95,38 @@ func NewCmdDecryptFile(ctx *context.Context) *cobra.Command {
 
 // Complete takes the command arguments and execute.
 func (o *DecryptFileOptions) complete(_ *context.Context, _ *cobra.Command, args []string) error {
+	var identities []age.Identity
 	var passphrase []byte
 	var err error
-	if o.KeyFile == "" {
-		fmt.Print("Enter passphrase: ")
-		if passphrase, err = term.ReadPassword(int(syscall.Stdin)); err != nil {
-			klog.Errorf("Failed to read passphrase from stdin: %v", err)
-			return err
+	if o.IdentityFile != "" {
+		if o.KeyFile != "" {
+			return errors.New("--key-file cannot be used with --identity")
 		}
-		fmt.Println()
-	} else {
-		passphrase, err = os.ReadFile(o.KeyFile)
-		if err != nil {
-			klog.Errorf("Failed to read key file: %s", o.KeyFile)
+		if identities, err = readIdentities(o.IdentityFile); err != nil {
 			return err
 		}
+	} else if passphrase, err = readPassphrase(o.KeyFile, o.FilePath == stdio); err != nil {
+		return err
 	}
 
-	var outputFilePath string
-	if o.OutputFilePath == "" {
-		outputFilePath = util.AddSuffixToFileName(o.FilePath, "-decrypted")
-	} else {
-		outputFilePath = o.OutputFilePath
+	outputFilePath := o.OutputFilePath
+	if outputFilePath == "" {
+		if o.FilePath == stdio {
+			outputFilePath = stdio
+		} else {
+			outputFilePath = util.AddSuffixToFileName(o.FilePath, "-decrypted")
+		}
 	}
-	_, err = cryptography.DecryptFile(
-		o.FilePath,
-		string(passphrase),
-		cryptography.WithOutputFilename(outputFilePath),
-	)
+	in, err := openInput(o.FilePath)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
 
-	return err
+	return writeOutput(outputFilePath, func(w io.Writer) error {
+		if identities != nil {
+			return DecryptWithIdentities(w, in, identities)
+		}
+		return DecryptStream(w, in, passphrase)
+	})
 }
@@ -23,6 +23,7 @@ THE SOFTWARE.
 package cryptography
 
 import (
+	"bytes"
 	"os"
 	"path/filepath"
 	"testing"
@@ -58,13 +59,15 @@ func TestNewCmdDecryptFile(t *testing.T) {
 		short             string
 		hasFlags          bool
 		keyFile           string
+		recipientsFile    string
+		identityFile      string
 		filePath          string
 		outputFilePath    string
 		encOutputFilename string
 		decOutputFilename string
 	}{
 		"valid command": {
-			use:               "decryptFile [-k] -f FILENAME -o OUTPUT_FILENAME",
+			use:               "decryptFile [-k] [-i] -f FILENAME -o OUTPUT_FILENAME",
 			short:             "Decrypt a file.",
 			hasFlags:          true,
 			keyFile:           filepath.Join("testdata", "secret-test.key"),
@@ -74,7 +77,7 @@ func TestNewCmdDecryptFile(t *testing.T) {
 			decOutputFilename: filepath.Join("testdata", "story-encrypted-decrypted.txt"),
 		},
 		"valid command with output file": {
-			use:               "decryptFile [-k] -f FILENAME -o OUTPUT_FILENAME",
+			use:               "decryptFile [-k] [-i] -f FILENAME -o OUTPUT_FILENAME",
 			short:             "Decrypt a file.",
 			hasFlags:          true,
 			keyFile:           filepath.Join("testdata", "secret-test.key"),
@@ -83,6 +86,17 @@ func TestNewCmdDecryptFile(t *testing.T) {
 			encOutputFilename: filepath.Join("testdata", "story-encrypted.txt"),
 			decOutputFilename: filepath.Join("testdata", "story-decrypted.txt"),
 		},
+		"valid command with identity": {
+			use:               "decryptFile [-k] [-i] -f FILENAME -o OUTPUT_FILENAME",
+			short:             "Decrypt a file.",
+			hasFlags:          true,
+			recipientsFile:    filepath.Join("testdata", "recipients-test.txt"),
+			identityFile:      filepath.Join("testdata", "identity-test.key"),
+			filePath:          filepath.Join("testdata", "story.txt"),
+			outputFilePath:    filepath.Join("testdata", "story-decrypted.txt"),
+			encOutputFilename: filepath.Join("testdata", "story-encrypted.txt"),
+			decOutputFilename: filepath.Join("testdata", "story-decrypted.txt"),
+		},
 	}
 
 	for name, c := range cases {
@@ -92,16 +106,24 @@ func TestNewCmdDecryptFile(t *testing.T) {
 			defer func() { os.Args = oldArgs }()
 			os.Args = []string{
 				"cmd",
-				"--key-file", c.keyFile,
 				"--file", c.filePath}
+			if c.recipientsFile != "" {
+				os.Args = append(os.Args, "--recipients-file", c.recipientsFile)
+			} else {
+				os.Args = append(os.Args, "--key-file", c.keyFile)
+			}
 
 			encCmd := NewCmdEncryptFile(nil)
 			encCmd.Execute()
 
 			os.Args = []string{
 				"cmd",
-				"--key-file", c.keyFile,
 				"--file", c.encOutputFilename}
+			if c.identityFile != "" {
+				os.Args = append(os.Args, "--identity", c.identityFile)
+			} else {
+				os.Args = append(os.Args, "--key-file", c.keyFile)
+			}
 			if c.outputFilePath != "" {
 				os.Args = append(os.Args, "--output-file", c.outputFilePath)
 			}
@@ -120,16 +142,30 @@ func TestNewCmdDecryptFile(t *testing.T) {
 // input and verifies that an error is returned.
 func TestNewCmdDecryptFileInvalid(t *testing.T) {
 	cases := map[string]struct {
-		use      string
-		short    string
-		hasFlags bool
-		keyFile  string
-		filePath string
+		use          string
+		short        string
+		hasFlags     bool
+		keyFile      string
+		identityFile string
+		filePath     string
 	}{
 		"non-exist key file": {
 			keyFile:  filepath.Join("testdata", "missing.key"),
 			filePath: filepath.Join("testdata", "story.txt"),
 		},
+		"non-exist identity file": {
+			identityFile: filepath.Join("testdata", "missing.key"),
+			filePath:     filepath.Join("testdata", "story.txt"),
+		},
+		"identity with key file": {
+			keyFile:      filepath.Join("testdata", "secret-test.key"),
+			identityFile: filepath.Join("testdata", "identity-test.key"),
+			filePath:     filepath.Join("testdata", "story.txt"),
+		},
+		"not encrypted to recipients": {
+			identityFile: filepath.Join("testdata", "identity-test.key"),
+			filePath:     filepath.Join("testdata", "story.txt"),
+		},
 	}
 
 	for name, c := range cases {
@@ -140,9 +176,48 @@ func TestNewCmdDecryptFileInvalid(t *testing.T) {
 				"cmd",
 				"--key-file", c.keyFile,
 				"--file", c.filePath}
+			if c.identityFile != "" {
+				os.Args = append(os.Args, "--identity", c.identityFile)
+			}
 
 			cmd := NewCmdDecryptFile(nil)
 			assert.Panics(t, func() { cmd.Execute() })
 		})
 	}
 }
+
+// TestNewCmdDecryptFileStdin tests the decryptFile command which reads
+// the file from stdin
+func TestNewCmdDecryptFileStdin(t *testing.T) {
+	keyFile := filepath.Join("testdata", "secret-test.key")
+	passphrase, err := os.ReadFile(keyFile)
+	if !assert.NoError(t, err) {
+		return
+	}
+	story, err := os.ReadFile(filepath.Join("testdata", "story.txt"))
+	if !assert.NoError(t, err) {
+		return
+	}
+	var encrypted bytes.Buffer
+	assert.NoError(t, EncryptStream(&encrypted, bytes.NewReader(story), passphrase, testStreamConfig))
+	dir := t.TempDir()
+	encFilename := filepath.Join(dir, "story.enc")
+	assert.NoError(t, os.WriteFile(encFilename, encrypted.Bytes(), 0600))
+
+	stdin, err := os.Open(encFilename)
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer stdin.Close()
+	oldArgs, oldStdin := os.Args, os.Stdin
+	defer func() { os.Args, os.Stdin = oldArgs, oldStdin }()
+	os.Stdin = stdin
+	decFilename := filepath.Join(dir, "story.txt")
+	os.Args = []string{"cmd", "--key-file", keyFile, "--file", "-", "--output-file", decFilename}
+
+	cmd := NewCmdDecryptFile(nil)
+	assert.NoError(t, cmd.Execute())
+	decrypted, err := os.ReadFile(decFilename)
+	assert.NoError(t, err)
+	assert.Equal(t, story, decrypted)
+}
@@ -36,23 +36,41 @@ func init() {
 	util.InitAxolgoConfig(filepath.Join(filepath.Dir(""), "..", "..", "testdata", "config"))
 }
 
+// recipientsTestMessage is "Hello World" encrypted to testdata/recipients-test.txt
+const recipientsTestMessage = "6167652d656e6372797074696f6e2e6f72672f76310a2d3e20583235353139204e575941776b42423044414d42456c34664e7450787746444d355979377a674876303749552b6a58486b510a346248642b584e7378377333463644336e47526843497574472f6b5263792b5464354b3563474e7474746f0a2d2d2d206452735059674d4d4158375667636f2b6a7a37634c537579487648527773674a6f57477461754a797544670a1eb54f2229dff1f67a94130ec0c153242ecabbf3b99801a0dcf99c25c8db5bdba854d07cf4529c6456b48e"
+
 // TestNewCmdDecrypt tests the NewCmdDecrypt function
 // to make sure it returns a valid command.
 func TestNewCmdDecrypt(t *testing.T) {
 	cases := map[string]struct {
-		use      string
-		short    string
-		hasFlags bool
-		keyFile  string
-		message  string
+		use          string
+		short        string
+		hasFlags     bool
+		keyFile      string
+		identityFile string
+		message      string
 	}{
 		"valid command": {
-			use:      "decrypt [-k] [-m]",
+			use:      "decrypt [-k] [-i] [-m]",
 			short:    "Decrypt a message.",
 			hasFlags: true,
 			keyFile:  filepath.Join("testdata", "secret-test.key"),
 			message:  "e4b433b2b2cc8d95e9859d1c66a338254a03316d95631f4d4af9d977a37c2776e8ed914486f67e",
 		},
+		"valid command with header": {
+			use:      "decrypt [-k] [-i] [-m]",
+			short:    "Decrypt a message.",
+			hasFlags: true,
+			keyFile:  filepath.Join("testdata", "secret-test.key"),
+			message:  "41584f4c474f020100010000010a00000008000000013427a51d6f3f954b663f89042e8b0a592b1f48dcad85a5b8ddabaf48da060a4f744c898e3a764b384a3d3c",
+		},
+		"valid command with identity": {
+			use:          "decrypt [-k] [-i] [-m]",
+			short:        "Decrypt a message.",
+			hasFlags:     true,
+			identityFile: filepath.Join("testdata", "identity-test.key"),
+			message:      recipientsTestMessage,
+		},
 	}
 
 	for name, c := range cases {
@@ -61,8 +79,13 @@ func TestNewCmdDecrypt(t *testing.T) {
 			defer func() { os.Args = oldArgs }()
 			os.Args = []string{
 				"cmd",
-				"--key-file", c.keyFile,
 				"--message", c.message}
+			if c.keyFile != "" {
+				os.Args = append(os.Args, "--key-file", c.keyFile)
+			}
+			if c.identityFile != "" {
+				os.Args = append(os.Args, "--identity", c.identityFile)
+			}
 
 			cmd := NewCmdDecrypt(nil)
 			assert.Equal(t, c.use, cmd.Use)
@@ -77,11 +100,12 @@ func TestNewCmdDecrypt(t *testing.T) {
 // input and verifies that an error is returned.
 func TestNewCmdDecryptInvalid(t *testing.T) {
 	cases := map[string]struct {
-		use      string
-		short    string
-		hasFlags bool
-		keyFile  string
-		message  string
+		use          string
+		short        string
+		hasFlags     bool
+		keyFile      string
+		identityFile string
+		message      string
 	}{
 		"non-exist key file": {
 			keyFile: filepath.Join("testdata", "missing.key"),
@@ -91,6 +115,19 @@ func TestNewCmdDecryptInvalid(t *testing.T) {
 			keyFile: filepath.Join("testdata", "secret-test.key"),
 			message: "123455",
 		},
+		"identity with key file": {
+			keyFile:      filepath.Join("testdata", "secret-test.key"),
+			identityFile: filepath.Join("testdata", "identity-test.key"),
+			message:      recipientsTestMessage,
+		},
+		"key file for recipients": {
+			keyFile: filepath.Join("testdata", "secret-test.key"),
+			message: recipientsTestMessage,
+		},
+		"identity for passphrase": {
+			identityFile: filepath.Join("testdata", "identity-test.key"),
+			message:      "41584f4c474f020100010000010a00000008000000013427a51d6f3f954b663f89042e8b0a592b1f48dcad85a5b8ddabaf48da060a4f744c898e3a764b384a3d3c",
+		},
 	}
 
 	for name, c := range cases {
@@ -99,8 +136,13 @@ func TestNewCmdDecryptInvalid(t *testing.T) {
 			defer func() { os.Args = oldArgs }()
 			os.Args = []string{
 				"cmd",
-				"--key-file", c.keyFile,
 				"--message", c.message}
+			if c.keyFile != "" {
+				os.Args = append(os.Args, "--key-file", c.keyFile)
+			}
+			if c.identityFile != "" {
+				os.Args = append(os.Args, "--identity", c.identityFile)
+			}
 
 			cmd := NewCmdDecrypt(nil)
 			assert.Panics(t, func() { cmd.Execute() })
@@ -24,6 +24,7 @@ package cryptography
 
 import (
 	"bufio"
+	"bytes"
 	"context"
 	"encoding/hex"
 	"fmt"
@@ -31,25 +32,43 @@ import (
 	"strings"
 	"syscall"
 
+	"filippo.io/age"
 	"golang.org/x/term"
 	"k8s.io/klog/v2"
 
 	"github.com/spf13/cobra"
-	"github.com/tchiunam/axolgo-lib/cryptography"
 )
 
 var (
-	encryptLong = "Encrypt the provided string with a key."
+	encryptLong = `Encrypt the provided string with a key.
+
+The key is derived from the passphrase with Argon2id by default, or
+with scrypt. The encrypted message starts with a header which records
+the format version, the KDF and its costs, the salt and the cipher, so
+they are not needed to decrypt it.
+
+Instead of a passphrase, the message can be encrypted to the public
+keys of one or more recipients. Any one of the recipients can decrypt
+it with the private key. The encrypted message is the hex of the
+format of age.`
 
 	encryptExample = `  # Encrypt the string "Hello World" with a key file.
   axolgo crytography encrypt --key-file secret.key --message "Hello World"
+
+  # Encrypt the string "Hello World" with a key derived by scrypt
+  axolgo cryptography encrypt --key-file secret.key --message "Hello World" --kdf scrypt
+
+  # Encrypt the string "Hello World" to the recipients of a team
+  axolgo cryptography encrypt --recipients-file team.txt --message "Hello World"
 `
 )
 
 // EncryptOptions defines flags and other configuration parameters for the `encrypt` command
 type EncryptOptions struct {
-	KeyFile string
-	Message string
+	KeyFile        string
+	Message        string
+	KDFFlags       KDFFlags
+	RecipientFlags RecipientFlags
 }
 
 // NewCmdEncrypt creates the `encrypt` command
@@ -57,7 +76,7 @@ func NewCmdEncrypt(ctx *context.Context) *cobra.Command {
 	o := EncryptOptions{}
 
 	cmd := &cobra.Command{
-		Use:                   "encrypt [-k] [-m]",
+		Use:                   "encrypt [-k] [-r]... [-R] [-m] [--kdf] [--argon2-time] [--argon2-memory] [--argon2-threads] [--scrypt-log-n] [--scrypt-r] [--scrypt-p]",
 		DisableFlagsInUseLine: true,
 		Short:                 "Encrypt a message.",
 		Long:                  encryptLong,
@@ -71,15 +90,25 @@ func NewCmdEncrypt(ctx *context.Context) *cobra.Command {
 
 	cmd.Flags().StringVarP(&o.KeyFile, "key-file", "k", "", "Key file.")
 	cmd.Flags().StringVarP(&o.Message, "message", "m", "", "Message to be encrypted.")
+	addKDFFlags(cmd, &o.KDFFlags)
+	addRecipientFlags(cmd, &o.RecipientFlags)
 
 	return cmd
 }
 
 // Complete takes the command arguments and execute.
-func (o *EncryptOptions) complete(_ *context.Context, _ *cobra.Command, args []string) error {
+func (o *EncryptOptions) complete(_ *context.Context, cmd *cobra.Command, args []string) error {
+	var recipients []age.Recipient
+	var kdf KDF
 	var passphrase []byte
 	var err error
-	if o.KeyFile == "" {
+	if o.RecipientFlags.Given() {
+		if recipients, err = o.RecipientFlags.Parse(cmd); err != nil {
+			return err
+		}
+	} else if kdf, err = o.KDFFlags.KDF(cmd); err != nil {
+		return err
+	} else if o.KeyFile == "" {
 		fmt.Print("Enter passphrase: ")
 		if passphrase, err = term.ReadPassword(int(syscall.Stdin)); err != nil {
 			klog.Errorf("Failed to read passphrase from stdin: %v", err)
@@ -102,8 +131,17 @@ func (o *EncryptOptions) complete(_ *context.Context, _ *cobra.Command, args []s
 		}
 		o.Message = strings.Join(input, "\n")
 	}
-	data, err := cryptography.Encrypt([]byte(o.Message), string(passphrase))
-	fmt.Println(hex.EncodeToString(data))
+	var data bytes.Buffer
+	if recipients != nil {
+		err = EncryptToRecipients(&data, strings.NewReader(o.Message), recipients)
+	} else {
+		err = EncryptStream(&data, strings.NewReader(o.Message), passphrase, StreamConfig{ChunkSize: DefaultChunkSize, KDF: kdf})
+	}
+	if err != nil {
+		klog.Errorf("Failed to encrypt message: %v", err)
+		return err
+	}
+	fmt.Println(hex.EncodeToString(data.Bytes()))
 
 	return nil
 }
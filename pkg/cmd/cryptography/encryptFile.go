@@ -25,22 +25,46 @@ package cryptography
 import (
 	"context"
 	"fmt"
-	"os"
-	"syscall"
-
-	"golang.org/x/term"
-	"k8s.io/klog/v2"
+	"io"
 
+	"filippo.io/age"
 	"github.com/spf13/cobra"
-	"github.com/tchiunam/axolgo-lib/cryptography"
 	"github.com/tchiunam/axolgo-lib/util"
 )
 
 var (
-	encryptFileLong = "Encrypt the provided file with a key."
+	encryptFileLong = `Encrypt the provided file with a key.
+
+The file is encrypted as a stream of authenticated chunks in constant
+memory, so a file of any size can be encrypted. Give - as the file to
+read from stdin and as the output file to write to stdout, e.g. in a
+pipeline. A key file is required to read from stdin. The output file
+is written only if the encryption succeeds.
+
+The key is derived from the passphrase with Argon2id by default, or
+with scrypt. The KDF and its costs are recorded in the header of the
+encrypted file, so they are not needed to decrypt it.
+
+Instead of a passphrase, the file can be encrypted to the public keys
+of one or more recipients, which are generated by genKeyPair or
+age-keygen. Any one of the recipients can decrypt it with the private
+key. The encrypted file is in the format of age, so it can also be
+decrypted by age. A key file is not required to read from stdin then.`
 
 	encryptFileExample = `  # Encrypt the file 'example.txt with a key file.
   axolgo crytography encryptFile --key-file secret.key --file example.txt
+
+  # Encrypt a database dump from a pipeline
+  pg_dump mydb | axolgo cryptography encryptFile --key-file secret.key -f - -o dump.enc
+
+  # Encrypt a file with a key derived by scrypt of a higher cost
+  axolgo cryptography encryptFile --key-file secret.key --file example.txt --kdf scrypt --scrypt-log-n 18
+
+  # Encrypt a file to two recipients
+  axolgo cryptography encryptFile --file example.txt --recipient age1ql3z7hjy54pw3hyww5ayyfg7zqgvc7w3j2elw8zmrj2kg5sfn9aqmcac8p --recipient age1lggyhqrw2nlhcxprm67z43rta597azn8gknawjehu9d9dl0jq3yqqvfafg
+
+  # Encrypt a file to the recipients of a team
+  axolgo cryptography encryptFile --file example.txt --recipients-file team.txt
 `
 )
 
@@ -49,6 +73,9 @@ type EncryptFileOptions struct {
 	KeyFile        string
 	FilePath       string
 	OutputFilePath string
+	ChunkSize      int
+	KDFFlags       KDFFlags
+	RecipientFlags RecipientFlags
 }
 
 // NewCmdEncryptFile creates the `encryptFile` command
@@ -56,7 +83,7 @@ func NewCmdEncryptFile(ctx *context.Context) *cobra.Command {
 	o := EncryptFileOptions{}
 
 	cmd := &cobra.Command{
-		Use:                   "encryptFile [-k] -f FILENAME -o OUTPUT_FILENAME",
+		Use:                   "encryptFile [-k] [-r]... [-R] -f FILENAME -o OUTPUT_FILENAME [--chunk-size] [--kdf] [--argon2-time] [--argon2-memory] [--argon2-threads] [--scrypt-log-n] [--scrypt-r] [--scrypt-p]",
 		DisableFlagsInUseLine: true,
 		Short:                 "Encrypt a file.",
 		Long:                  encryptFileLong,
@@ -69,8 +96,11 @@ func NewCmdEncryptFile(ctx *context.Context) *cobra.Command {
 	}
 
 	cmd.Flags().StringVarP(&o.KeyFile, "key-file", "k", "", "Key file.")
-	cmd.Flags().StringVarP(&o.FilePath, "file", "f", "", "File to be encrypted.")
-	cmd.Flags().StringVarP(&o.OutputFilePath, "output-file", "o", "", "Output file.")
+	cmd.Flags().StringVarP(&o.FilePath, "file", "f", "", "File to be encrypted. - is stdin.")
+	cmd.Flags().StringVarP(&o.OutputFilePath, "output-file", "o", "", "Output file. - is stdout. Default is the file name with suffix -encrypted, or stdout for stdin.")
+	cmd.Flags().IntVar(&o.ChunkSize, "chunk-size", DefaultChunkSize/1024, "Size of a chunk in KiB.")
+	addKDFFlags(cmd, &o.KDFFlags)
+	addRecipientFlags(cmd, &o.RecipientFlags)
 
 	cmd.MarkFlagRequired("file")
 
@@ -78,35 +108,46 @@ func NewCmdEncryptFile(ctx *context.Context) *cobra.Command {
 }
 
 // Complete takes the command arguments and execute.
-func (o *EncryptFileOptions) complete(_ *context.Context, _ *cobra.Command, args []string) error {
+func (o *EncryptFileOptions) complete(_ *context.Context, cmd *cobra.Command, args []string) error {
+	chunkSize := o.ChunkSize * 1024
+	if chunkSize < minChunkSize || chunkSize > maxChunkSize {
+		return fmt.Errorf("invalid chunk size: %d KiB", o.ChunkSize)
+	}
+	var recipients []age.Recipient
+	var kdf KDF
 	var passphrase []byte
 	var err error
-	if o.KeyFile == "" {
-		fmt.Print("Enter passphrase: ")
-		if passphrase, err = term.ReadPassword(int(syscall.Stdin)); err != nil {
-			klog.Errorf("Failed to read passphrase from stdin: %v", err)
+	if o.RecipientFlags.Given() {
+		if recipients, err = o.RecipientFlags.Parse(cmd); err != nil {
 			return err
 		}
-		fmt.Println()
 	} else {
-		passphrase, err = os.ReadFile(o.KeyFile)
-		if err != nil {
-			klog.Errorf("Failed to read key file: %s", o.KeyFile)
+		if kdf, err = o.KDFFlags.KDF(cmd); err != nil {
+			return err
+		}
+		if passphrase, err = readPassphrase(o.KeyFile, o.FilePath == stdio); err != nil {
 			return err
 		}
 	}
 
-	var outputFilePath string
-	if o.OutputFilePath == "" {
-		outputFilePath = util.AddSuffixToFileName(o.FilePath, "-encrypted")
-	} else {
-		outputFilePath = o.OutputFilePath
+	outputFilePath := o.OutputFilePath
+	if outputFilePath == "" {
+		if o.FilePath == stdio {
+			outputFilePath = stdio
+		} else {
+			outputFilePath = util.AddSuffixToFileName(o.FilePath, "-encrypted")
+		}
+	}
+	in, err := openInput(o.FilePath)
+	if err != nil {
+		return err
 	}
-	_, err = cryptography.EncryptFile(
-		o.FilePath,
-		string(passphrase),
-		cryptography.WithOutputFilename(outputFilePath),
-	)
+	defer in.Close()
 
-	return err
+	return writeOutput(outputFilePath, func(w io.Writer) error {
+		if recipients != nil {
+			return EncryptToRecipients(w, in, recipients)
+		}
+		return EncryptStream(w, in, passphrase, StreamConfig{ChunkSize: chunkSize, KDF: kdf})
+	})
 }
@@ -55,9 +55,10 @@ func TestNewCmdEncryptFile(t *testing.T) {
 		keyFile        string
 		filePath       string
 		outputFilePath string
+		args           []string
 	}{
 		"valid command": {
-			use:            "encryptFile [-k] -f FILENAME -o OUTPUT_FILENAME",
+			use:            "encryptFile [-k] [-r]... [-R] -f FILENAME -o OUTPUT_FILENAME [--chunk-size] [--kdf] [--argon2-time] [--argon2-memory] [--argon2-threads] [--scrypt-log-n] [--scrypt-r] [--scrypt-p]",
 			short:          "Encrypt a file.",
 			hasFlags:       true,
 			keyFile:        filepath.Join("testdata", "secret-test.key"),
@@ -65,13 +66,32 @@ func TestNewCmdEncryptFile(t *testing.T) {
 			outputFilePath: "",
 		},
 		"valid command with output file": {
-			use:            "encryptFile [-k] -f FILENAME -o OUTPUT_FILENAME",
+			use:            "encryptFile [-k] [-r]... [-R] -f FILENAME -o OUTPUT_FILENAME [--chunk-size] [--kdf] [--argon2-time] [--argon2-memory] [--argon2-threads] [--scrypt-log-n] [--scrypt-r] [--scrypt-p]",
 			short:          "Encrypt a file.",
 			hasFlags:       true,
 			keyFile:        filepath.Join("testdata", "secret-test.key"),
 			filePath:       filepath.Join("testdata", "story.txt"),
 			outputFilePath: filepath.Join("testdata", "story-encrypted.txt"),
 		},
+		"valid command with scrypt": {
+			use:            "encryptFile [-k] [-r]... [-R] -f FILENAME -o OUTPUT_FILENAME [--chunk-size] [--kdf] [--argon2-time] [--argon2-memory] [--argon2-threads] [--scrypt-log-n] [--scrypt-r] [--scrypt-p]",
+			short:          "Encrypt a file.",
+			hasFlags:       true,
+			keyFile:        filepath.Join("testdata", "secret-test.key"),
+			filePath:       filepath.Join("testdata", "story.txt"),
+			outputFilePath: "",
+			args:           []string{"--kdf", "scrypt", "--scrypt-log-n", "10"},
+		},
+		"valid command with recipients": {
+			use:            "encryptFile [-k] [-r]... [-R] -f FILENAME -o OUTPUT_FILENAME [--chunk-size] [--kdf] [--argon2-time] [--argon2-memory] [--argon2-threads] [--scrypt-log-n] [--scrypt-r] [--scrypt-p]",
+			short:          "Encrypt a file.",
+			hasFlags:       true,
+			filePath:       filepath.Join("testdata", "story.txt"),
+			outputFilePath: "",
+			args: []string{
+				"--recipients-file", filepath.Join("testdata", "recipients-test.txt"),
+				"--recipient", "age1ql3z7hjy54pw3hyww5ayyfg7zqgvc7w3j2elw8zmrj2kg5sfn9aqmcac8p"},
+		},
 	}
 
 	for name, c := range cases {
@@ -81,11 +101,14 @@ func TestNewCmdEncryptFile(t *testing.T) {
 			defer func() { os.Args = oldArgs }()
 			os.Args = []string{
 				"cmd",
-				"--key-file", c.keyFile,
 				"--file", c.filePath}
+			if c.keyFile != "" {
+				os.Args = append(os.Args, "--key-file", c.keyFile)
+			}
 			if c.outputFilePath != "" {
 				os.Args = append(os.Args, "--output-file", c.outputFilePath)
 			}
+			os.Args = append(os.Args, c.args...)
 
 			cmd := NewCmdEncryptFile(nil)
 			assert.Equal(t, c.use, cmd.Use)
@@ -106,11 +129,43 @@ func TestNewCmdEncryptFileInvalid(t *testing.T) {
 		hasFlags bool
 		keyFile  string
 		filePath string
+		args     []string
 	}{
 		"non-exist key file": {
 			keyFile:  filepath.Join("testdata", "missing.key"),
 			filePath: filepath.Join("testdata", "story.txt"),
 		},
+		"stdin without key file": {
+			filePath: "-",
+		},
+		"invalid KDF": {
+			keyFile:  filepath.Join("testdata", "secret-test.key"),
+			filePath: filepath.Join("testdata", "story.txt"),
+			args:     []string{"--kdf", "pbkdf2"},
+		},
+		"scrypt cost with argon2id": {
+			keyFile:  filepath.Join("testdata", "secret-test.key"),
+			filePath: filepath.Join("testdata", "story.txt"),
+			args:     []string{"--scrypt-log-n", "10"},
+		},
+		"invalid argon2id memory": {
+			keyFile:  filepath.Join("testdata", "secret-test.key"),
+			filePath: filepath.Join("testdata", "story.txt"),
+			args:     []string{"--argon2-memory", "1"},
+		},
+		"recipient with key file": {
+			keyFile:  filepath.Join("testdata", "secret-test.key"),
+			filePath: filepath.Join("testdata", "story.txt"),
+			args:     []string{"--recipients-file", filepath.Join("testdata", "recipients-test.txt")},
+		},
+		"recipient with KDF": {
+			filePath: filepath.Join("testdata", "story.txt"),
+			args:     []string{"--recipient", "age1ql3z7hjy54pw3hyww5ayyfg7zqgvc7w3j2elw8zmrj2kg5sfn9aqmcac8p", "--kdf", "scrypt"},
+		},
+		"invalid recipient": {
+			filePath: filepath.Join("testdata", "story.txt"),
+			args:     []string{"--recipient", "age1axolgo"},
+		},
 	}
 
 	for name, c := range cases {
@@ -119,8 +174,11 @@ func TestNewCmdEncryptFileInvalid(t *testing.T) {
 			defer func() { os.Args = oldArgs }()
 			os.Args = []string{
 				"cmd",
-				"--key-file", c.keyFile,
 				"--file", c.filePath}
+			if c.keyFile != "" {
+				os.Args = append(os.Args, "--key-file", c.keyFile)
+			}
+			os.Args = append(os.Args, c.args...)
 
 			cmd := NewCmdEncryptFile(nil)
 			assert.Panics(t, func() { cmd.Execute() })
@@ -45,14 +45,30 @@ func TestNewCmdEncrypt(t *testing.T) {
 		hasFlags bool
 		keyFile  string
 		message  string
+		args     []string
 	}{
 		"valid command": {
-			use:      "encrypt [-k] [-m]",
+			use:      "encrypt [-k] [-r]... [-R] [-m] [--kdf] [--argon2-time] [--argon2-memory] [--argon2-threads] [--scrypt-log-n] [--scrypt-r] [--scrypt-p]",
 			short:    "Encrypt a message.",
 			hasFlags: true,
 			keyFile:  filepath.Join("testdata", "secret-test.key"),
 			message:  "Hello World",
 		},
+		"valid command with scrypt": {
+			use:      "encrypt [-k] [-r]... [-R] [-m] [--kdf] [--argon2-time] [--argon2-memory] [--argon2-threads] [--scrypt-log-n] [--scrypt-r] [--scrypt-p]",
+			short:    "Encrypt a message.",
+			hasFlags: true,
+			keyFile:  filepath.Join("testdata", "secret-test.key"),
+			message:  "Hello World",
+			args:     []string{"--kdf", "scrypt", "--scrypt-log-n", "10"},
+		},
+		"valid command with recipients": {
+			use:      "encrypt [-k] [-r]... [-R] [-m] [--kdf] [--argon2-time] [--argon2-memory] [--argon2-threads] [--scrypt-log-n] [--scrypt-r] [--scrypt-p]",
+			short:    "Encrypt a message.",
+			hasFlags: true,
+			message:  "Hello World",
+			args:     []string{"--recipients-file", filepath.Join("testdata", "recipients-test.txt")},
+		},
 	}
 
 	for name, c := range cases {
@@ -61,8 +77,11 @@ func TestNewCmdEncrypt(t *testing.T) {
 			defer func() { os.Args = oldArgs }()
 			os.Args = []string{
 				"cmd",
-				"--key-file", c.keyFile,
 				"--message", c.message}
+			if c.keyFile != "" {
+				os.Args = append(os.Args, "--key-file", c.keyFile)
+			}
+			os.Args = append(os.Args, c.args...)
 
 			cmd := NewCmdEncrypt(nil)
 			assert.Equal(t, c.use, cmd.Use)
@@ -82,11 +101,31 @@ func TestNewCmdEncryptInvalid(t *testing.T) {
 		hasFlags bool
 		keyFile  string
 		message  string
+		args     []string
 	}{
 		"non-exist key file": {
 			keyFile: filepath.Join("testdata", "missing.key"),
 			message: "Hello World",
 		},
+		"invalid KDF": {
+			keyFile: filepath.Join("testdata", "secret-test.key"),
+			message: "Hello World",
+			args:    []string{"--kdf", "pbkdf2"},
+		},
+		"argon2id cost with scrypt": {
+			keyFile: filepath.Join("testdata", "secret-test.key"),
+			message: "Hello World",
+			args:    []string{"--kdf", "scrypt", "--argon2-time", "1"},
+		},
+		"recipient with key file": {
+			keyFile: filepath.Join("testdata", "secret-test.key"),
+			message: "Hello World",
+			args:    []string{"--recipients-file", filepath.Join("testdata", "recipients-test.txt")},
+		},
+		"non-exist recipients file": {
+			message: "Hello World",
+			args:    []string{"--recipients-file", filepath.Join("testdata", "missing.txt")},
+		},
 	}
 
 	for name, c := range cases {
@@ -95,8 +134,11 @@ func TestNewCmdEncryptInvalid(t *testing.T) {
 			defer func() { os.Args = oldArgs }()
 			os.Args = []string{
 				"cmd",
-				"--key-file", c.keyFile,
 				"--message", c.message}
+			if c.keyFile != "" {
+				os.Args = append(os.Args, "--key-file", c.keyFile)
+			}
+			os.Args = append(os.Args, c.args...)
 
 			cmd := NewCmdEncrypt(nil)
 			assert.Panics(t, func() { cmd.Execute() })
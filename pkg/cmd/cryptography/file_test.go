@@ -0,0 +1,110 @@
+/*
+Copyright © 2022 tchiunam
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package cryptography
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestWriteOutput tests that writeOutput leaves no partial file
+func TestWriteOutput(t *testing.T) {
+	cases := map[string]struct {
+		err   error
+		valid bool
+	}{
+		"success": {
+			valid: true,
+		},
+		"failure": {
+			err: errors.New("authentication failed"),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			dir := t.TempDir()
+			path := filepath.Join(dir, "out.txt")
+			err := writeOutput(path, func(w io.Writer) error {
+				io.WriteString(w, "partial")
+				return tc.err
+			})
+			entries, _ := os.ReadDir(dir)
+			if !tc.valid {
+				assert.Error(t, err)
+				assert.Empty(t, entries)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Len(t, entries, 1)
+			data, err := os.ReadFile(path)
+			assert.NoError(t, err)
+			assert.Equal(t, "partial", string(data))
+			info, err := os.Stat(path)
+			assert.NoError(t, err)
+			assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+		})
+	}
+}
+
+// TestReadPassphrase tests the readPassphrase function
+func TestReadPassphrase(t *testing.T) {
+	cases := map[string]struct {
+		keyFile      string
+		stdinIsInput bool
+		valid        bool
+	}{
+		"key file": {
+			keyFile: filepath.Join("testdata", "secret-test.key"),
+			valid:   true,
+		},
+		"key file with stdin": {
+			keyFile:      filepath.Join("testdata", "secret-test.key"),
+			stdinIsInput: true,
+			valid:        true,
+		},
+		"missing key file": {
+			keyFile: filepath.Join("testdata", "missing.key"),
+		},
+		"no key file with stdin": {
+			stdinIsInput: true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			passphrase, err := readPassphrase(tc.keyFile, tc.stdinIsInput)
+			if !tc.valid {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Len(t, passphrase, 100)
+		})
+	}
+}
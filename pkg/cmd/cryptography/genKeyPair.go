@@ -0,0 +1,111 @@
+/*
+Copyright © 2022 tchiunam
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package cryptography
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"filippo.io/age"
+	"k8s.io/klog/v2"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	genKeyPairLong = `Generate an X25519 key pair to encrypt to recipients.
+
+The private key, i.e. the identity, is saved to a file which only the
+owner can read, and an existing file is not overwritten. The public
+key is printed, so that it can be shared to encrypt with --recipient.
+The keys are compatible with age.`
+
+	genKeyPairExample = `  # Generate a key pair.
+  axolgo cryptography genKeyPair --save-file private.key
+`
+)
+
+// GenKeyPairOptions defines flags and other configuration parameters for the `genKeyPair` command
+type GenKeyPairOptions struct {
+	SaveFile string
+}
+
+// NewCmdGenKeyPair creates the `genKeyPair` command
+func NewCmdGenKeyPair(ctx *context.Context) *cobra.Command {
+	o := GenKeyPairOptions{}
+
+	cmd := &cobra.Command{
+		Use:                   "genKeyPair -s FILE",
+		DisableFlagsInUseLine: true,
+		Short:                 "Generate a key pair.",
+		Long:                  genKeyPairLong,
+		Example:               genKeyPairExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := o.complete(ctx, cmd, args); err != nil {
+				panic(err)
+			}
+		},
+	}
+
+	cmd.Flags().StringVarP(&o.SaveFile, "save-file", "s", "", "Save the private key to a file.")
+
+	cmd.MarkFlagRequired("save-file")
+
+	return cmd
+}
+
+// Complete takes the command arguments and execute.
+func (o *GenKeyPairOptions) complete(_ *context.Context, _ *cobra.Command, args []string) error {
+	identity, err := age.GenerateX25519Identity()
+	if err != nil {
+		klog.Errorf("Failed to generate key pair: %s", err)
+		return err
+	}
+	if err := writeIdentityFile(o.SaveFile, identity, time.Now()); err != nil {
+		klog.Errorf("Failed to write private key into file: %s", o.SaveFile)
+		return err
+	}
+	fmt.Println(identity.Recipient().String())
+
+	return nil
+}
+
+// writeIdentityFile writes the identity to a new file in the format of
+// age-keygen
+func writeIdentityFile(path string, identity *age.X25519Identity, now time.Time) error {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(f, "# created: %s\n# public key: %s\n%s\n", now.Format(time.RFC3339), identity.Recipient(), identity)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(path)
+	}
+
+	return err
+}
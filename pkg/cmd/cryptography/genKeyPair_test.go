@@ -0,0 +1,131 @@
+/*
+Copyright © 2022 tchiunam
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package cryptography
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"filippo.io/age"
+	"github.com/stretchr/testify/assert"
+	"github.com/tchiunam/axolgo-cli/pkg/util"
+)
+
+// Initialize environment for the tests
+func init() {
+	util.InitAxolgoConfig(filepath.Join(filepath.Dir(""), "..", "..", "testdata", "config"))
+}
+
+// TestNewCmdGenKeyPair tests the NewCmdGenKeyPair function
+// to make sure it returns a valid command.
+func TestNewCmdGenKeyPair(t *testing.T) {
+	cases := map[string]struct {
+		use      string
+		short    string
+		hasFlags bool
+		saveFile string
+	}{
+		"save file": {
+			use:      "genKeyPair -s FILE",
+			short:    "Generate a key pair.",
+			hasFlags: true,
+			saveFile: filepath.Join(t.TempDir(), "private.key"),
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			oldArgs := os.Args
+			defer func() { os.Args = oldArgs }()
+			os.Args = []string{
+				"cmd",
+				"--save-file", c.saveFile}
+
+			cmd := NewCmdGenKeyPair(nil)
+			assert.Equal(t, c.use, cmd.Use)
+			assert.Equal(t, c.short, cmd.Short)
+			assert.Equal(t, c.hasFlags, cmd.Flags().HasFlags())
+			assert.NoError(t, cmd.Execute())
+
+			identities, err := readIdentities(c.saveFile)
+			assert.NoError(t, err)
+			assert.Equal(t, 1, len(identities))
+			info, err := os.Stat(c.saveFile)
+			if assert.NoError(t, err) {
+				assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+			}
+		})
+	}
+}
+
+// TestNewCmdGenKeyPairInvalid calls the NewCmdGenKeyPair function with an invalid
+// input and verifies that an error is returned.
+func TestNewCmdGenKeyPairInvalid(t *testing.T) {
+	cases := map[string]struct {
+		saveFile string
+	}{
+		"existing file": {
+			saveFile: filepath.Join("testdata", "identity-test.key"),
+		},
+		"non-exist directory": {
+			saveFile: filepath.Join("testdata", "missing", "private.key"),
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			oldArgs := os.Args
+			defer func() { os.Args = oldArgs }()
+			os.Args = []string{
+				"cmd",
+				"--save-file", c.saveFile}
+
+			cmd := NewCmdGenKeyPair(nil)
+			assert.Panics(t, func() { cmd.Execute() })
+		})
+	}
+}
+
+// TestWriteIdentityFile tests that the identity file is in the format of age-keygen
+func TestWriteIdentityFile(t *testing.T) {
+	identity, err := age.GenerateX25519Identity()
+	if !assert.NoError(t, err) {
+		return
+	}
+	path := filepath.Join(t.TempDir(), "private.key")
+	now := time.Date(2022, 12, 31, 8, 0, 0, 0, time.UTC)
+	assert.NoError(t, writeIdentityFile(path, identity, now))
+
+	data, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		"# created: 2022-12-31T08:00:00Z",
+		"# public key: " + identity.Recipient().String(),
+		identity.String(),
+		"",
+	}, strings.Split(string(data), "\n"))
+	assert.Error(t, writeIdentityFile(path, identity, now))
+}
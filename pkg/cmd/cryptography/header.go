@@ -0,0 +1,350 @@
+/*
+Copyright © 2022 tchiunam
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package cryptography
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/scrypt"
+)
+
+// The header of an encrypted stream describes how to decrypt it. It
+// starts with the magic and the format version.
+//
+// Version 1 is followed by the chunk size and the salt, and its key is
+// derived with scrypt of the default costs.
+//
+// Version 2 is followed by the cipher ID, the chunk size, the KDF ID,
+// the parameters of the KDF and the salt. The parameters of scrypt are
+// log2(N), r and p, and those of Argon2id are time, memory in KiB and
+// threads.
+const (
+	streamMagic    = "AXOLGO"
+	streamVersion1 = 1
+	streamVersion  = 2
+	saltSize       = 16
+	keySize        = 32
+)
+
+// The ciphers of a stream
+const (
+	cipherAES256GCM = 1
+)
+
+// The KDFs which derive the key of a stream from a passphrase
+const (
+	KDFScrypt   = "scrypt"
+	KDFArgon2id = "argon2id"
+)
+
+var kdfs = []string{KDFArgon2id, KDFScrypt}
+
+// The IDs of the KDFs in a header
+var kdfIDs = map[string]uint8{KDFScrypt: 1, KDFArgon2id: 2}
+
+// The default and max. costs of the KDFs. The max. costs keep a
+// crafted header from exhausting the memory.
+const (
+	defaultScryptLogN    = 15
+	defaultScryptR       = 8
+	defaultScryptP       = 1
+	maxScryptLogN        = 24
+	defaultArgon2Time    = 3
+	defaultArgon2Memory  = 64 * 1024
+	defaultArgon2Threads = 4
+	maxArgon2Time        = 64
+	maxArgon2Memory      = 4 * 1024 * 1024
+	// scrypt uses about 128*r*(N+p) bytes, which are capped at the
+	// same 4 GiB as maxArgon2Memory
+	maxScryptMemory = 4 * 1024 * 1024 * 1024
+)
+
+// KDF is a key derivation function and its parameters
+type KDF struct {
+	Algorithm string
+	// The parameters of scrypt
+	ScryptLogN uint8
+	ScryptR    uint32
+	ScryptP    uint32
+	// The parameters of Argon2id
+	Argon2Time    uint32
+	Argon2Memory  uint32
+	Argon2Threads uint8
+}
+
+// DefaultKDF returns a KDF with the default costs
+func DefaultKDF(algorithm string) (KDF, error) {
+	switch algorithm {
+	case KDFScrypt:
+		return KDF{Algorithm: KDFScrypt, ScryptLogN: defaultScryptLogN, ScryptR: defaultScryptR, ScryptP: defaultScryptP}, nil
+	case KDFArgon2id:
+		return KDF{Algorithm: KDFArgon2id, Argon2Time: defaultArgon2Time, Argon2Memory: defaultArgon2Memory, Argon2Threads: defaultArgon2Threads}, nil
+	default:
+		return KDF{}, fmt.Errorf("invalid KDF %s, must be one of %v", algorithm, kdfs)
+	}
+}
+
+// validate checks that the costs are in range
+func (k KDF) validate() error {
+	switch k.Algorithm {
+	case KDFScrypt:
+		if k.ScryptLogN < 1 || k.ScryptLogN > maxScryptLogN {
+			return fmt.Errorf("invalid scrypt log2(N) %d, must be 1 to %d", k.ScryptLogN, maxScryptLogN)
+		}
+		if k.ScryptR < 1 || k.ScryptP < 1 || uint64(k.ScryptR)*uint64(k.ScryptP) >= 1<<30 {
+			return fmt.Errorf("invalid scrypt r %d and p %d", k.ScryptR, k.ScryptP)
+		}
+		// It cannot overflow as r*p < 2^30 and N <= 2^24
+		if r := uint64(k.ScryptR); r<<k.ScryptLogN+r*uint64(k.ScryptP) > maxScryptMemory/128 {
+			return fmt.Errorf("invalid scrypt log2(N) %d, r %d and p %d, which use more than %d bytes of memory", k.ScryptLogN, k.ScryptR, k.ScryptP, maxScryptMemory)
+		}
+	case KDFArgon2id:
+		if k.Argon2Time < 1 || k.Argon2Time > maxArgon2Time {
+			return fmt.Errorf("invalid Argon2id time %d, must be 1 to %d", k.Argon2Time, maxArgon2Time)
+		}
+		if k.Argon2Threads < 1 {
+			return fmt.Errorf("invalid Argon2id threads: %d", k.Argon2Threads)
+		}
+		if k.Argon2Memory < 8*uint32(k.Argon2Threads) || k.Argon2Memory > maxArgon2Memory {
+			return fmt.Errorf("invalid Argon2id memory %d KiB, must be 8 KiB per thread to %d KiB", k.Argon2Memory, maxArgon2Memory)
+		}
+	default:
+		return fmt.Errorf("invalid KDF %s, must be one of %v", k.Algorithm, kdfs)
+	}
+
+	return nil
+}
+
+// Key derives a key from the passphrase and the salt
+func (k KDF) Key(passphrase []byte, salt []byte) ([]byte, error) {
+	if err := k.validate(); err != nil {
+		return nil, err
+	}
+	if k.Algorithm == KDFArgon2id {
+		return argon2.IDKey(passphrase, salt, k.Argon2Time, k.Argon2Memory, k.Argon2Threads, keySize), nil
+	}
+	key, err := scrypt.Key(passphrase, salt, 1<<k.ScryptLogN, int(k.ScryptR), int(k.ScryptP), keySize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive key: %w", err)
+	}
+
+	return key, nil
+}
+
+// Header is the header of an encrypted stream
+type Header struct {
+	Version   uint8
+	Cipher    uint8
+	ChunkSize uint32
+	KDF       KDF
+	Salt      []byte
+}
+
+// MarshalBinary encodes the header in the current version
+func (h Header) MarshalBinary() ([]byte, error) {
+	if err := h.KDF.validate(); err != nil {
+		return nil, err
+	}
+	if len(h.Salt) != saltSize {
+		return nil, fmt.Errorf("invalid salt size: %d", len(h.Salt))
+	}
+	var buf bytes.Buffer
+	buf.WriteString(streamMagic)
+	buf.WriteByte(streamVersion)
+	buf.WriteByte(h.Cipher)
+	binary.Write(&buf, binary.BigEndian, h.ChunkSize)
+	buf.WriteByte(kdfIDs[h.KDF.Algorithm])
+	if h.KDF.Algorithm == KDFScrypt {
+		buf.WriteByte(h.KDF.ScryptLogN)
+		binary.Write(&buf, binary.BigEndian, h.KDF.ScryptR)
+		binary.Write(&buf, binary.BigEndian, h.KDF.ScryptP)
+	} else {
+		binary.Write(&buf, binary.BigEndian, h.KDF.Argon2Time)
+		binary.Write(&buf, binary.BigEndian, h.KDF.Argon2Memory)
+		buf.WriteByte(h.KDF.Argon2Threads)
+	}
+	buf.Write(h.Salt)
+
+	return buf.Bytes(), nil
+}
+
+// ReadHeader reads and validates the header of an encrypted stream. It
+// also returns the bytes of the header, which are authenticated with
+// the chunks.
+func ReadHeader(r io.Reader) (Header, []byte, error) {
+	var raw bytes.Buffer
+	tr := io.TeeReader(r, &raw)
+	read := func(v interface{}) error {
+		if err := binary.Read(tr, binary.BigEndian, v); err != nil {
+			return fmt.Errorf("failed to read stream header: %w", err)
+		}
+		return nil
+	}
+
+	magic := make([]byte, len(streamMagic))
+	if _, err := io.ReadFull(tr, magic); err != nil || !IsStream(magic) {
+		return Header{}, nil, errors.New("not an encrypted stream")
+	}
+	h := Header{Cipher: cipherAES256GCM}
+	if err := read(&h.Version); err != nil {
+		return Header{}, nil, err
+	}
+	switch h.Version {
+	case streamVersion1:
+		if err := read(&h.ChunkSize); err != nil {
+			return Header{}, nil, err
+		}
+		h.KDF, _ = DefaultKDF(KDFScrypt)
+	case streamVersion:
+		var kdfID uint8
+		if err := read(&h.Cipher); err != nil {
+			return Header{}, nil, err
+		}
+		if err := read(&h.ChunkSize); err != nil {
+			return Header{}, nil, err
+		}
+		if err := read(&kdfID); err != nil {
+			return Header{}, nil, err
+		}
+		switch kdfID {
+		case kdfIDs[KDFScrypt]:
+			h.KDF.Algorithm = KDFScrypt
+			if err := read(&h.KDF.ScryptLogN); err != nil {
+				return Header{}, nil, err
+			}
+			if err := read(&h.KDF.ScryptR); err != nil {
+				return Header{}, nil, err
+			}
+			if err := read(&h.KDF.ScryptP); err != nil {
+				return Header{}, nil, err
+			}
+		case kdfIDs[KDFArgon2id]:
+			h.KDF.Algorithm = KDFArgon2id
+			if err := read(&h.KDF.Argon2Time); err != nil {
+				return Header{}, nil, err
+			}
+			if err := read(&h.KDF.Argon2Memory); err != nil {
+				return Header{}, nil, err
+			}
+			if err := read(&h.KDF.Argon2Threads); err != nil {
+				return Header{}, nil, err
+			}
+		default:
+			return Header{}, nil, fmt.Errorf("unsupported KDF ID: %d", kdfID)
+		}
+	default:
+		return Header{}, nil, fmt.Errorf("unsupported stream version: %d", h.Version)
+	}
+	h.Salt = make([]byte, saltSize)
+	if _, err := io.ReadFull(tr, h.Salt); err != nil {
+		return Header{}, nil, fmt.Errorf("failed to read stream header: %w", err)
+	}
+
+	if h.Cipher != cipherAES256GCM {
+		return Header{}, nil, fmt.Errorf("unsupported cipher ID: %d", h.Cipher)
+	}
+	if h.ChunkSize < minChunkSize || h.ChunkSize > maxChunkSize {
+		return Header{}, nil, fmt.Errorf("invalid chunk size: %d", h.ChunkSize)
+	}
+	if err := h.KDF.validate(); err != nil {
+		return Header{}, nil, err
+	}
+
+	return h, raw.Bytes(), nil
+}
+
+// AEAD creates the cipher of the stream with the passphrase
+func (h Header) AEAD(passphrase []byte) (cipher.AEAD, error) {
+	key, err := h.KDF.Key(passphrase, h.Salt)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
+
+// KDFFlags are the flags to choose a KDF and tune its costs
+type KDFFlags struct {
+	Algorithm     string
+	ScryptLogN    uint8
+	ScryptR       uint32
+	ScryptP       uint32
+	Argon2Time    uint32
+	Argon2Memory  uint32
+	Argon2Threads uint8
+}
+
+// The flags of the costs of each KDF
+var kdfCostFlags = map[string][]string{
+	KDFScrypt:   {"scrypt-log-n", "scrypt-r", "scrypt-p"},
+	KDFArgon2id: {"argon2-time", "argon2-memory", "argon2-threads"},
+}
+
+// addKDFFlags adds the flags of a KDF to a command
+func addKDFFlags(cmd *cobra.Command, f *KDFFlags) {
+	cmd.Flags().StringVar(&f.Algorithm, "kdf", KDFArgon2id, fmt.Sprintf("KDF to derive the key from the passphrase. One of %v.", kdfs))
+	cmd.Flags().Uint8Var(&f.ScryptLogN, "scrypt-log-n", defaultScryptLogN, "log2 of the CPU/memory cost N of scrypt.")
+	cmd.Flags().Uint32Var(&f.ScryptR, "scrypt-r", defaultScryptR, "Block size r of scrypt.")
+	cmd.Flags().Uint32Var(&f.ScryptP, "scrypt-p", defaultScryptP, "Parallelization p of scrypt.")
+	cmd.Flags().Uint32Var(&f.Argon2Time, "argon2-time", defaultArgon2Time, "No. of passes of Argon2id.")
+	cmd.Flags().Uint32Var(&f.Argon2Memory, "argon2-memory", defaultArgon2Memory, "Memory of Argon2id in KiB.")
+	cmd.Flags().Uint8Var(&f.Argon2Threads, "argon2-threads", defaultArgon2Threads, "No. of threads of Argon2id.")
+}
+
+// KDF returns the KDF of the flags. The cost flags of the other KDF
+// cannot be given.
+func (f KDFFlags) KDF(cmd *cobra.Command) (KDF, error) {
+	for algorithm, names := range kdfCostFlags {
+		if algorithm == f.Algorithm {
+			continue
+		}
+		for _, name := range names {
+			if cmd.Flags().Changed(name) {
+				return KDF{}, fmt.Errorf("--%s cannot be used with --kdf %s", name, f.Algorithm)
+			}
+		}
+	}
+	k := KDF{Algorithm: f.Algorithm}
+	if f.Algorithm == KDFScrypt {
+		k.ScryptLogN, k.ScryptR, k.ScryptP = f.ScryptLogN, f.ScryptR, f.ScryptP
+	} else {
+		k.Argon2Time, k.Argon2Memory, k.Argon2Threads = f.Argon2Time, f.Argon2Memory, f.Argon2Threads
+	}
+	if err := k.validate(); err != nil {
+		return KDF{}, err
+	}
+
+	return k, nil
+}
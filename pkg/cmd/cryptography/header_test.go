@@ -0,0 +1,159 @@
+/*
+Copyright © 2022 tchiunam
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package cryptography
+
+import (
+	"bytes"
+	"crypto/rand"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestHeader tests that a header is read as it is written
+func TestHeader(t *testing.T) {
+	cases := map[string]struct {
+		kdf  KDF
+		size int
+	}{
+		"scrypt": {
+			kdf:  KDF{Algorithm: KDFScrypt, ScryptLogN: 10, ScryptR: 8, ScryptP: 2},
+			size: len(streamMagic) + 1 + 1 + 4 + 1 + 9 + saltSize,
+		},
+		"argon2id": {
+			kdf:  KDF{Algorithm: KDFArgon2id, Argon2Time: 1, Argon2Memory: 1024, Argon2Threads: 2},
+			size: len(streamMagic) + 1 + 1 + 4 + 1 + 9 + saltSize,
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			h := Header{Cipher: cipherAES256GCM, ChunkSize: minChunkSize, KDF: c.kdf, Salt: make([]byte, saltSize)}
+			rand.Read(h.Salt)
+			b, err := h.MarshalBinary()
+			if !assert.NoError(t, err) {
+				return
+			}
+			assert.Equal(t, c.size, len(b))
+			read, raw, err := ReadHeader(bytes.NewReader(append(b, "chunks"...)))
+			assert.NoError(t, err)
+			h.Version = streamVersion
+			assert.Equal(t, h, read)
+			assert.Equal(t, b, raw)
+		})
+	}
+}
+
+// TestReadHeaderInvalid tests that ReadHeader rejects the KDF costs out of range
+func TestReadHeaderInvalid(t *testing.T) {
+	h := Header{
+		Cipher:    cipherAES256GCM,
+		ChunkSize: minChunkSize,
+		KDF:       KDF{Algorithm: KDFArgon2id, Argon2Time: 1, Argon2Memory: 1024, Argon2Threads: 1},
+		Salt:      make([]byte, saltSize),
+	}
+	b, err := h.MarshalBinary()
+	if !assert.NoError(t, err) {
+		return
+	}
+	kdfOffset := len(streamMagic) + 1 + 1 + 4
+	cases := map[string]func(b []byte){
+		"not a stream": func(b []byte) { b[0] = 'a' },
+		"unknown KDF":  func(b []byte) { b[kdfOffset] = 99 },
+		"zero time":    func(b []byte) { copy(b[kdfOffset+1:], []byte{0, 0, 0, 0}) },
+		"huge memory":  func(b []byte) { copy(b[kdfOffset+5:], []byte{0xff, 0xff, 0xff, 0xff}) },
+		"zero threads": func(b []byte) { b[kdfOffset+9] = 0 },
+		"huge scrypt N": func(b []byte) {
+			copy(b[kdfOffset:], []byte{kdfIDs[KDFScrypt], 24, 0, 0, 0, 8, 0, 0, 0, 1})
+		},
+		"huge scrypt p": func(b []byte) {
+			copy(b[kdfOffset:], []byte{kdfIDs[KDFScrypt], 1, 0, 0, 0, 1, 0x20, 0, 0, 0})
+		},
+		"truncated": nil,
+	}
+
+	for name, modify := range cases {
+		t.Run(name, func(t *testing.T) {
+			data := append([]byte{}, b...)
+			if modify == nil {
+				data = data[:len(data)-1]
+			} else {
+				modify(data)
+			}
+			_, _, err := ReadHeader(bytes.NewReader(data))
+			assert.Error(t, err)
+		})
+	}
+}
+
+// TestKDFFlags tests that the flags make a KDF
+func TestKDFFlags(t *testing.T) {
+	cases := map[string]struct {
+		args    []string
+		kdf     KDF
+		invalid bool
+	}{
+		"default": {
+			kdf: KDF{Algorithm: KDFArgon2id, Argon2Time: defaultArgon2Time, Argon2Memory: defaultArgon2Memory, Argon2Threads: defaultArgon2Threads},
+		},
+		"argon2id": {
+			args: []string{"--argon2-time", "2", "--argon2-memory", "32768", "--argon2-threads", "1"},
+			kdf:  KDF{Algorithm: KDFArgon2id, Argon2Time: 2, Argon2Memory: 32768, Argon2Threads: 1},
+		},
+		"scrypt": {
+			args: []string{"--kdf", "scrypt", "--scrypt-log-n", "17"},
+			kdf:  KDF{Algorithm: KDFScrypt, ScryptLogN: 17, ScryptR: defaultScryptR, ScryptP: defaultScryptP},
+		},
+		"unknown KDF": {
+			args:    []string{"--kdf", "bcrypt"},
+			invalid: true,
+		},
+		"scrypt cost with argon2id": {
+			args:    []string{"--scrypt-r", "16"},
+			invalid: true,
+		},
+		"too large log2(N)": {
+			args:    []string{"--kdf", "scrypt", "--scrypt-log-n", "30"},
+			invalid: true,
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			var f KDFFlags
+			cmd := &cobra.Command{}
+			addKDFFlags(cmd, &f)
+			if !assert.NoError(t, cmd.ParseFlags(c.args)) {
+				return
+			}
+			kdf, err := f.KDF(cmd)
+			if c.invalid {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, c.kdf, kdf)
+		})
+	}
+}
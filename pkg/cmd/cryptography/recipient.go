@@ -0,0 +1,152 @@
+/*
+Copyright © 2022 tchiunam
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package cryptography
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"filippo.io/age"
+	"github.com/spf13/cobra"
+)
+
+// The data encrypted to recipients is in the format of age, see
+// https://age-encryption.org/v1. A random file key is wrapped with
+// X25519 for each recipient, so any one of their identities decrypts
+// it, and the data can be decrypted with the age tools as well.
+const ageMagic = "age-encryption.org/v1"
+
+// ErrIdentityRequired is returned when data encrypted to recipients is
+// decrypted with a passphrase
+var ErrIdentityRequired = errors.New("the input is encrypted to recipients, an identity file is required")
+
+// IsAge tells whether data starts with the header of age
+func IsAge(data []byte) bool {
+	return bytes.HasPrefix(data, []byte(ageMagic))
+}
+
+// RecipientFlags are the flags of the recipients to encrypt to
+type RecipientFlags struct {
+	Recipients     []string
+	RecipientsFile string
+}
+
+// addRecipientFlags adds the flags of the recipients to a command
+func addRecipientFlags(cmd *cobra.Command, f *RecipientFlags) {
+	cmd.Flags().StringArrayVarP(&f.Recipients, "recipient", "r", nil, "Public key of a recipient, e.g. age1... Can be repeated.")
+	cmd.Flags().StringVarP(&f.RecipientsFile, "recipients-file", "R", "", "File of the public keys of the recipients, one per line.")
+}
+
+// Given tells whether any recipient is given
+func (f RecipientFlags) Given() bool {
+	return len(f.Recipients) > 0 || f.RecipientsFile != ""
+}
+
+// Parse parses the recipients of the flags. The flags of the
+// passphrase, the chunk size and the KDF cannot be used with them.
+func (f RecipientFlags) Parse(cmd *cobra.Command) ([]age.Recipient, error) {
+	for _, name := range append([]string{"key-file", "chunk-size", "kdf"}, append(kdfCostFlags[KDFScrypt], kdfCostFlags[KDFArgon2id]...)...) {
+		if cmd.Flags().Changed(name) {
+			return nil, fmt.Errorf("--%s cannot be used with recipients", name)
+		}
+	}
+
+	var recipients []age.Recipient
+	for _, s := range f.Recipients {
+		r, err := age.ParseX25519Recipient(s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid recipient %s: %w", s, err)
+		}
+		recipients = append(recipients, r)
+	}
+	if f.RecipientsFile != "" {
+		file, err := os.Open(f.RecipientsFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read recipients file: %w", err)
+		}
+		defer file.Close()
+		rs, err := age.ParseRecipients(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse recipients file %s: %w", f.RecipientsFile, err)
+		}
+		recipients = append(recipients, rs...)
+	}
+
+	return recipients, nil
+}
+
+// readIdentities reads the identities, i.e. the private keys, from a file
+func readIdentities(identityFile string) ([]age.Identity, error) {
+	file, err := os.Open(identityFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read identity file: %w", err)
+	}
+	defer file.Close()
+	identities, err := age.ParseIdentities(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse identity file %s: %w", identityFile, err)
+	}
+
+	return identities, nil
+}
+
+// EncryptToRecipients encrypts the data read from r to the recipients
+// and writes it to w
+func EncryptToRecipients(w io.Writer, r io.Reader, recipients []age.Recipient) error {
+	if len(recipients) == 0 {
+		return errors.New("no recipient is given")
+	}
+	ew, err := age.Encrypt(w, recipients...)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt: %w", err)
+	}
+	if _, err := io.Copy(ew, r); err != nil {
+		return err
+	}
+
+	return ew.Close()
+}
+
+// DecryptWithIdentities decrypts the data read from r, which is
+// encrypted to recipients, with the identities and writes it to w
+func DecryptWithIdentities(w io.Writer, r io.Reader, identities []age.Identity) error {
+	br := bufio.NewReader(r)
+	magic, err := br.Peek(len(ageMagic))
+	if err != nil && err != io.EOF {
+		return err
+	}
+	if !IsAge(magic) {
+		return errors.New("the input is not encrypted to recipients, use the key file instead")
+	}
+	dr, err := age.Decrypt(br, identities...)
+	if err != nil {
+		return fmt.Errorf("failed to decrypt: %w", err)
+	}
+	_, err = io.Copy(w, dr)
+
+	return err
+}
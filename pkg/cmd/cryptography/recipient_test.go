@@ -0,0 +1,148 @@
+/*
+Copyright © 2022 tchiunam
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package cryptography
+
+import (
+	"bytes"
+	"crypto/rand"
+	"errors"
+	"io"
+	"path/filepath"
+	"testing"
+
+	"filippo.io/age"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestEncryptToRecipients tests that any one of the recipients decrypts the data
+func TestEncryptToRecipients(t *testing.T) {
+	var identities []*age.X25519Identity
+	var recipients []age.Recipient
+	for i := 0; i < 3; i++ {
+		identity, err := age.GenerateX25519Identity()
+		if !assert.NoError(t, err) {
+			return
+		}
+		identities = append(identities, identity)
+		recipients = append(recipients, identity.Recipient())
+	}
+	data := make([]byte, 100*1024)
+	rand.Read(data)
+	var encrypted bytes.Buffer
+	assert.NoError(t, EncryptToRecipients(&encrypted, bytes.NewReader(data), recipients))
+	assert.True(t, IsAge(encrypted.Bytes()))
+
+	for _, identity := range identities {
+		var buf bytes.Buffer
+		assert.NoError(t, DecryptWithIdentities(&buf, bytes.NewReader(encrypted.Bytes()), []age.Identity{identity}))
+		assert.Equal(t, data, buf.Bytes())
+	}
+
+	other, err := age.GenerateX25519Identity()
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Error(t, DecryptWithIdentities(io.Discard, bytes.NewReader(encrypted.Bytes()), []age.Identity{other}))
+	assert.True(t, errors.Is(DecryptStream(io.Discard, bytes.NewReader(encrypted.Bytes()), []byte("axolgo")), ErrIdentityRequired))
+	assert.Error(t, EncryptToRecipients(io.Discard, bytes.NewReader(data), nil))
+}
+
+// TestDecryptWithIdentitiesInvalid tests that DecryptWithIdentities rejects
+// the data which is not encrypted to recipients
+func TestDecryptWithIdentitiesInvalid(t *testing.T) {
+	identities, err := readIdentities(filepath.Join("testdata", "identity-test.key"))
+	if !assert.NoError(t, err) {
+		return
+	}
+	cases := map[string][]byte{
+		"empty":  {},
+		"stream": encryptTestStream(t, []byte("Hello World"), "axolgo"),
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			assert.Error(t, DecryptWithIdentities(io.Discard, bytes.NewReader(c), identities))
+		})
+	}
+}
+
+// TestRecipientFlags tests that the flags are parsed into recipients
+func TestRecipientFlags(t *testing.T) {
+	cases := map[string]struct {
+		args       []string
+		recipients int
+		invalid    bool
+	}{
+		"none": {},
+		"recipients": {
+			args: []string{
+				"--recipient", "age1ql3z7hjy54pw3hyww5ayyfg7zqgvc7w3j2elw8zmrj2kg5sfn9aqmcac8p",
+				"-r", "age1lggyhqrw2nlhcxprm67z43rta597azn8gknawjehu9d9dl0jq3yqqvfafg"},
+			recipients: 2,
+		},
+		"recipients file": {
+			args:       []string{"-R", filepath.Join("testdata", "recipients-test.txt"), "-r", "age1ql3z7hjy54pw3hyww5ayyfg7zqgvc7w3j2elw8zmrj2kg5sfn9aqmcac8p"},
+			recipients: 2,
+		},
+		"invalid recipient": {
+			args:    []string{"--recipient", "ssh-ed25519 AAAA"},
+			invalid: true,
+		},
+		"invalid recipients file": {
+			args:    []string{"--recipients-file", filepath.Join("testdata", "story.txt")},
+			invalid: true,
+		},
+		"key file": {
+			args:    []string{"-r", "age1ql3z7hjy54pw3hyww5ayyfg7zqgvc7w3j2elw8zmrj2kg5sfn9aqmcac8p", "--key-file", "secret.key"},
+			invalid: true,
+		},
+		"KDF cost": {
+			args:    []string{"-r", "age1ql3z7hjy54pw3hyww5ayyfg7zqgvc7w3j2elw8zmrj2kg5sfn9aqmcac8p", "--argon2-threads", "2"},
+			invalid: true,
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			var f RecipientFlags
+			var kdf KDFFlags
+			var keyFile string
+			cmd := &cobra.Command{}
+			cmd.Flags().StringVarP(&keyFile, "key-file", "k", "", "")
+			addKDFFlags(cmd, &kdf)
+			addRecipientFlags(cmd, &f)
+			if !assert.NoError(t, cmd.ParseFlags(c.args)) {
+				return
+			}
+			assert.Equal(t, len(c.args) > 0, f.Given())
+			recipients, err := f.Parse(cmd)
+			if c.invalid {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, c.recipients, len(recipients))
+		})
+	}
+}
@@ -0,0 +1,317 @@
+/*
+Copyright © 2022 tchiunam
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package cryptography
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/tchiunam/axolgo-lib/cryptography"
+)
+
+// An encrypted stream starts with a header, which records how the key
+// is derived from the passphrase. The plaintext is split into chunks
+// which are sealed one by one. The nonce of a chunk is its counter and
+// a flag of the final chunk, so chunks cannot be reordered, dropped or
+// appended and a truncated stream is detected. The header is
+// authenticated with every chunk.
+const (
+	tagSize = 16
+	// DefaultChunkSize is the default size of a plaintext chunk
+	DefaultChunkSize = 64 * 1024
+	minChunkSize     = 1024
+	maxChunkSize     = 16 * 1024 * 1024
+)
+
+// ErrTruncated is returned when an encrypted stream ends before its final chunk
+var ErrTruncated = errors.New("encrypted stream is truncated")
+
+// StreamConfig is the configuration to encrypt a stream
+type StreamConfig struct {
+	// ChunkSize is the size of a plaintext chunk in bytes
+	ChunkSize int
+	// KDF derives the key from the passphrase
+	KDF KDF
+}
+
+// IsStream tells whether data starts with the header of an encrypted stream
+func IsStream(data []byte) bool {
+	return bytes.HasPrefix(data, []byte(streamMagic))
+}
+
+// chunkNonce returns the nonce of a chunk, which is the big endian
+// counter followed by 1 for the final chunk or 0 for the others
+func chunkNonce(counter uint64, last bool) []byte {
+	nonce := make([]byte, 12)
+	binary.BigEndian.PutUint64(nonce[3:11], counter)
+	if last {
+		nonce[11] = 1
+	}
+
+	return nonce
+}
+
+// encryptWriter encrypts the data written to it as a stream
+type encryptWriter struct {
+	w       io.Writer
+	aead    cipher.AEAD
+	header  []byte
+	buf     []byte
+	out     []byte
+	counter uint64
+	err     error
+}
+
+// NewEncryptWriter returns a writer which encrypts the data written to
+// it with a key derived from the passphrase and writes the stream to w.
+// It holds one chunk in memory. Close must be called to write the final
+// chunk, and it does not close w.
+func NewEncryptWriter(w io.Writer, passphrase []byte, config StreamConfig) (io.WriteCloser, error) {
+	chunkSize := config.ChunkSize
+	if chunkSize < minChunkSize || chunkSize > maxChunkSize {
+		return nil, fmt.Errorf("invalid chunk size %d, must be %d to %d bytes", chunkSize, minChunkSize, maxChunkSize)
+	}
+	h := Header{
+		Cipher:    cipherAES256GCM,
+		ChunkSize: uint32(chunkSize),
+		KDF:       config.KDF,
+		Salt:      make([]byte, saltSize),
+	}
+	if _, err := io.ReadFull(rand.Reader, h.Salt); err != nil {
+		return nil, fmt.Errorf("failed to generate salt: %w", err)
+	}
+	header, err := h.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	aead, err := h.AEAD(passphrase)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := w.Write(header); err != nil {
+		return nil, err
+	}
+
+	return &encryptWriter{
+		w:      w,
+		aead:   aead,
+		header: header,
+		buf:    make([]byte, 0, chunkSize),
+		out:    make([]byte, 0, chunkSize+tagSize),
+	}, nil
+}
+
+// Write buffers the data and writes the full chunks. A full chunk is
+// held until more data is written because the final chunk is sealed
+// differently.
+func (e *encryptWriter) Write(p []byte) (int, error) {
+	if e.err != nil {
+		return 0, e.err
+	}
+	n := 0
+	for len(p) > 0 {
+		if len(e.buf) == cap(e.buf) {
+			if e.err = e.seal(false); e.err != nil {
+				return n, e.err
+			}
+		}
+		m := copy(e.buf[len(e.buf):cap(e.buf)], p)
+		e.buf = e.buf[:len(e.buf)+m]
+		p = p[m:]
+		n += m
+	}
+
+	return n, nil
+}
+
+// Close writes the final chunk
+func (e *encryptWriter) Close() error {
+	if e.err != nil {
+		return e.err
+	}
+	e.err = e.seal(true)
+	if e.err == nil {
+		e.err = errors.New("encrypted stream is closed")
+		return nil
+	}
+
+	return e.err
+}
+
+// seal encrypts and writes the buffered chunk
+func (e *encryptWriter) seal(last bool) error {
+	e.out = e.aead.Seal(e.out[:0], chunkNonce(e.counter, last), e.buf, e.header)
+	if _, err := e.w.Write(e.out); err != nil {
+		return err
+	}
+	e.buf = e.buf[:0]
+	e.counter++
+
+	return nil
+}
+
+// decryptReader decrypts a stream which is read from it
+type decryptReader struct {
+	r       *bufio.Reader
+	aead    cipher.AEAD
+	header  []byte
+	in      []byte
+	out     []byte
+	counter uint64
+	done    bool
+	err     error
+}
+
+// NewDecryptReader returns a reader which decrypts the stream read from
+// r with a key derived from the passphrase. It holds one chunk in
+// memory. The data of a chunk is returned only after the chunk is
+// authenticated, but the data before a corrupted chunk or a truncation
+// is returned before the error.
+func NewDecryptReader(r io.Reader, passphrase []byte) (io.Reader, error) {
+	h, header, err := ReadHeader(r)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := h.AEAD(passphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	return &decryptReader{
+		r:      bufio.NewReader(r),
+		aead:   aead,
+		header: header,
+		in:     make([]byte, int(h.ChunkSize)+tagSize),
+	}, nil
+}
+
+// Read returns the decrypted data
+func (d *decryptReader) Read(p []byte) (int, error) {
+	for len(d.out) == 0 {
+		if d.err != nil {
+			return 0, d.err
+		}
+		if d.done {
+			return 0, io.EOF
+		}
+		d.err = d.open()
+	}
+	n := copy(p, d.out)
+	d.out = d.out[n:]
+
+	return n, nil
+}
+
+// open reads and decrypts the next chunk. A chunk is the final one if
+// it is shorter than a full chunk or nothing follows it.
+func (d *decryptReader) open() error {
+	n, err := io.ReadFull(d.r, d.in)
+	last := false
+	switch {
+	case err == io.EOF:
+		return ErrTruncated
+	case err == io.ErrUnexpectedEOF:
+		if n < tagSize {
+			return ErrTruncated
+		}
+		last = true
+	case err != nil:
+		return err
+	default:
+		if _, err := d.r.Peek(1); err == io.EOF {
+			last = true
+		} else if err != nil {
+			return err
+		}
+	}
+
+	out, err := d.aead.Open(d.in[:0], chunkNonce(d.counter, last), d.in[:n], d.header)
+	if err != nil {
+		if d.counter == 0 {
+			return errors.New("failed to decrypt stream, the passphrase is wrong or the stream is corrupted")
+		}
+		return fmt.Errorf("failed to decrypt chunk %d, the stream is corrupted or truncated", d.counter)
+	}
+	// Only an empty stream has an empty final chunk
+	if last && len(out) == 0 && d.counter > 0 {
+		return ErrTruncated
+	}
+	d.out = out
+	d.counter++
+	d.done = last
+
+	return nil
+}
+
+// EncryptStream encrypts the data read from r as a stream to w
+func EncryptStream(w io.Writer, r io.Reader, passphrase []byte, config StreamConfig) error {
+	ew, err := NewEncryptWriter(w, passphrase, config)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(ew, r); err != nil {
+		return err
+	}
+
+	return ew.Close()
+}
+
+// DecryptStream decrypts an encrypted stream, or data of the former
+// format of axolgo-lib which is read as a whole, from r to w. Data
+// encrypted to recipients is not decrypted with a passphrase.
+func DecryptStream(w io.Writer, r io.Reader, passphrase []byte) error {
+	br := bufio.NewReader(r)
+	magic, err := br.Peek(len(ageMagic))
+	if err != nil && err != io.EOF {
+		return err
+	}
+	if IsAge(magic) {
+		return ErrIdentityRequired
+	}
+	if !IsStream(magic) {
+		data, err := io.ReadAll(br)
+		if err != nil {
+			return err
+		}
+		decrypted, err := cryptography.Decrypt(data, string(passphrase))
+		if err != nil {
+			return fmt.Errorf("failed to decrypt: %w", err)
+		}
+		_, err = w.Write(decrypted)
+		return err
+	}
+
+	dr, err := NewDecryptReader(br, passphrase)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(w, dr)
+
+	return err
+}
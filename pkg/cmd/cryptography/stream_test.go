@@ -0,0 +1,281 @@
+/*
+Copyright © 2022 tchiunam
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package cryptography
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tchiunam/axolgo-lib/cryptography"
+)
+
+// testStreamConfig is the min. chunk size and a cheap KDF to speed up the tests
+var testStreamConfig = StreamConfig{
+	ChunkSize: minChunkSize,
+	KDF:       KDF{Algorithm: KDFScrypt, ScryptLogN: 10, ScryptR: 8, ScryptP: 1},
+}
+
+// testHeaderSize is the size of the header of testStreamConfig
+const testHeaderSize = len(streamMagic) + 1 + 1 + 4 + 1 + 9 + saltSize
+
+// encryptTestStream encrypts data with testStreamConfig
+func encryptTestStream(t *testing.T, data []byte, passphrase string) []byte {
+	var buf bytes.Buffer
+	assert.NoError(t, EncryptStream(&buf, bytes.NewReader(data), []byte(passphrase), testStreamConfig))
+
+	return buf.Bytes()
+}
+
+// TestEncryptStream tests that EncryptStream and DecryptStream round trip
+func TestEncryptStream(t *testing.T) {
+	cases := map[string]struct {
+		size   int
+		chunks int
+	}{
+		"empty": {
+			size:   0,
+			chunks: 1,
+		},
+		"one byte": {
+			size:   1,
+			chunks: 1,
+		},
+		"less than a chunk": {
+			size:   minChunkSize - 1,
+			chunks: 1,
+		},
+		"one chunk": {
+			size:   minChunkSize,
+			chunks: 1,
+		},
+		"more than a chunk": {
+			size:   minChunkSize + 1,
+			chunks: 2,
+		},
+		"many chunks": {
+			size:   3 * minChunkSize,
+			chunks: 3,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			data := make([]byte, tc.size)
+			rand.Read(data)
+			encrypted := encryptTestStream(t, data, "axolgo")
+			assert.True(t, IsStream(encrypted))
+			assert.Equal(t, testHeaderSize+tc.size+tc.chunks*tagSize, len(encrypted))
+
+			var buf bytes.Buffer
+			assert.NoError(t, DecryptStream(&buf, bytes.NewReader(encrypted), []byte("axolgo")))
+			assert.Equal(t, data, buf.Bytes())
+		})
+	}
+}
+
+// TestEncryptStreamSmallWrites tests that the chunks do not depend on the size of the writes
+func TestEncryptStreamSmallWrites(t *testing.T) {
+	data := make([]byte, 2*minChunkSize+100)
+	rand.Read(data)
+	var buf bytes.Buffer
+	w, err := NewEncryptWriter(&buf, []byte("axolgo"), testStreamConfig)
+	if !assert.NoError(t, err) {
+		return
+	}
+	for i := 0; i < len(data); i += 7 {
+		end := i + 7
+		if end > len(data) {
+			end = len(data)
+		}
+		_, err := w.Write(data[i:end])
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, w.Close())
+	_, err = w.Write([]byte("more"))
+	assert.Error(t, err)
+
+	r, err := NewDecryptReader(&buf, []byte("axolgo"))
+	if !assert.NoError(t, err) {
+		return
+	}
+	decrypted, err := io.ReadAll(r)
+	assert.NoError(t, err)
+	assert.Equal(t, data, decrypted)
+}
+
+// TestDecryptStreamInvalid tests that DecryptStream detects a tampered stream
+func TestDecryptStreamInvalid(t *testing.T) {
+	data := make([]byte, 3*minChunkSize)
+	rand.Read(data)
+	encrypted := encryptTestStream(t, data, "axolgo")
+	chunk := minChunkSize + tagSize
+	chunks := encrypted[testHeaderSize:]
+	cases := map[string]struct {
+		stream     []byte
+		passphrase string
+		truncated  bool
+	}{
+		"wrong passphrase": {
+			stream:     encrypted,
+			passphrase: "axolotl",
+		},
+		"truncated at a chunk": {
+			stream:     encrypted[:testHeaderSize+2*chunk],
+			passphrase: "axolgo",
+		},
+		"truncated in a chunk": {
+			stream:     encrypted[:len(encrypted)-1],
+			passphrase: "axolgo",
+		},
+		"no chunk": {
+			stream:     encrypted[:testHeaderSize],
+			passphrase: "axolgo",
+			truncated:  true,
+		},
+		"appended": {
+			stream:     append(append([]byte{}, encrypted...), chunks[:chunk]...),
+			passphrase: "axolgo",
+		},
+		"reordered": {
+			stream:     bytes.Join([][]byte{encrypted[:testHeaderSize], chunks[chunk : 2*chunk], chunks[:chunk], chunks[2*chunk:]}, nil),
+			passphrase: "axolgo",
+		},
+		"modified": {
+			stream: func() []byte {
+				b := append([]byte{}, encrypted...)
+				b[testHeaderSize+chunk+10] ^= 1
+				return b
+			}(),
+			passphrase: "axolgo",
+		},
+		"modified header": {
+			stream: func() []byte {
+				b := append([]byte{}, encrypted...)
+				b[testHeaderSize-1] ^= 1
+				return b
+			}(),
+			passphrase: "axolgo",
+		},
+		"unsupported version": {
+			stream: func() []byte {
+				b := append([]byte{}, encrypted...)
+				b[len(streamMagic)] = 99
+				return b
+			}(),
+			passphrase: "axolgo",
+		},
+		"unsupported cipher": {
+			stream: func() []byte {
+				b := append([]byte{}, encrypted...)
+				b[len(streamMagic)+1] = 99
+				return b
+			}(),
+			passphrase: "axolgo",
+		},
+		"invalid chunk size": {
+			stream: func() []byte {
+				b := append([]byte{}, encrypted...)
+				binary.BigEndian.PutUint32(b[len(streamMagic)+2:], 1<<30)
+				return b
+			}(),
+			passphrase: "axolgo",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := DecryptStream(io.Discard, bytes.NewReader(tc.stream), []byte(tc.passphrase))
+			assert.Error(t, err)
+			if tc.truncated {
+				assert.True(t, errors.Is(err, ErrTruncated))
+			}
+		})
+	}
+}
+
+// TestDecryptStreamLegacy tests that DecryptStream decrypts the former format
+func TestDecryptStreamLegacy(t *testing.T) {
+	encrypted, err := cryptography.Encrypt([]byte("Hello World"), "axolgo")
+	if !assert.NoError(t, err) {
+		return
+	}
+	var buf bytes.Buffer
+	assert.NoError(t, DecryptStream(&buf, bytes.NewReader(encrypted), []byte("axolgo")))
+	assert.Equal(t, "Hello World", buf.String())
+}
+
+// TestDecryptStreamVersion1 tests that DecryptStream decrypts a stream
+// of version 1, whose key is derived by scrypt of the default costs
+func TestDecryptStreamVersion1(t *testing.T) {
+	header := make([]byte, len(streamMagic)+1+4+saltSize)
+	copy(header, streamMagic)
+	header[len(streamMagic)] = streamVersion1
+	binary.BigEndian.PutUint32(header[len(streamMagic)+1:], minChunkSize)
+	salt := header[len(header)-saltSize:]
+	rand.Read(salt)
+	kdf, _ := DefaultKDF(KDFScrypt)
+	aead, err := Header{KDF: kdf, Salt: salt}.AEAD([]byte("axolgo"))
+	if !assert.NoError(t, err) {
+		return
+	}
+	data := make([]byte, minChunkSize+100)
+	rand.Read(data)
+	encrypted := aead.Seal(append([]byte{}, header...), chunkNonce(0, false), data[:minChunkSize], header)
+	encrypted = aead.Seal(encrypted, chunkNonce(1, true), data[minChunkSize:], header)
+
+	var buf bytes.Buffer
+	assert.NoError(t, DecryptStream(&buf, bytes.NewReader(encrypted), []byte("axolgo")))
+	assert.Equal(t, data, buf.Bytes())
+}
+
+// TestNewEncryptWriterInvalid tests NewEncryptWriter with invalid configurations
+func TestNewEncryptWriterInvalid(t *testing.T) {
+	cases := map[string]StreamConfig{
+		"zero chunk size": {
+			KDF: testStreamConfig.KDF,
+		},
+		"too small chunk size": {
+			ChunkSize: minChunkSize - 1,
+			KDF:       testStreamConfig.KDF,
+		},
+		"too large chunk size": {
+			ChunkSize: maxChunkSize + 1,
+			KDF:       testStreamConfig.KDF,
+		},
+		"no KDF": {
+			ChunkSize: minChunkSize,
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := NewEncryptWriter(io.Discard, []byte("axolgo"), c)
+			assert.Error(t, err)
+		})
+	}
+}
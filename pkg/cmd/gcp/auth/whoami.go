@@ -0,0 +1,210 @@
+/*
+Copyright © 2022 tchiunam
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	"cloud.google.com/go/compute/metadata"
+	"github.com/spf13/cobra"
+	"github.com/tchiunam/axolgo-cli/pkg/util"
+	"k8s.io/klog/v2"
+)
+
+var (
+	whoamiLong = `Show the effective principal of the GCP commands. The credentials are
+read from the credentials file in axolgo configuration. If it is not
+set, Application Default Credentials are used, which are the
+credentials of gcloud auth application-default login or of the
+metadata server.
+
+If a service account is impersonated, the effective principal is the
+service account and a token is requested through the delegation chain
+to verify it.
+`
+	whoamiExample = `  # Show the effective principal
+  axolgo gcp auth whoami
+
+  # Verify the impersonation of a service account through a delegation chain
+  axolgo gcp auth whoami --impersonate-service-account a@proj1.iam.gserviceaccount.com,deployer@proj2.iam.gserviceaccount.com
+`
+)
+
+// The endpoint to look up the principal of an access token
+var tokenInfoURL = "https://oauth2.googleapis.com/tokeninfo"
+
+// WhoamiOptions defines flags and other configuration parameters for the `whoami` command
+type WhoamiOptions struct {
+	Output string
+}
+
+// NewCmdWhoami creates the `whoami` command
+func NewCmdWhoami(ctx *context.Context) *cobra.Command {
+	o := WhoamiOptions{}
+
+	cmd := &cobra.Command{
+		Use:                   "whoami [-o]",
+		DisableFlagsInUseLine: true,
+		Short:                 "Show the effective principal.",
+		Long:                  whoamiLong,
+		Example:               whoamiExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := o.complete(ctx, cmd, args); err != nil {
+				panic(err)
+			}
+		},
+	}
+
+	cmd.Flags().StringVarP(&o.Output, "output", "o", util.OutputTable, fmt.Sprintf("Output format. One of %v.", util.OutputFormats))
+
+	return cmd
+}
+
+// Complete takes the command arguments and execute.
+func (o *WhoamiOptions) complete(ctx *context.Context, _ *cobra.Command, args []string) error {
+	auth := util.LoadGCPAuth()
+	creds, err := auth.Credentials(context.TODO())
+	if err != nil {
+		return err
+	}
+
+	credentialsType := CredentialsType(creds.JSON)
+	source := auth.CredentialsFile
+	if source == "" {
+		source = "application default credentials"
+		if file := os.Getenv("GOOGLE_APPLICATION_CREDENTIALS"); file != "" {
+			source += " (" + file + ")"
+		}
+	}
+
+	principal := CredentialsEmail(creds.JSON)
+	if principal == "" && credentialsType == "compute_metadata" {
+		if principal, err = metadata.Email("default"); err != nil {
+			return fmt.Errorf("failed to get the service account of the metadata server: %w", err)
+		}
+	}
+	if principal == "" {
+		token, err := creds.TokenSource.Token()
+		if err != nil {
+			return fmt.Errorf("failed to get a token: %w", err)
+		}
+		if principal, err = TokenInfoEmail(context.TODO(), http.DefaultClient, token.AccessToken); err != nil {
+			return err
+		}
+	}
+
+	effective := principal
+	if auth.ImpersonateServiceAccount != "" {
+		ts, err := auth.TokenSource(context.TODO())
+		if err != nil {
+			return err
+		}
+		if _, err := ts.Token(); err != nil {
+			return fmt.Errorf("failed to impersonate service account %s: %w", auth.ImpersonateServiceAccount, err)
+		}
+		effective = auth.ImpersonateServiceAccount
+	}
+	klog.V(3).InfoS("Principal", "effective", effective, "credentials", principal)
+
+	delegates := "-"
+	if len(auth.Delegates) > 0 {
+		delegates = strings.Join(auth.Delegates, ",")
+	}
+
+	return util.PrintRecords(
+		os.Stdout,
+		o.Output,
+		[]string{"PRINCIPAL", "CREDENTIALS PRINCIPAL", "CREDENTIALS TYPE", "CREDENTIALS SOURCE", "DELEGATES"},
+		[][]string{{effective, principal, credentialsType, source, delegates}},
+	)
+}
+
+// CredentialsType returns the type of credentials JSON. Credentials
+// without JSON are from the metadata server.
+func CredentialsType(data []byte) string {
+	if len(data) == 0 {
+		return "compute_metadata"
+	}
+	var creds struct {
+		Type string `json:"type"`
+	}
+	if err := json.Unmarshal(data, &creds); err != nil || creds.Type == "" {
+		return "unknown"
+	}
+
+	return creds.Type
+}
+
+// CredentialsEmail returns the client email of credentials JSON, which
+// is only available for service account keys
+func CredentialsEmail(data []byte) string {
+	var creds struct {
+		ClientEmail string `json:"client_email"`
+	}
+	if len(data) == 0 || json.Unmarshal(data, &creds) != nil {
+		return ""
+	}
+
+	return creds.ClientEmail
+}
+
+// TokenInfoEmail looks up the email of the principal of an access token
+func TokenInfoEmail(ctx context.Context, client *http.Client, accessToken string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, tokenInfoURL, strings.NewReader(url.Values{"access_token": {accessToken}}.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to look up token info: %w", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to look up token info: %s", resp.Status)
+	}
+
+	var info struct {
+		Email string `json:"email"`
+	}
+	if err := json.Unmarshal(body, &info); err != nil {
+		return "", fmt.Errorf("failed to parse token info: %w", err)
+	}
+	if info.Email == "" {
+		return "", fmt.Errorf("token info has no email, the credentials may lack the userinfo.email scope")
+	}
+
+	return info.Email, nil
+}
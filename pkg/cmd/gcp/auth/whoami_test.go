@@ -0,0 +1,139 @@
+/*
+Copyright © 2022 tchiunam
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package auth
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestNewCmdWhoami tests the NewCmdWhoami function
+// to make sure it returns a valid command.
+func TestNewCmdWhoami(t *testing.T) {
+	cases := map[string]struct {
+		use      string
+		short    string
+		hasFlags bool
+	}{
+		"valid command": {
+			use:      "whoami [-o]",
+			short:    "Show the effective principal.",
+			hasFlags: true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cmd := NewCmdWhoami(nil)
+			assert.Equal(t, tc.use, cmd.Use)
+			assert.Equal(t, tc.short, cmd.Short)
+			assert.Equal(t, tc.hasFlags, cmd.Flags().HasFlags())
+		})
+	}
+}
+
+// TestCredentialsTypeAndEmail tests the CredentialsType and CredentialsEmail functions
+func TestCredentialsTypeAndEmail(t *testing.T) {
+	cases := map[string]struct {
+		json            string
+		credentialsType string
+		email           string
+	}{
+		"service account": {
+			json:            `{"type": "service_account", "client_email": "axolgo@proj1.iam.gserviceaccount.com"}`,
+			credentialsType: "service_account",
+			email:           "axolgo@proj1.iam.gserviceaccount.com",
+		},
+		"authorized user": {
+			json:            `{"type": "authorized_user", "client_id": "123.apps.googleusercontent.com"}`,
+			credentialsType: "authorized_user",
+		},
+		"metadata server": {
+			json:            "",
+			credentialsType: "compute_metadata",
+		},
+		"invalid json": {
+			json:            "{",
+			credentialsType: "unknown",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.credentialsType, CredentialsType([]byte(tc.json)))
+			assert.Equal(t, tc.email, CredentialsEmail([]byte(tc.json)))
+		})
+	}
+}
+
+// TestTokenInfoEmail tests the TokenInfoEmail function
+func TestTokenInfoEmail(t *testing.T) {
+	cases := map[string]struct {
+		status int
+		body   string
+		email  string
+		valid  bool
+	}{
+		"valid token": {
+			status: http.StatusOK,
+			body:   `{"email": "engineer@example.com", "expires_in": "3599"}`,
+			email:  "engineer@example.com",
+			valid:  true,
+		},
+		"no email": {
+			status: http.StatusOK,
+			body:   `{"expires_in": "3599"}`,
+		},
+		"invalid token": {
+			status: http.StatusBadRequest,
+			body:   `{"error_description": "Invalid Value"}`,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.NoError(t, r.ParseForm())
+				assert.Equal(t, "token", r.PostForm.Get("access_token"))
+				w.WriteHeader(tc.status)
+				w.Write([]byte(tc.body))
+			}))
+			defer server.Close()
+			oldURL := tokenInfoURL
+			defer func() { tokenInfoURL = oldURL }()
+			tokenInfoURL = server.URL
+
+			email, err := TokenInfoEmail(context.TODO(), server.Client(), "token")
+			if tc.valid {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.email, email)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,409 @@
+/*
+Copyright © 2022 tchiunam
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package compute
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	compute "cloud.google.com/go/compute/apiv1"
+	"cloud.google.com/go/compute/apiv1/computepb"
+	"github.com/spf13/cobra"
+	"github.com/tchiunam/axolgo-cli/pkg/util"
+	"google.golang.org/protobuf/proto"
+	"k8s.io/klog/v2"
+)
+
+// The kinds of resources which are labelled
+const (
+	labelKindInstance = "instance"
+	labelKindDisk     = "disk"
+)
+
+var (
+	applyLabelsLong = `Set and remove the labels of the compute engine instances which are
+selected by a label file. The selector of the file has the same
+criteria as the filter flags of listInstances, and is required. The
+other labels of the instances are kept.
+
+  selector:
+    names: [web-1, web-2]
+    labels: [env=prod]
+  set:
+    team: web
+  remove: [owner]
+
+With --propagate-to-disks, the labels of an instance after the change
+are also set on its attached disks, and the removed labels are removed
+from them.
+
+The changes are printed as a diff and a confirmation is asked unless
+--yes is given. A label change is based on the label fingerprint of
+the resource. If the labels are changed by others at the same time, it
+is retried with the latest labels.
+`
+	applyLabelsExample = `  # Print the label changes of a label file without applying them
+  axolgo gcp compute applyLabels -f labels.yaml --project proj1 --all-zones --dry-run
+
+  # Apply a label file to the instances and their disks
+  axolgo gcp compute applyLabels -f labels.yaml --project proj1 --zone asia-east1-a --propagate-to-disks --yes
+`
+)
+
+// ApplyLabelsOptions defines flags and other configuration parameters for the `applyLabels` command
+type ApplyLabelsOptions struct {
+	Project          string
+	Zone             string
+	AllZones         bool
+	LabelFile        string
+	PropagateToDisks bool
+	DryRun           bool
+	Yes              bool
+	Retries          int
+	Wait             time.Duration
+	Concurrency      int
+}
+
+// NewCmdApplyLabels creates the `applyLabels` command
+func NewCmdApplyLabels(ctx *context.Context) *cobra.Command {
+	o := ApplyLabelsOptions{}
+
+	cmd := &cobra.Command{
+		Use:                   "applyLabels -f FILENAME [-p] [-z] [--all-zones] [--propagate-to-disks] [--dry-run] [-y] [--retries] [--wait] [--concurrency]",
+		DisableFlagsInUseLine: true,
+		Short:                 "Set and remove the labels of instances from a label file.",
+		Long:                  applyLabelsLong,
+		Example:               applyLabelsExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := o.complete(ctx, cmd, args); err != nil {
+				panic(err)
+			}
+		},
+	}
+
+	cmd.Flags().StringVarP(&o.LabelFile, "label-file", "f", "", "The file that selects instances and contains labels.")
+	cmd.Flags().StringVarP(&o.Project, "project", "p", "", "Project ID. Default is the project in axolgo configuration.")
+	cmd.Flags().StringVarP(&o.Zone, "zone", "z", "", "Zone.")
+	cmd.Flags().BoolVar(&o.AllZones, "all-zones", false, "Select the instances in all zones.")
+	cmd.Flags().BoolVar(&o.PropagateToDisks, "propagate-to-disks", false, "Set the labels of the instances on their attached disks.")
+	cmd.Flags().BoolVar(&o.DryRun, "dry-run", false, "Print the changes without applying them.")
+	cmd.Flags().BoolVarP(&o.Yes, "yes", "y", false, "Do not ask for confirmation.")
+	cmd.Flags().IntVar(&o.Retries, "retries", 3, "Max. no. of retries of a resource whose labels are changed by others.")
+	cmd.Flags().DurationVar(&o.Wait, "wait", 2*time.Minute, "Max. time to wait for a label change to be done. 0 is not to wait.")
+	cmd.Flags().IntVar(&o.Concurrency, "concurrency", 4, "Max. no. of instances to label at the same time.")
+	cmd.MarkFlagRequired("label-file")
+
+	return cmd
+}
+
+// Complete takes the command arguments and execute.
+func (o *ApplyLabelsOptions) complete(ctx *context.Context, _ *cobra.Command, args []string) error {
+	if o.AllZones && o.Zone != "" {
+		return fmt.Errorf("--zone cannot be used with --all-zones")
+	}
+	if o.Concurrency < 1 {
+		return fmt.Errorf("invalid concurrency: %d", o.Concurrency)
+	}
+	if o.Retries < 0 {
+		return fmt.Errorf("invalid retries: %d", o.Retries)
+	}
+	if o.Wait < 0 {
+		return fmt.Errorf("invalid wait: %s", o.Wait)
+	}
+	file, err := ReadLabelFile(o.LabelFile)
+	if err != nil {
+		return err
+	}
+	filter := file.Selector.InstanceFilter()
+	f, err := filter.String()
+	if err != nil {
+		return err
+	}
+	// Never label all the instances of a zone by accident
+	if f == "" {
+		return fmt.Errorf("a selector is required to select the instances to label")
+	}
+
+	project, err := util.GCPProject(o.Project)
+	if err != nil {
+		return err
+	}
+	zone := ""
+	if !o.AllZones {
+		zone = util.GCPZone(o.Zone)
+	}
+
+	opts, err := util.GCPClientOptions(context.TODO())
+	if err != nil {
+		return err
+	}
+	ic, err := newInstancesClient(context.TODO())
+	if err != nil {
+		return err
+	}
+	defer ic.Close()
+	var dc *compute.DisksClient
+	var rc *compute.RegionDisksClient
+	if o.PropagateToDisks {
+		if dc, err = compute.NewDisksRESTClient(context.TODO(), opts...); err != nil {
+			return fmt.Errorf("failed to create disk client: %w", err)
+		}
+		defer dc.Close()
+		if rc, err = compute.NewRegionDisksRESTClient(context.TODO(), opts...); err != nil {
+			return fmt.Errorf("failed to create regional disk client: %w", err)
+		}
+		defer rc.Close()
+	}
+
+	instances, err := ListInstances(context.TODO(), ic, project, zone, f, 0, o.AllZones)
+	if err != nil {
+		return fmt.Errorf("failed to list compute engine instances: %w", err)
+	}
+	if len(instances) == 0 {
+		klog.Infof("No instance matches the selector: %s", f)
+		return nil
+	}
+
+	var getDisk func(ctx context.Context, path string) (map[string]string, error)
+	if o.PropagateToDisks {
+		getDisk = func(ctx context.Context, path string) (map[string]string, error) {
+			labels, _, err := diskLabelGetter(dc, rc, path)(ctx)
+			return labels, err
+		}
+	}
+	plans, err := PlanInstanceLabels(context.TODO(), instances, file, getDisk)
+	if err != nil {
+		return err
+	}
+	if len(plans) == 0 {
+		klog.Infof("The labels of %d instance(s) are up to date", len(instances))
+		return nil
+	}
+	if err := util.PrintTable(os.Stdout, labelPlanHeader, labelPlanRows(plans)); err != nil {
+		return err
+	}
+
+	if o.DryRun {
+		klog.Infof("Dry run: the labels of %d resource(s) would be changed", len(plans))
+		return nil
+	}
+	if !o.Yes {
+		confirmed, err := util.Confirm(os.Stdin, os.Stdout, fmt.Sprintf("Do you want to change the labels of %d resource(s)?", len(plans)))
+		if err != nil {
+			return err
+		}
+		if !confirmed {
+			klog.Info("Cancelled")
+			return nil
+		}
+	}
+
+	errs := RunInstanceActions(context.TODO(), instances, o.Concurrency, func(ctx context.Context, i InstanceInfo) error {
+		var failures []string
+		for _, p := range plans {
+			if p.Instance.Project != i.Project || p.Instance.Zone != i.Zone || p.Instance.Name != i.Name {
+				continue
+			}
+			if err := o.apply(ctx, ic, dc, rc, p, file.Remove); err != nil {
+				klog.Errorf("Failed to change the labels of %s %s: %v", p.Kind, p.Name, err)
+				failures = append(failures, p.Kind+" "+p.Name)
+			}
+		}
+		if len(failures) > 0 {
+			return fmt.Errorf("labels of %s", strings.Join(failures, ", "))
+		}
+		return nil
+	})
+	var failures []string
+	for i, err := range errs {
+		if err != nil {
+			failures = append(failures, instances[i].Name)
+		}
+	}
+	if len(failures) > 0 {
+		return fmt.Errorf("failed to change the labels of instance(s): %s", strings.Join(failures, ", "))
+	}
+
+	return nil
+}
+
+// apply changes the labels of an instance or a disk. The changes are
+// planned again on the latest labels, so they may differ from the plan.
+func (o *ApplyLabelsOptions) apply(ctx context.Context, ic *compute.InstancesClient, dc *compute.DisksClient, rc *compute.RegionDisksClient, p LabelPlan, remove []string) error {
+	var get LabelGetter
+	var set LabelSetter
+	if p.Kind == labelKindInstance {
+		get = func(ctx context.Context) (map[string]string, string, error) {
+			instance, err := ic.Get(ctx, &computepb.GetInstanceRequest{Project: p.Project, Zone: p.Location, Instance: p.Name})
+			if err != nil {
+				return nil, "", err
+			}
+			return instance.GetLabels(), instance.GetLabelFingerprint(), nil
+		}
+		set = func(ctx context.Context, labels map[string]string, fingerprint string) error {
+			op, err := ic.SetLabels(ctx, &computepb.SetLabelsInstanceRequest{
+				Project:  p.Project,
+				Zone:     p.Location,
+				Instance: p.Name,
+				InstancesSetLabelsRequestResource: &computepb.InstancesSetLabelsRequest{
+					Labels:           labels,
+					LabelFingerprint: proto.String(fingerprint),
+				},
+			})
+			return o.wait(ctx, op, err)
+		}
+	} else {
+		get = diskLabelGetter(dc, rc, p.Path)
+		set = func(ctx context.Context, labels map[string]string, fingerprint string) error {
+			project, location, disk, regional, err := diskLocation(p.Path)
+			if err != nil {
+				return err
+			}
+			var op *compute.Operation
+			if regional {
+				op, err = rc.SetLabels(ctx, &computepb.SetLabelsRegionDiskRequest{
+					Project:  project,
+					Region:   location,
+					Resource: disk,
+					RegionSetLabelsRequestResource: &computepb.RegionSetLabelsRequest{
+						Labels:           labels,
+						LabelFingerprint: proto.String(fingerprint),
+					},
+				})
+			} else {
+				op, err = dc.SetLabels(ctx, &computepb.SetLabelsDiskRequest{
+					Project:  project,
+					Zone:     location,
+					Resource: disk,
+					ZoneSetLabelsRequestResource: &computepb.ZoneSetLabelsRequest{
+						Labels:           labels,
+						LabelFingerprint: proto.String(fingerprint),
+					},
+				})
+			}
+			return o.wait(ctx, op, err)
+		}
+	}
+
+	changes, err := SetLabels(ctx, get, set, p.Labels, remove, o.Retries, time.Second)
+	if err != nil {
+		return err
+	}
+	if len(changes) == 0 {
+		klog.Infof("The labels of %s %s are up to date", p.Kind, p.Name)
+		return nil
+	}
+	klog.Infof("Changed %d label(s) of %s %s", len(changes), p.Kind, p.Name)
+
+	return nil
+}
+
+// wait waits for the operation of a label change unless --wait is 0
+func (o *ApplyLabelsOptions) wait(ctx context.Context, op *compute.Operation, err error) error {
+	if err != nil || o.Wait == 0 {
+		return err
+	}
+
+	return waitOperation(ctx, op, o.Wait)
+}
+
+// diskLabelGetter returns a getter of the labels of a zonal or regional disk
+func diskLabelGetter(dc *compute.DisksClient, rc *compute.RegionDisksClient, path string) LabelGetter {
+	return func(ctx context.Context) (map[string]string, string, error) {
+		project, location, name, regional, err := diskLocation(path)
+		if err != nil {
+			return nil, "", err
+		}
+		var disk *computepb.Disk
+		if regional {
+			disk, err = rc.Get(ctx, &computepb.GetRegionDiskRequest{Project: project, Region: location, Disk: name})
+		} else {
+			disk, err = dc.Get(ctx, &computepb.GetDiskRequest{Project: project, Zone: location, Disk: name})
+		}
+		if err != nil {
+			return nil, "", err
+		}
+		return disk.GetLabels(), disk.GetLabelFingerprint(), nil
+	}
+}
+
+// PlanInstanceLabels returns the label changes of the instances of a
+// label file. If getDisk is given, the labels of each instance after
+// the change are propagated to its disks, whose labels are read with
+// getDisk. A disk which is attached to more than one instance takes the
+// labels of the first one. Resources without changes are skipped.
+func PlanInstanceLabels(ctx context.Context, instances []InstanceInfo, file LabelFile, getDisk func(context.Context, string) (map[string]string, error)) ([]LabelPlan, error) {
+	var plans []LabelPlan
+	seen := map[string]string{}
+	for _, i := range instances {
+		changes := PlanLabels(i.Labels, file.Set, file.Remove)
+		if len(changes) > 0 {
+			plans = append(plans, LabelPlan{
+				Instance: i,
+				Kind:     labelKindInstance,
+				Project:  i.Project,
+				Location: i.Zone,
+				Name:     i.Name,
+				Changes:  changes,
+				Labels:   file.Set,
+			})
+		}
+		if getDisk == nil {
+			continue
+		}
+
+		labels := ApplyLabelChanges(i.Labels, changes)
+		for _, d := range i.Disks {
+			if owner, ok := seen[d]; ok {
+				klog.Warningf("Disk %s is attached to %s and %s, the labels of %s are used", lastSegment(d), owner, i.Name, owner)
+				continue
+			}
+			seen[d] = i.Name
+			project, location, name, _, err := diskLocation(d)
+			if err != nil {
+				return nil, err
+			}
+			current, err := getDisk(ctx, d)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get disk %s: %w", name, err)
+			}
+			if changes := PlanLabels(current, labels, file.Remove); len(changes) > 0 {
+				plans = append(plans, LabelPlan{
+					Instance: i,
+					Kind:     labelKindDisk,
+					Project:  project,
+					Location: location,
+					Name:     name,
+					Path:     d,
+					Changes:  changes,
+					Labels:   labels,
+				})
+			}
+		}
+	}
+
+	return plans, nil
+}
@@ -0,0 +1,160 @@
+/*
+Copyright © 2022 tchiunam
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package compute
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestNewCmdApplyLabels tests the NewCmdApplyLabels function
+// to make sure it returns a valid command.
+func TestNewCmdApplyLabels(t *testing.T) {
+	cases := map[string]struct {
+		use      string
+		short    string
+		hasFlags bool
+	}{
+		"valid command": {
+			use:      "applyLabels -f FILENAME [-p] [-z] [--all-zones] [--propagate-to-disks] [--dry-run] [-y] [--retries] [--wait] [--concurrency]",
+			short:    "Set and remove the labels of instances from a label file.",
+			hasFlags: true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cmd := NewCmdApplyLabels(nil)
+			assert.Equal(t, tc.use, cmd.Use)
+			assert.Equal(t, tc.short, cmd.Short)
+			assert.Equal(t, tc.hasFlags, cmd.Flags().HasFlags())
+		})
+	}
+}
+
+// TestNewCmdApplyLabelsInvalid calls the NewCmdApplyLabels function
+// with invalid input and makes sure it returns an error.
+func TestNewCmdApplyLabelsInvalid(t *testing.T) {
+	cases := map[string]struct {
+		args []string
+	}{
+		"zone with all zones": {
+			args: []string{"-f", "labels.yaml", "--project", "proj1", "--zone", "asia-east1-a", "--all-zones"},
+		},
+		"invalid retries": {
+			args: []string{"-f", "labels.yaml", "--project", "proj1", "--retries", "-1"},
+		},
+		"missing label file": {
+			args: []string{"-f", "/nonexistent/labels.yaml", "--project", "proj1"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			oldArgs := os.Args
+			defer func() { os.Args = oldArgs }()
+			os.Args = append([]string{"cmd"}, tc.args...)
+
+			cmd := NewCmdApplyLabels(nil)
+			assert.Panics(t, func() { cmd.Execute() })
+		})
+	}
+}
+
+// TestPlanInstanceLabels tests the PlanInstanceLabels function
+func TestPlanInstanceLabels(t *testing.T) {
+	instances := []InstanceInfo{
+		{
+			Project: "proj1",
+			Zone:    "asia-east1-a",
+			Name:    "web-1",
+			Labels:  map[string]string{"env": "prod", "owner": "alice"},
+			Disks:   []string{"projects/proj1/zones/asia-east1-a/disks/web-1", "projects/proj1/regions/asia-east1/disks/shared"},
+		},
+		{
+			Project: "proj1",
+			Zone:    "asia-east1-b",
+			Name:    "web-2",
+			Labels:  map[string]string{"env": "prod", "team": "web"},
+			Disks:   []string{"projects/proj1/zones/asia-east1-b/disks/web-2", "projects/proj1/regions/asia-east1/disks/shared"},
+		},
+	}
+	disks := map[string]map[string]string{
+		"projects/proj1/zones/asia-east1-a/disks/web-1":  {"owner": "alice"},
+		"projects/proj1/regions/asia-east1/disks/shared": {"env": "prod", "team": "web"},
+		"projects/proj1/zones/asia-east1-b/disks/web-2":  {"env": "prod", "team": "web"},
+	}
+	file := LabelFile{Set: map[string]string{"team": "web"}, Remove: []string{"owner"}}
+	cases := map[string]struct {
+		getDisk func(context.Context, string) (map[string]string, error)
+		plans   []string
+		valid   bool
+	}{
+		"instances only": {
+			plans: []string{"instance web-1"},
+			valid: true,
+		},
+		"propagate to disks": {
+			getDisk: func(_ context.Context, path string) (map[string]string, error) {
+				return disks[path], nil
+			},
+			plans: []string{"instance web-1", "disk web-1"},
+			valid: true,
+		},
+		"disk error": {
+			getDisk: func(context.Context, string) (map[string]string, error) {
+				return nil, errors.New("not found")
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			plans, err := PlanInstanceLabels(context.TODO(), instances, file, tc.getDisk)
+			if !tc.valid {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			var names []string
+			for _, p := range plans {
+				names = append(names, p.Kind+" "+p.Name)
+			}
+			assert.Equal(t, tc.plans, names)
+			if len(plans) > 1 {
+				assert.Equal(t, "projects/proj1/zones/asia-east1-a/disks/web-1", plans[1].Path)
+				assert.Equal(t, "asia-east1-a", plans[1].Location)
+				assert.Equal(t, map[string]string{"env": "prod", "team": "web"}, plans[1].Labels)
+				assert.Equal(t, []LabelChange{
+					{Action: labelAdd, Key: "env", Desired: "prod"},
+					{Action: labelRemove, Key: "owner", Current: "alice"},
+					{Action: labelAdd, Key: "team", Desired: "web"},
+				}, plans[1].Changes)
+			}
+		})
+	}
+}
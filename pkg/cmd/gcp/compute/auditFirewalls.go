@@ -0,0 +1,175 @@
+/*
+Copyright © 2022 tchiunam
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package compute
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	compute "cloud.google.com/go/compute/apiv1"
+	"github.com/spf13/cobra"
+	"github.com/tchiunam/axolgo-cli/pkg/util"
+	"k8s.io/klog/v2"
+)
+
+// ErrFirewallViolations is returned when a firewall rule exposes
+// sensitive ports to the internet
+var ErrFirewallViolations = errors.New("firewall violations found")
+
+var (
+	auditFirewallsLong = `Audit VPC firewall rules for exposure to the internet. An enabled
+ingress allow rule with a source range of 0.0.0.0/0 or ::/0 is a
+finding if it allows all ports, which is CRITICAL, or a sensitive
+port such as SSH (22), RDP (3389) or a database port. More sensitive
+ports can be given with --port and are HIGH. Protocols without ports,
+e.g. icmp of the default-allow-icmp rule, are not findings.
+
+The instances which a finding applies to are resolved from the
+instances in all zones of the projects. A rule applies to the
+instances in its network which have one of its target tags or target
+service accounts, or to all instances in its network if it has no
+target. For a Shared VPC, give both the host and service projects.
+Deny rules of a higher priority are not taken into account.
+
+The findings are printed with their severity and the command exits
+with code 1 if there is any finding of --min-severity or above.
+`
+	auditFirewallsExample = `  # Audit the firewall rules of a project
+  axolgo gcp compute auditFirewalls --project proj1
+
+  # Audit a Shared VPC with an extra sensitive port and report HIGH or above only
+  axolgo gcp compute auditFirewalls --projects host-proj,service-proj --port 8443 --port 53/udp --min-severity HIGH
+`
+)
+
+// AuditFirewallsOptions defines flags and other configuration parameters for the `auditFirewalls` command
+type AuditFirewallsOptions struct {
+	Project     string
+	Projects    []string
+	Ports       []string
+	MinSeverity string
+	Concurrency int
+	Output      string
+}
+
+// NewCmdAuditFirewalls creates the `auditFirewalls` command
+func NewCmdAuditFirewalls(ctx *context.Context) *cobra.Command {
+	o := AuditFirewallsOptions{}
+
+	cmd := &cobra.Command{
+		Use:                   "auditFirewalls [-p] [--projects] [--port PORT[/PROTOCOL]] [--min-severity] [--concurrency] [-o]",
+		DisableFlagsInUseLine: true,
+		Short:                 "Audit VPC firewall rules for exposure to the internet.",
+		Long:                  auditFirewallsLong,
+		Example:               auditFirewallsExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := o.complete(ctx, cmd, args); err != nil {
+				if errors.Is(err, ErrFirewallViolations) {
+					klog.Error(err)
+					os.Exit(1)
+				}
+				panic(err)
+			}
+		},
+	}
+
+	cmd.Flags().StringVarP(&o.Project, "project", "p", "", "Project ID. Default is the project in axolgo configuration.")
+	cmd.Flags().StringSliceVar(&o.Projects, "projects", nil, "Comma separated project IDs.")
+	cmd.Flags().StringArrayVar(&o.Ports, "port", nil, "Extra sensitive port in the format of PORT[/PROTOCOL]. Default protocol is tcp.")
+	cmd.Flags().StringVar(&o.MinSeverity, "min-severity", "LOW", fmt.Sprintf("Min. severity of the findings to report. One of %v.", severities))
+	cmd.Flags().IntVar(&o.Concurrency, "concurrency", 4, "Max. no. of projects to list instances at the same time.")
+	cmd.Flags().StringVarP(&o.Output, "output", "o", util.OutputTable, fmt.Sprintf("Output format. One of %v.", util.OutputFormats))
+
+	return cmd
+}
+
+// Complete takes the command arguments and execute.
+func (o *AuditFirewallsOptions) complete(ctx *context.Context, _ *cobra.Command, args []string) error {
+	if err := ValidateSeverity(o.MinSeverity); err != nil {
+		return err
+	}
+	if o.Concurrency < 1 {
+		return fmt.Errorf("invalid concurrency: %d", o.Concurrency)
+	}
+	ports := append([]SensitivePort{}, defaultSensitivePorts...)
+	for _, s := range o.Ports {
+		p, err := ParseSensitivePort(s)
+		if err != nil {
+			return err
+		}
+		ports = append(ports, p)
+	}
+
+	projects, err := defaultProjects(uniqueProjects(append([]string{o.Project}, o.Projects...)))
+	if err != nil {
+		return err
+	}
+
+	opts, err := util.GCPClientOptions(context.TODO())
+	if err != nil {
+		return err
+	}
+	fc, err := compute.NewFirewallsRESTClient(context.TODO(), opts...)
+	if err != nil {
+		return fmt.Errorf("failed to create firewall client: %w", err)
+	}
+	defer fc.Close()
+	ic, err := newInstancesClient(context.TODO())
+	if err != nil {
+		return err
+	}
+	defer ic.Close()
+
+	// A project which fails makes the audit incomplete, so it aborts
+	var firewalls []FirewallInfo
+	for _, project := range projects {
+		f, err := ListFirewalls(context.TODO(), fc, project, "", 0)
+		if err != nil {
+			return fmt.Errorf("failed to list firewall rules in project %s: %w", project, err)
+		}
+		firewalls = append(firewalls, f...)
+	}
+	results := ListProjectsInstances(context.TODO(), projects, o.Concurrency, func(ctx context.Context, project string) ([]InstanceInfo, error) {
+		return ListInstances(ctx, ic, project, "", "", 0, true)
+	})
+	var instances []InstanceInfo
+	for _, r := range results {
+		if r.Err != nil {
+			return fmt.Errorf("failed to list compute engine instances in project %s: %w", r.Project, r.Err)
+		}
+		instances = append(instances, r.Instances...)
+	}
+
+	findings := AuditFirewalls(firewalls, instances, ports, o.MinSeverity)
+	klog.V(3).InfoS("Audit firewall rules", "firewalls", len(firewalls), "instances", len(instances), "findings", len(findings))
+	if err := util.PrintRecords(os.Stdout, o.Output, findingHeader, findingRows(findings)); err != nil {
+		return err
+	}
+	if len(findings) > 0 {
+		return fmt.Errorf("%w: %d firewall rules expose sensitive ports to the internet", ErrFirewallViolations, len(findings))
+	}
+
+	return nil
+}
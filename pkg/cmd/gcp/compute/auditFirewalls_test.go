@@ -0,0 +1,87 @@
+/*
+Copyright © 2022 tchiunam
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package compute
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestNewCmdAuditFirewalls tests the NewCmdAuditFirewalls function
+// to make sure it returns a valid command.
+func TestNewCmdAuditFirewalls(t *testing.T) {
+	cases := map[string]struct {
+		use      string
+		short    string
+		hasFlags bool
+	}{
+		"valid command": {
+			use:      "auditFirewalls [-p] [--projects] [--port PORT[/PROTOCOL]] [--min-severity] [--concurrency] [-o]",
+			short:    "Audit VPC firewall rules for exposure to the internet.",
+			hasFlags: true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cmd := NewCmdAuditFirewalls(nil)
+			assert.Equal(t, tc.use, cmd.Use)
+			assert.Equal(t, tc.short, cmd.Short)
+			assert.Equal(t, tc.hasFlags, cmd.Flags().HasFlags())
+		})
+	}
+}
+
+// TestNewCmdAuditFirewallsInvalid calls the NewCmdAuditFirewalls function
+// with invalid input and makes sure it returns an error.
+func TestNewCmdAuditFirewallsInvalid(t *testing.T) {
+	cases := map[string]struct {
+		args []string
+	}{
+		"invalid min severity": {
+			args: []string{"--project", "proj1", "--min-severity", "SEVERE"},
+		},
+		"invalid port": {
+			args: []string{"--project", "proj1", "--port", "70000"},
+		},
+		"invalid protocol": {
+			args: []string{"--project", "proj1", "--port", "53/icmp"},
+		},
+		"invalid concurrency": {
+			args: []string{"--projects", "proj1,proj2", "--concurrency", "0"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			oldArgs := os.Args
+			defer func() { os.Args = oldArgs }()
+			os.Args = append([]string{"cmd"}, tc.args...)
+
+			cmd := NewCmdAuditFirewalls(nil)
+			assert.Panics(t, func() { cmd.Execute() })
+		})
+	}
+}
@@ -43,6 +43,20 @@ func NewComputeCmd(ctx *context.Context) *cobra.Command {
 
 	cmd.AddCommand(
 		NewCmdListInstances(ctx),
+		NewCmdStartInstances(ctx),
+		NewCmdStopInstances(ctx),
+		NewCmdResetInstances(ctx),
+		NewCmdSuspendInstances(ctx),
+		NewCmdResumeInstances(ctx),
+		NewCmdDeleteInstances(ctx),
+		NewCmdGetSerialPortOutput(ctx),
+		NewCmdGetGuestAttributes(ctx),
+		NewCmdListFirewalls(ctx),
+		NewCmdAuditFirewalls(ctx),
+		NewCmdListDisks(ctx),
+		NewCmdCreateSnapshots(ctx),
+		NewCmdApplyLabels(ctx),
+		NewCmdPruneSnapshots(ctx),
 	)
 
 	return cmd
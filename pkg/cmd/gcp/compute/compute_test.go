@@ -40,7 +40,7 @@ func TestNewComputeCmd(t *testing.T) {
 			use:      "compute",
 			short:    "A set of compute commands.",
 			long:     "A set of compute commands.",
-			commands: 1,
+			commands: 15,
 		},
 	}
 
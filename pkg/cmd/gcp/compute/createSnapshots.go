@@ -0,0 +1,295 @@
+/*
+Copyright © 2022 tchiunam
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package compute
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	compute "cloud.google.com/go/compute/apiv1"
+	"cloud.google.com/go/compute/apiv1/computepb"
+	"github.com/spf13/cobra"
+	"github.com/tchiunam/axolgo-cli/pkg/util"
+	"google.golang.org/protobuf/proto"
+	"k8s.io/klog/v2"
+)
+
+var (
+	createSnapshotsLong = `Create snapshots of the disks of the compute engine instances which
+are filtered by given criteria in the same way as listInstances. A
+filter is required. Each snapshot is named after its disk with a short
+hash of the disk path and the creation time, e.g.
+web-1-3f2a1c-20221231235959, so the disks of the same name in
+different zones get different snapshot names.
+
+A snapshot carries the labels of its source instance and a label of
+axolgo-instance with the instance name, which can be used to select
+the snapshots to prune with pruneSnapshots. More labels can be given
+with --snapshot-label and take precedence.
+
+The snapshots are printed and a confirmation is asked unless --yes is
+given.
+`
+	createSnapshotsExample = `  # Snapshot the disks of the production web instances
+  axolgo gcp compute createSnapshots --project proj1 --all-zones --label env=prod --name web-1 --name web-2
+
+  # Snapshot the disks of an instance with an extra label and flush the guest file system
+  axolgo gcp compute createSnapshots --project proj1 --zone asia-east1-a --name db-1 --snapshot-label reason=upgrade --guest-flush --yes
+`
+)
+
+// CreateSnapshotsOptions defines flags and other configuration parameters for the `createSnapshots` command
+type CreateSnapshotsOptions struct {
+	Project        string
+	Zone           string
+	AllZones       bool
+	Filter         InstanceFilter
+	SnapshotLabels []string
+	GuestFlush     bool
+	Yes            bool
+	Wait           time.Duration
+	Concurrency    int
+}
+
+// SnapshotPlan is a snapshot to create of an instance disk
+type SnapshotPlan struct {
+	Instance InstanceInfo
+	// Disk is the path of the disk
+	Disk   string
+	Name   string
+	Labels map[string]string
+}
+
+// NewCmdCreateSnapshots creates the `createSnapshots` command
+func NewCmdCreateSnapshots(ctx *context.Context) *cobra.Command {
+	o := CreateSnapshotsOptions{}
+
+	cmd := &cobra.Command{
+		Use:                   "createSnapshots [-p] [-z] [--all-zones] [-i] [-n] [-l] [-s] [-m] [--network-ip] [-f] [--snapshot-label KEY=VALUE] [--guest-flush] [-y] [--wait] [--concurrency]",
+		DisableFlagsInUseLine: true,
+		Short:                 "Create snapshots of the disks of instances.",
+		Long:                  createSnapshotsLong,
+		Example:               createSnapshotsExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := o.complete(ctx, cmd, args); err != nil {
+				panic(err)
+			}
+		},
+	}
+
+	cmd.Flags().StringVarP(&o.Project, "project", "p", "", "Project ID. Default is the project in axolgo configuration.")
+	cmd.Flags().StringVarP(&o.Zone, "zone", "z", "", "Zone.")
+	cmd.Flags().BoolVar(&o.AllZones, "all-zones", false, "Select the instances in all zones.")
+	addFilterFlags(cmd, &o.Filter)
+	cmd.Flags().StringArrayVar(&o.SnapshotLabels, "snapshot-label", nil, "Extra label of the snapshots in the format of KEY=VALUE.")
+	cmd.Flags().BoolVar(&o.GuestFlush, "guest-flush", false, "Flush the guest file system before the snapshot. It requires the guest environment.")
+	cmd.Flags().BoolVarP(&o.Yes, "yes", "y", false, "Do not ask for confirmation.")
+	cmd.Flags().DurationVar(&o.Wait, "wait", 10*time.Minute, "Max. time to wait for a snapshot to be created. 0 is not to wait.")
+	cmd.Flags().IntVar(&o.Concurrency, "concurrency", 4, "Max. no. of instances to snapshot at the same time.")
+
+	return cmd
+}
+
+// Complete takes the command arguments and execute.
+func (o *CreateSnapshotsOptions) complete(ctx *context.Context, _ *cobra.Command, args []string) error {
+	if o.AllZones && o.Zone != "" {
+		return fmt.Errorf("--zone cannot be used with --all-zones")
+	}
+	if o.Concurrency < 1 {
+		return fmt.Errorf("invalid concurrency: %d", o.Concurrency)
+	}
+	if o.Wait < 0 {
+		return fmt.Errorf("invalid wait: %s", o.Wait)
+	}
+	extraLabels, err := util.ParseKeyValues(o.SnapshotLabels)
+	if err != nil {
+		return err
+	}
+	f, err := o.Filter.String()
+	if err != nil {
+		return err
+	}
+	// Never snapshot all the instances of a zone by accident
+	if f == "" {
+		return fmt.Errorf("a filter is required to select the instances to snapshot")
+	}
+
+	project, err := util.GCPProject(o.Project)
+	if err != nil {
+		return err
+	}
+	zone := ""
+	if !o.AllZones {
+		zone = util.GCPZone(o.Zone)
+	}
+
+	opts, err := util.GCPClientOptions(context.TODO())
+	if err != nil {
+		return err
+	}
+	ic, err := newInstancesClient(context.TODO())
+	if err != nil {
+		return err
+	}
+	defer ic.Close()
+	dc, err := compute.NewDisksRESTClient(context.TODO(), opts...)
+	if err != nil {
+		return fmt.Errorf("failed to create disk client: %w", err)
+	}
+	defer dc.Close()
+	rc, err := compute.NewRegionDisksRESTClient(context.TODO(), opts...)
+	if err != nil {
+		return fmt.Errorf("failed to create regional disk client: %w", err)
+	}
+	defer rc.Close()
+
+	instances, err := ListInstances(context.TODO(), ic, project, zone, f, 0, o.AllZones)
+	if err != nil {
+		return fmt.Errorf("failed to list compute engine instances: %w", err)
+	}
+	if len(instances) == 0 {
+		klog.Infof("No instance matches the filter: %s", f)
+		return nil
+	}
+
+	plans := PlanSnapshots(instances, extraLabels, time.Now())
+	if err := util.PrintTable(os.Stdout, snapshotPlanHeader, snapshotPlanRows(plans)); err != nil {
+		return err
+	}
+	if !o.Yes {
+		confirmed, err := util.Confirm(os.Stdin, os.Stdout, fmt.Sprintf("Do you want to create %d snapshot(s) of %d instance(s)?", len(plans), len(instances)))
+		if err != nil {
+			return err
+		}
+		if !confirmed {
+			klog.Info("Cancelled")
+			return nil
+		}
+	}
+
+	errs := RunInstanceActions(context.TODO(), instances, o.Concurrency, func(ctx context.Context, i InstanceInfo) error {
+		var failures []string
+		for _, p := range plans {
+			if p.Instance.Project != i.Project || p.Instance.Zone != i.Zone || p.Instance.Name != i.Name {
+				continue
+			}
+			if err := o.create(ctx, dc, rc, p); err != nil {
+				klog.Errorf("Failed to create snapshot %s of disk %s: %v", p.Name, lastSegment(p.Disk), err)
+				failures = append(failures, p.Name)
+			}
+		}
+		if len(failures) > 0 {
+			return fmt.Errorf("snapshot(s) %s", strings.Join(failures, ", "))
+		}
+		return nil
+	})
+	var failures []string
+	for i, err := range errs {
+		if err != nil {
+			failures = append(failures, instances[i].Name)
+		}
+	}
+	if len(failures) > 0 {
+		return fmt.Errorf("failed to snapshot instance(s): %s", strings.Join(failures, ", "))
+	}
+
+	return nil
+}
+
+// create creates a snapshot of a zonal or regional disk and waits for
+// it to be created
+func (o *CreateSnapshotsOptions) create(ctx context.Context, dc *compute.DisksClient, rc *compute.RegionDisksClient, p SnapshotPlan) error {
+	project, location, disk, regional, err := diskLocation(p.Disk)
+	if err != nil {
+		return err
+	}
+	snapshot := &computepb.Snapshot{Name: proto.String(p.Name), Labels: p.Labels}
+
+	var op *compute.Operation
+	if regional {
+		op, err = rc.CreateSnapshot(ctx, &computepb.CreateSnapshotRegionDiskRequest{
+			Project:          project,
+			Region:           location,
+			Disk:             disk,
+			SnapshotResource: snapshot,
+		})
+	} else {
+		req := &computepb.CreateSnapshotDiskRequest{
+			Project:          project,
+			Zone:             location,
+			Disk:             disk,
+			SnapshotResource: snapshot,
+		}
+		if o.GuestFlush {
+			req.GuestFlush = proto.Bool(true)
+		}
+		op, err = dc.CreateSnapshot(ctx, req)
+	}
+	if err != nil {
+		return err
+	}
+	if o.Wait == 0 {
+		klog.Infof("Requested to create snapshot %s", p.Name)
+		return nil
+	}
+	if err := waitOperation(ctx, op, o.Wait); err != nil {
+		return err
+	}
+	klog.Infof("Snapshot %s of disk %s created", p.Name, disk)
+
+	return nil
+}
+
+// PlanSnapshots returns a snapshot to create for each disk of the instances
+func PlanSnapshots(instances []InstanceInfo, extraLabels map[string]string, now time.Time) []SnapshotPlan {
+	var plans []SnapshotPlan
+	for _, i := range instances {
+		labels := SnapshotLabels(i, extraLabels)
+		for _, d := range i.Disks {
+			plans = append(plans, SnapshotPlan{
+				Instance: i,
+				Disk:     d,
+				Name:     SnapshotName(d, now),
+				Labels:   labels,
+			})
+		}
+	}
+
+	return plans
+}
+
+// snapshotPlanRows formats the columns of the snapshots to create
+func snapshotPlanRows(plans []SnapshotPlan) [][]string {
+	rows := make([][]string, 0, len(plans))
+	for _, p := range plans {
+		rows = append(rows, []string{p.Instance.Project, p.Instance.Zone, p.Instance.Name, lastSegment(p.Disk), p.Name, formatLabels(p.Labels)})
+	}
+
+	return rows
+}
+
+// The header of the snapshot plan rows
+var snapshotPlanHeader = []string{"PROJECT", "ZONE", "INSTANCE", "DISK", "SNAPSHOT", "LABELS"}
@@ -0,0 +1,124 @@
+/*
+Copyright © 2022 tchiunam
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package compute
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestNewCmdCreateSnapshots tests the NewCmdCreateSnapshots function
+// to make sure it returns a valid command.
+func TestNewCmdCreateSnapshots(t *testing.T) {
+	cases := map[string]struct {
+		use      string
+		short    string
+		hasFlags bool
+	}{
+		"valid command": {
+			use:      "createSnapshots [-p] [-z] [--all-zones] [-i] [-n] [-l] [-s] [-m] [--network-ip] [-f] [--snapshot-label KEY=VALUE] [--guest-flush] [-y] [--wait] [--concurrency]",
+			short:    "Create snapshots of the disks of instances.",
+			hasFlags: true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cmd := NewCmdCreateSnapshots(nil)
+			assert.Equal(t, tc.use, cmd.Use)
+			assert.Equal(t, tc.short, cmd.Short)
+			assert.Equal(t, tc.hasFlags, cmd.Flags().HasFlags())
+		})
+	}
+}
+
+// TestNewCmdCreateSnapshotsInvalid calls the NewCmdCreateSnapshots function
+// with invalid input and makes sure it returns an error.
+func TestNewCmdCreateSnapshotsInvalid(t *testing.T) {
+	cases := map[string]struct {
+		args []string
+	}{
+		"no filter": {
+			args: []string{"--project", "proj1", "--zone", "asia-east1-a"},
+		},
+		"invalid snapshot label": {
+			args: []string{"--project", "proj1", "--name", "web-1", "--snapshot-label", "reason"},
+		},
+		"zone with all zones": {
+			args: []string{"--project", "proj1", "--zone", "asia-east1-a", "--all-zones", "--name", "web-1"},
+		},
+		"invalid concurrency": {
+			args: []string{"--project", "proj1", "--name", "web-1", "--concurrency", "0"},
+		},
+		"negative wait": {
+			args: []string{"--project", "proj1", "--name", "web-1", "--wait", "-1m"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			oldArgs := os.Args
+			defer func() { os.Args = oldArgs }()
+			os.Args = append([]string{"cmd"}, tc.args...)
+
+			cmd := NewCmdCreateSnapshots(nil)
+			assert.Panics(t, func() { cmd.Execute() })
+		})
+	}
+}
+
+// TestPlanSnapshots tests the PlanSnapshots function
+func TestPlanSnapshots(t *testing.T) {
+	now := time.Date(2022, 12, 31, 23, 59, 59, 0, time.UTC)
+	instances := []InstanceInfo{
+		{
+			Project: "proj1",
+			Zone:    "asia-east1-a",
+			Name:    "web-1",
+			Labels:  map[string]string{"env": "prod"},
+			Disks:   []string{"projects/proj1/zones/asia-east1-a/disks/web-1", "projects/proj1/regions/asia-east1/disks/web-1-data"},
+		},
+		{
+			Project: "proj1",
+			Zone:    "asia-east1-b",
+			Name:    "web-2",
+			Disks:   []string{"projects/proj1/zones/asia-east1-b/disks/web-1"},
+		},
+		{Project: "proj1", Zone: "asia-east1-a", Name: "db-1"},
+	}
+
+	plans := PlanSnapshots(instances, map[string]string{"reason": "upgrade"}, now)
+	assert.Len(t, plans, 3)
+	assert.Equal(t, "web-1-97ca4d-20221231235959", plans[0].Name)
+	assert.Equal(t, "web-1-data-598058-20221231235959", plans[1].Name)
+	assert.Equal(t, "web-1-bcad4b-20221231235959", plans[2].Name)
+	assert.Equal(t, "projects/proj1/regions/asia-east1/disks/web-1-data", plans[1].Disk)
+	assert.Equal(t, map[string]string{"env": "prod", "axolgo-instance": "web-1", "reason": "upgrade"}, plans[0].Labels)
+
+	rows := snapshotPlanRows(plans)
+	assert.Equal(t, []string{"proj1", "asia-east1-a", "web-1", "web-1", "web-1-97ca4d-20221231235959", "axolgo-instance=web-1,env=prod,reason=upgrade"}, rows[0])
+	assert.Len(t, rows[0], len(snapshotPlanHeader))
+}
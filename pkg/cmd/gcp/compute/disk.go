@@ -0,0 +1,178 @@
+/*
+Copyright © 2022 tchiunam
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package compute
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+
+	compute "cloud.google.com/go/compute/apiv1"
+	"cloud.google.com/go/compute/apiv1/computepb"
+	"google.golang.org/api/iterator"
+)
+
+// DiskInfo is a persistent disk
+type DiskInfo struct {
+	Project string
+	// Location is the zone of a zonal disk or the region of a regional disk
+	Location string
+	Name     string
+	ID       uint64
+	SizeGB   int64
+	Type     string
+	Status   string
+	// Users are the names of the instances which the disk is attached to
+	Users  []string
+	Labels map[string]string
+}
+
+// ListDisks lists the disks of a project in a zone, or in all zones and
+// regions with the aggregated list if allZones is true
+func ListDisks(ctx context.Context, client *compute.DisksClient, project string, zone string, filter string, allZones bool) ([]DiskInfo, error) {
+	var f *string
+	if filter != "" {
+		f = &filter
+	}
+
+	var disks []DiskInfo
+	if allZones {
+		it := client.AggregatedList(ctx, &computepb.AggregatedListDisksRequest{Project: project, Filter: f})
+		for {
+			pair, err := it.Next()
+			if err == iterator.Done {
+				break
+			}
+			if err != nil {
+				return nil, err
+			}
+			for _, disk := range pair.Value.GetDisks() {
+				disks = append(disks, diskInfo(project, disk))
+			}
+		}
+	} else {
+		it := client.List(ctx, &computepb.ListDisksRequest{Project: project, Zone: zone, Filter: f})
+		for {
+			disk, err := it.Next()
+			if err == iterator.Done {
+				break
+			}
+			if err != nil {
+				return nil, err
+			}
+			disks = append(disks, diskInfo(project, disk))
+		}
+	}
+	sortDisks(disks)
+
+	return disks, nil
+}
+
+// ListRegionDisks lists the regional disks of a project in a region
+func ListRegionDisks(ctx context.Context, client *compute.RegionDisksClient, project string, region string, filter string) ([]DiskInfo, error) {
+	var f *string
+	if filter != "" {
+		f = &filter
+	}
+
+	var disks []DiskInfo
+	it := client.List(ctx, &computepb.ListRegionDisksRequest{Project: project, Region: region, Filter: f})
+	for {
+		disk, err := it.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		disks = append(disks, diskInfo(project, disk))
+	}
+	sortDisks(disks)
+
+	return disks, nil
+}
+
+// diskInfo converts a disk
+func diskInfo(project string, disk *computepb.Disk) DiskInfo {
+	info := DiskInfo{
+		Project:  project,
+		Location: lastSegment(disk.GetZone()),
+		Name:     disk.GetName(),
+		ID:       disk.GetId(),
+		SizeGB:   disk.GetSizeGb(),
+		Type:     lastSegment(disk.GetType()),
+		Status:   disk.GetStatus(),
+		Labels:   disk.GetLabels(),
+	}
+	if info.Location == "" {
+		info.Location = lastSegment(disk.GetRegion())
+	}
+	for _, u := range disk.GetUsers() {
+		info.Users = append(info.Users, lastSegment(u))
+	}
+
+	return info
+}
+
+// sortDisks sorts the disks by project, location and name
+func sortDisks(disks []DiskInfo) {
+	sort.SliceStable(disks, func(i, j int) bool {
+		a, b := disks[i], disks[j]
+		if a.Project != b.Project {
+			return a.Project < b.Project
+		}
+		if a.Location != b.Location {
+			return a.Location < b.Location
+		}
+		return a.Name < b.Name
+	})
+}
+
+// diskRows formats the columns of the disks
+func diskRows(disks []DiskInfo) [][]string {
+	rows := make([][]string, 0, len(disks))
+	for _, d := range disks {
+		attachedTo := "-"
+		if len(d.Users) > 0 {
+			attachedTo = strings.Join(d.Users, ",")
+		}
+		rows = append(rows, []string{
+			d.Project,
+			d.Location,
+			d.Name,
+			strconv.FormatUint(d.ID, 10),
+			strconv.FormatInt(d.SizeGB, 10),
+			d.Type,
+			d.Status,
+			attachedTo,
+			strconv.FormatBool(len(d.Users) == 0),
+			formatLabels(d.Labels),
+		})
+	}
+
+	return rows
+}
+
+// The header of the disk rows
+var diskHeader = []string{"PROJECT", "LOCATION", "NAME", "ID", "SIZE (GB)", "TYPE", "STATUS", "ATTACHED TO", "UNATTACHED", "LABELS"}
@@ -0,0 +1,159 @@
+/*
+Copyright © 2022 tchiunam
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package compute
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	compute "cloud.google.com/go/compute/apiv1"
+	"cloud.google.com/go/compute/apiv1/computepb"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/api/option"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// TestDiskInfo tests the diskInfo function
+func TestDiskInfo(t *testing.T) {
+	cases := map[string]struct {
+		disk *computepb.Disk
+		info DiskInfo
+	}{
+		"attached zonal disk": {
+			disk: &computepb.Disk{
+				Name:   proto.String("web-1"),
+				Id:     proto.Uint64(1),
+				Zone:   proto.String("https://www.googleapis.com/compute/v1/projects/proj1/zones/asia-east1-a"),
+				SizeGb: proto.Int64(20),
+				Type:   proto.String("https://www.googleapis.com/compute/v1/projects/proj1/zones/asia-east1-a/diskTypes/pd-balanced"),
+				Status: proto.String("READY"),
+				Users:  []string{"https://www.googleapis.com/compute/v1/projects/proj1/zones/asia-east1-a/instances/web-1"},
+				Labels: map[string]string{"env": "prod"},
+			},
+			info: DiskInfo{
+				Project:  "proj1",
+				Location: "asia-east1-a",
+				Name:     "web-1",
+				ID:       1,
+				SizeGB:   20,
+				Type:     "pd-balanced",
+				Status:   "READY",
+				Users:    []string{"web-1"},
+				Labels:   map[string]string{"env": "prod"},
+			},
+		},
+		"unattached regional disk": {
+			disk: &computepb.Disk{
+				Name:   proto.String("shared-1"),
+				Id:     proto.Uint64(2),
+				Region: proto.String("https://www.googleapis.com/compute/v1/projects/proj1/regions/asia-east1"),
+				SizeGb: proto.Int64(200),
+				Type:   proto.String("https://www.googleapis.com/compute/v1/projects/proj1/regions/asia-east1/diskTypes/pd-ssd"),
+				Status: proto.String("READY"),
+			},
+			info: DiskInfo{
+				Project:  "proj1",
+				Location: "asia-east1",
+				Name:     "shared-1",
+				ID:       2,
+				SizeGB:   200,
+				Type:     "pd-ssd",
+				Status:   "READY",
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.info, diskInfo("proj1", tc.disk))
+		})
+	}
+}
+
+// TestDiskRows tests the diskRows function
+func TestDiskRows(t *testing.T) {
+	cases := map[string]struct {
+		disk DiskInfo
+		row  []string
+	}{
+		"attached disk": {
+			disk: DiskInfo{Project: "proj1", Location: "asia-east1-a", Name: "shared-1", ID: 1, SizeGB: 20, Type: "pd-balanced", Status: "READY", Users: []string{"web-1", "web-2"}, Labels: map[string]string{"env": "prod"}},
+			row:  []string{"proj1", "asia-east1-a", "shared-1", "1", "20", "pd-balanced", "READY", "web-1,web-2", "false", "env=prod"},
+		},
+		"unattached disk": {
+			disk: DiskInfo{Project: "proj1", Location: "asia-east1", Name: "orphan-1", ID: 2, SizeGB: 200, Type: "pd-ssd", Status: "READY"},
+			row:  []string{"proj1", "asia-east1", "orphan-1", "2", "200", "pd-ssd", "READY", "-", "true", "-"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			rows := diskRows([]DiskInfo{tc.disk})
+			assert.Equal(t, [][]string{tc.row}, rows)
+			assert.Len(t, tc.row, len(diskHeader))
+		})
+	}
+}
+
+// TestListRegionDisks tests that ListRegionDisks lists the disks of a region
+func TestListRegionDisks(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/compute/v1/projects/proj1/regions/asia-east1/disks" {
+			http.NotFound(w, r)
+			return
+		}
+		assert.Equal(t, "labels.env = prod", r.URL.Query().Get("filter"))
+		b, _ := protojson.Marshal(&computepb.DiskList{Items: []*computepb.Disk{
+			{
+				Name:   proto.String("db-2"),
+				Region: proto.String("https://www.googleapis.com/compute/v1/projects/proj1/regions/asia-east1"),
+				SizeGb: proto.Int64(100),
+			},
+			{
+				Name:   proto.String("db-1"),
+				Region: proto.String("https://www.googleapis.com/compute/v1/projects/proj1/regions/asia-east1"),
+				SizeGb: proto.Int64(50),
+			},
+		}})
+		w.Write(b)
+	}))
+	defer server.Close()
+	client, err := compute.NewRegionDisksRESTClient(context.TODO(), option.WithEndpoint(server.URL), option.WithoutAuthentication())
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer client.Close()
+
+	disks, err := ListRegionDisks(context.TODO(), client, "proj1", "asia-east1", "labels.env = prod")
+	assert.NoError(t, err)
+	assert.Equal(t, []DiskInfo{
+		{Project: "proj1", Location: "asia-east1", Name: "db-1", SizeGB: 50},
+		{Project: "proj1", Location: "asia-east1", Name: "db-2", SizeGB: 100},
+	}, disks)
+
+	_, err = ListRegionDisks(context.TODO(), client, "proj1", "us-central1", "")
+	assert.Error(t, err)
+}
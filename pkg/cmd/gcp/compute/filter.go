@@ -0,0 +1,164 @@
+/*
+Copyright © 2022 tchiunam
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package compute
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// The statuses of an instance
+var instanceStatuses = []string{
+	"PROVISIONING",
+	"STAGING",
+	"RUNNING",
+	"STOPPING",
+	"STOPPED",
+	"SUSPENDING",
+	"SUSPENDED",
+	"REPAIRING",
+	"TERMINATED",
+}
+
+// InstanceFilter selects instances by their properties. Different
+// properties are joined with AND and the values of a property are
+// joined with OR.
+type InstanceFilter struct {
+	IDs          []string
+	Names        []string
+	Labels       []string
+	Statuses     []string
+	MachineTypes []string
+	NetworkIPs   []string
+	// Raw is a filter expression which is passed through
+	Raw string
+}
+
+// addFilterFlags adds the flags of an instance filter to a command
+func addFilterFlags(cmd *cobra.Command, f *InstanceFilter) {
+	cmd.Flags().StringArrayVarP(&f.IDs, "id", "i", nil, "Instance IDs.")
+	cmd.Flags().StringArrayVarP(&f.Names, "name", "n", nil, "Instance Names.")
+	cmd.Flags().StringArrayVarP(&f.Labels, "label", "l", nil, "Instance labels in the format of KEY=VALUE, or KEY for any value.")
+	cmd.Flags().StringArrayVarP(&f.Statuses, "status", "s", nil, fmt.Sprintf("Instance statuses. One of %v.", instanceStatuses))
+	cmd.Flags().StringArrayVarP(&f.MachineTypes, "machine-type", "m", nil, "Machine types, e.g. e2-medium.")
+	cmd.Flags().StringArrayVar(&f.NetworkIPs, "network-ip", nil, "Internal IP addresses.")
+	cmd.Flags().StringVarP(&f.Raw, "filter", "f", "", "Filter expression which is passed to the API as it is.")
+}
+
+// String builds the filter expression of the API. It is empty if no
+// property is given.
+func (f *InstanceFilter) String() (string, error) {
+	var groups [][]string
+
+	ids := make([]string, 0, len(f.IDs))
+	for _, id := range f.IDs {
+		if id == "" || strings.Trim(id, "0123456789") != "" {
+			return "", fmt.Errorf("invalid instance ID: %s", id)
+		}
+		ids = append(ids, fmt.Sprintf("id = %s", id))
+	}
+	groups = append(groups, ids)
+
+	names := make([]string, 0, len(f.Names))
+	for _, name := range f.Names {
+		names = append(names, fmt.Sprintf("name = %s", quoteFilterValue(name)))
+	}
+	groups = append(groups, names)
+
+	labels := make([]string, 0, len(f.Labels))
+	for _, label := range f.Labels {
+		key, value, hasValue := strings.Cut(label, "=")
+		key = strings.TrimSpace(key)
+		if key == "" {
+			return "", fmt.Errorf("invalid label: %s", label)
+		}
+		if hasValue {
+			labels = append(labels, fmt.Sprintf("labels.%s = %s", key, quoteFilterValue(value)))
+		} else {
+			labels = append(labels, fmt.Sprintf("labels.%s:*", key))
+		}
+	}
+	groups = append(groups, labels)
+
+	statuses := make([]string, 0, len(f.Statuses))
+	for _, status := range f.Statuses {
+		status = strings.ToUpper(status)
+		if !isInstanceStatus(status) {
+			return "", fmt.Errorf("invalid status %s, must be one of %v", status, instanceStatuses)
+		}
+		statuses = append(statuses, fmt.Sprintf("status = %s", status))
+	}
+	groups = append(groups, statuses)
+
+	machineTypes := make([]string, 0, len(f.MachineTypes))
+	for _, machineType := range f.MachineTypes {
+		// Machine type is a URL which ends with the name
+		machineTypes = append(machineTypes, fmt.Sprintf("machineType : %s", quoteFilterValue("/machineTypes/"+machineType)))
+	}
+	groups = append(groups, machineTypes)
+
+	networkIPs := make([]string, 0, len(f.NetworkIPs))
+	for _, ip := range f.NetworkIPs {
+		networkIPs = append(networkIPs, fmt.Sprintf("networkInterfaces.networkIP = %s", quoteFilterValue(ip)))
+	}
+	groups = append(groups, networkIPs)
+
+	if raw := strings.TrimSpace(f.Raw); raw != "" {
+		groups = append(groups, []string{raw})
+	}
+
+	var expressions []string
+	for _, group := range groups {
+		switch len(group) {
+		case 0:
+		case 1:
+			expressions = append(expressions, "("+group[0]+")")
+		default:
+			terms := make([]string, 0, len(group))
+			for _, term := range group {
+				terms = append(terms, "("+term+")")
+			}
+			expressions = append(expressions, "("+strings.Join(terms, " OR ")+")")
+		}
+	}
+
+	return strings.Join(expressions, " AND "), nil
+}
+
+// isInstanceStatus tells whether a status is a status of an instance
+func isInstanceStatus(status string) bool {
+	for _, s := range instanceStatuses {
+		if s == status {
+			return true
+		}
+	}
+
+	return false
+}
+
+// quoteFilterValue quotes a string value of a filter expression
+func quoteFilterValue(value string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(value) + `"`
+}
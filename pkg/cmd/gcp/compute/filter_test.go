@@ -0,0 +1,105 @@
+/*
+Copyright © 2022 tchiunam
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package compute
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestInstanceFilterString tests the String method of InstanceFilter
+func TestInstanceFilterString(t *testing.T) {
+	cases := map[string]struct {
+		filter InstanceFilter
+		result string
+		valid  bool
+	}{
+		"no filter": {
+			filter: InstanceFilter{},
+			result: "",
+			valid:  true,
+		},
+		"an id": {
+			filter: InstanceFilter{IDs: []string{"7452065390813417482"}},
+			result: "(id = 7452065390813417482)",
+			valid:  true,
+		},
+		"ids are joined with or": {
+			filter: InstanceFilter{IDs: []string{"1", "2"}},
+			result: "((id = 1) OR (id = 2))",
+			valid:  true,
+		},
+		"id and name are joined with and": {
+			filter: InstanceFilter{IDs: []string{"1"}, Names: []string{"web-1"}},
+			result: `(id = 1) AND (name = "web-1")`,
+			valid:  true,
+		},
+		"names and statuses": {
+			filter: InstanceFilter{Names: []string{"web-1", "web-2"}, Statuses: []string{"running", "STOPPED"}},
+			result: `((name = "web-1") OR (name = "web-2")) AND ((status = RUNNING) OR (status = STOPPED))`,
+			valid:  true,
+		},
+		"labels are joined with or": {
+			filter: InstanceFilter{Labels: []string{"env=prod", "team"}},
+			result: `((labels.env = "prod") OR (labels.team:*))`,
+			valid:  true,
+		},
+		"machine types and network ips": {
+			filter: InstanceFilter{MachineTypes: []string{"e2-medium", "n2-standard-4"}, NetworkIPs: []string{"10.0.0.2"}},
+			result: `((machineType : "/machineTypes/e2-medium") OR (machineType : "/machineTypes/n2-standard-4")) AND (networkInterfaces.networkIP = "10.0.0.2")`,
+			valid:  true,
+		},
+		"raw filter": {
+			filter: InstanceFilter{Names: []string{"web-1"}, Raw: `scheduling.preemptible = true`},
+			result: `(name = "web-1") AND (scheduling.preemptible = true)`,
+			valid:  true,
+		},
+		"quoted value": {
+			filter: InstanceFilter{Names: []string{`web"1`}},
+			result: `(name = "web\"1")`,
+			valid:  true,
+		},
+		"invalid id": {
+			filter: InstanceFilter{IDs: []string{"web-1"}},
+		},
+		"invalid label": {
+			filter: InstanceFilter{Labels: []string{"=prod"}},
+		},
+		"invalid status": {
+			filter: InstanceFilter{Statuses: []string{"DELETED"}},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			result, err := tc.filter.String()
+			if tc.valid {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.result, result)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,199 @@
+/*
+Copyright © 2022 tchiunam
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package compute
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	compute "cloud.google.com/go/compute/apiv1"
+	"cloud.google.com/go/compute/apiv1/computepb"
+	"google.golang.org/api/iterator"
+)
+
+// FirewallRule is a protocol and the ports of a firewall rule. No port
+// means all ports.
+type FirewallRule struct {
+	Protocol string
+	Ports    []string
+}
+
+// FirewallInfo is a VPC firewall rule
+type FirewallInfo struct {
+	Project string
+	Name    string
+	// Network is the network path, e.g. projects/proj1/global/networks/default
+	Network               string
+	Direction             string
+	Priority              int32
+	Action                string
+	Rules                 []FirewallRule
+	SourceRanges          []string
+	DestinationRanges     []string
+	SourceTags            []string
+	SourceServiceAccounts []string
+	TargetTags            []string
+	TargetServiceAccounts []string
+	Disabled              bool
+}
+
+// ListFirewalls lists the firewall rules of a project
+func ListFirewalls(ctx context.Context, client *compute.FirewallsClient, project string, filter string, maxResults int32) ([]FirewallInfo, error) {
+	req := &computepb.ListFirewallsRequest{Project: project}
+	if filter != "" {
+		req.Filter = &filter
+	}
+	if maxResults > 0 {
+		m := uint32(maxResults)
+		req.MaxResults = &m
+	}
+
+	var firewalls []FirewallInfo
+	it := client.List(ctx, req)
+	for {
+		firewall, err := it.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		firewalls = append(firewalls, firewallInfo(project, firewall))
+	}
+	sortFirewalls(firewalls)
+
+	return firewalls, nil
+}
+
+// firewallInfo converts a firewall rule
+func firewallInfo(project string, firewall *computepb.Firewall) FirewallInfo {
+	info := FirewallInfo{
+		Project:               project,
+		Name:                  firewall.GetName(),
+		Network:               resourcePath(firewall.GetNetwork()),
+		Direction:             firewall.GetDirection(),
+		Priority:              firewall.GetPriority(),
+		Action:                "ALLOW",
+		SourceRanges:          firewall.GetSourceRanges(),
+		DestinationRanges:     firewall.GetDestinationRanges(),
+		SourceTags:            firewall.GetSourceTags(),
+		SourceServiceAccounts: firewall.GetSourceServiceAccounts(),
+		TargetTags:            firewall.GetTargetTags(),
+		TargetServiceAccounts: firewall.GetTargetServiceAccounts(),
+		Disabled:              firewall.GetDisabled(),
+	}
+	for _, a := range firewall.GetAllowed() {
+		info.Rules = append(info.Rules, FirewallRule{Protocol: a.GetIPProtocol(), Ports: a.GetPorts()})
+	}
+	if denied := firewall.GetDenied(); len(denied) > 0 {
+		info.Action = "DENY"
+		for _, d := range denied {
+			info.Rules = append(info.Rules, FirewallRule{Protocol: d.GetIPProtocol(), Ports: d.GetPorts()})
+		}
+	}
+
+	return info
+}
+
+// sortFirewalls sorts the firewall rules by project, network, priority
+// and name
+func sortFirewalls(firewalls []FirewallInfo) {
+	sort.SliceStable(firewalls, func(i, j int) bool {
+		a, b := firewalls[i], firewalls[j]
+		if a.Project != b.Project {
+			return a.Project < b.Project
+		}
+		if a.Network != b.Network {
+			return a.Network < b.Network
+		}
+		if a.Priority != b.Priority {
+			return a.Priority < b.Priority
+		}
+		return a.Name < b.Name
+	})
+}
+
+// formatFirewallRules formats the rules as PROTOCOL:PORTS, e.g. tcp:22,80
+func formatFirewallRules(rules []FirewallRule) string {
+	if len(rules) == 0 {
+		return "-"
+	}
+	formatted := make([]string, 0, len(rules))
+	for _, r := range rules {
+		if len(r.Ports) == 0 {
+			formatted = append(formatted, r.Protocol)
+		} else {
+			formatted = append(formatted, fmt.Sprintf("%s:%s", r.Protocol, strings.Join(r.Ports, ",")))
+		}
+	}
+
+	return strings.Join(formatted, ";")
+}
+
+// formatFirewallTargets formats the target tags and service accounts.
+// A rule without targets applies to all instances of the network.
+func formatFirewallTargets(f FirewallInfo) string {
+	targets := make([]string, 0, len(f.TargetTags)+len(f.TargetServiceAccounts))
+	for _, t := range f.TargetTags {
+		targets = append(targets, "tag:"+t)
+	}
+	for _, sa := range f.TargetServiceAccounts {
+		targets = append(targets, "serviceAccount:"+sa)
+	}
+	if len(targets) == 0 {
+		return "all instances"
+	}
+
+	return strings.Join(targets, ",")
+}
+
+// firewallRows formats the columns of the firewall rules
+func firewallRows(firewalls []FirewallInfo) [][]string {
+	rows := make([][]string, 0, len(firewalls))
+	for _, f := range firewalls {
+		ranges := f.SourceRanges
+		if f.Direction == "EGRESS" {
+			ranges = f.DestinationRanges
+		}
+		rows = append(rows, []string{
+			f.Project,
+			lastSegment(f.Network),
+			f.Name,
+			f.Direction,
+			strconv.FormatInt(int64(f.Priority), 10),
+			f.Action,
+			formatFirewallRules(f.Rules),
+			formatIPs(ranges),
+			formatFirewallTargets(f),
+			strconv.FormatBool(f.Disabled),
+		})
+	}
+
+	return rows
+}
+
+// The header of the firewall rows
+var firewallHeader = []string{"PROJECT", "NETWORK", "NAME", "DIRECTION", "PRIORITY", "ACTION", "RULES", "RANGES", "TARGETS", "DISABLED"}
@@ -0,0 +1,291 @@
+/*
+Copyright © 2022 tchiunam
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package compute
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// The severities of a finding from the lowest to the highest
+var severities = []string{"LOW", "MEDIUM", "HIGH", "CRITICAL"}
+
+// The protocols which have ports. A rule of another protocol, e.g.
+// icmp, esp or ah, exposes no port.
+var portProtocols = []string{"tcp", "udp", "sctp"}
+
+// The source ranges which mean the whole internet
+var anywhereRanges = []string{"0.0.0.0/0", "::/0"}
+
+// SensitivePort is a port which should not be open to the internet
+type SensitivePort struct {
+	Protocol string
+	Port     int
+	Service  string
+	Severity string
+}
+
+// The sensitive ports which are audited by default in the order of port
+var defaultSensitivePorts = []SensitivePort{
+	{Protocol: "tcp", Port: 21, Service: "FTP", Severity: "MEDIUM"},
+	{Protocol: "tcp", Port: 22, Service: "SSH", Severity: "HIGH"},
+	{Protocol: "tcp", Port: 23, Service: "Telnet", Severity: "CRITICAL"},
+	{Protocol: "udp", Port: 161, Service: "SNMP", Severity: "MEDIUM"},
+	{Protocol: "tcp", Port: 445, Service: "SMB", Severity: "HIGH"},
+	{Protocol: "tcp", Port: 1433, Service: "SQL Server", Severity: "HIGH"},
+	{Protocol: "tcp", Port: 1521, Service: "Oracle", Severity: "HIGH"},
+	{Protocol: "tcp", Port: 2379, Service: "etcd", Severity: "HIGH"},
+	{Protocol: "tcp", Port: 3306, Service: "MySQL", Severity: "HIGH"},
+	{Protocol: "tcp", Port: 3389, Service: "RDP", Severity: "HIGH"},
+	{Protocol: "tcp", Port: 5432, Service: "PostgreSQL", Severity: "HIGH"},
+	{Protocol: "tcp", Port: 5900, Service: "VNC", Severity: "HIGH"},
+	{Protocol: "tcp", Port: 6379, Service: "Redis", Severity: "HIGH"},
+	{Protocol: "tcp", Port: 9200, Service: "Elasticsearch", Severity: "HIGH"},
+	{Protocol: "tcp", Port: 10250, Service: "kubelet", Severity: "HIGH"},
+	{Protocol: "tcp", Port: 11211, Service: "Memcached", Severity: "HIGH"},
+	{Protocol: "udp", Port: 11211, Service: "Memcached", Severity: "HIGH"},
+	{Protocol: "tcp", Port: 27017, Service: "MongoDB", Severity: "HIGH"},
+}
+
+// FirewallFinding is a firewall rule which exposes ports to the internet
+// and the instances which it applies to
+type FirewallFinding struct {
+	Severity  string
+	Firewall  FirewallInfo
+	Exposed   []string
+	Instances []InstanceInfo
+}
+
+// ParseSensitivePort parses a port in the format of PORT[/PROTOCOL].
+// The protocol is tcp by default.
+func ParseSensitivePort(s string) (SensitivePort, error) {
+	p := SensitivePort{Protocol: "tcp", Service: "custom", Severity: "HIGH"}
+	port := s
+	if i := strings.Index(s, "/"); i >= 0 {
+		port, p.Protocol = s[:i], strings.ToLower(s[i+1:])
+	}
+	if p.Protocol != "tcp" && p.Protocol != "udp" && p.Protocol != "sctp" {
+		return p, fmt.Errorf("invalid protocol of port %s", s)
+	}
+	n, err := strconv.Atoi(port)
+	if err != nil || n < 1 || n > 65535 {
+		return p, fmt.Errorf("invalid port: %s", s)
+	}
+	p.Port = n
+
+	return p, nil
+}
+
+// ValidateSeverity returns an error if a severity is unknown
+func ValidateSeverity(severity string) error {
+	if severityRank(severity) < 0 {
+		return fmt.Errorf("invalid severity: %s, must be one of %v", severity, severities)
+	}
+
+	return nil
+}
+
+// AuditFirewalls finds the enabled ingress allow rules which are open to
+// the internet on all ports or on a sensitive port, and resolves the
+// instances which they apply to. Findings below minSeverity are
+// dropped. The findings are sorted by severity from the highest.
+func AuditFirewalls(firewalls []FirewallInfo, instances []InstanceInfo, ports []SensitivePort, minSeverity string) []FirewallFinding {
+	var findings []FirewallFinding
+	for _, f := range firewalls {
+		if f.Direction != "INGRESS" || f.Action != "ALLOW" || f.Disabled || !openToInternet(f) {
+			continue
+		}
+
+		severity := ""
+		var exposed []string
+		for _, r := range f.Rules {
+			protocol := normalizeProtocol(r.Protocol)
+			if protocol != "all" && !contains(portProtocols, protocol) {
+				continue
+			}
+			if protocol == "all" || len(r.Ports) == 0 {
+				exposed = append(exposed, protocol+":all")
+				severity = maxSeverity(severity, "CRITICAL")
+				continue
+			}
+			for _, p := range ports {
+				if p.Protocol == protocol && portInRanges(p.Port, r.Ports) {
+					exposed = append(exposed, fmt.Sprintf("%s:%d (%s)", p.Protocol, p.Port, p.Service))
+					severity = maxSeverity(severity, p.Severity)
+				}
+			}
+		}
+		if severity == "" || severityRank(severity) < severityRank(minSeverity) {
+			continue
+		}
+
+		finding := FirewallFinding{Severity: severity, Firewall: f, Exposed: exposed}
+		for _, i := range instances {
+			if firewallAppliesTo(f, i) {
+				finding.Instances = append(finding.Instances, i)
+			}
+		}
+		findings = append(findings, finding)
+	}
+
+	sort.SliceStable(findings, func(i, j int) bool {
+		return severityRank(findings[i].Severity) > severityRank(findings[j].Severity)
+	})
+
+	return findings
+}
+
+// openToInternet checks if a firewall rule allows any source address
+func openToInternet(f FirewallInfo) bool {
+	for _, r := range f.SourceRanges {
+		for _, a := range anywhereRanges {
+			if r == a {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// firewallAppliesTo checks if a firewall rule applies to an instance.
+// A rule applies to the instances in its network which have one of
+// its target tags or target service accounts, or to all instances in
+// its network if it has no target.
+func firewallAppliesTo(f FirewallInfo, i InstanceInfo) bool {
+	if !contains(i.Networks, f.Network) {
+		return false
+	}
+	if len(f.TargetTags) == 0 && len(f.TargetServiceAccounts) == 0 {
+		return true
+	}
+	for _, t := range f.TargetTags {
+		if contains(i.Tags, t) {
+			return true
+		}
+	}
+	for _, sa := range f.TargetServiceAccounts {
+		if contains(i.ServiceAccounts, sa) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// normalizeProtocol converts a protocol number to its name
+func normalizeProtocol(protocol string) string {
+	switch protocol = strings.ToLower(protocol); protocol {
+	case "6":
+		return "tcp"
+	case "17":
+		return "udp"
+	case "132":
+		return "sctp"
+	}
+
+	return protocol
+}
+
+// portInRanges checks if a port is in one of the ports or port ranges,
+// e.g. 22 or 8000-8999
+func portInRanges(port int, ranges []string) bool {
+	for _, r := range ranges {
+		from, to, found := strings.Cut(r, "-")
+		if !found {
+			to = from
+		}
+		low, err := strconv.Atoi(from)
+		if err != nil {
+			continue
+		}
+		high, err := strconv.Atoi(to)
+		if err != nil {
+			continue
+		}
+		if low <= port && port <= high {
+			return true
+		}
+	}
+
+	return false
+}
+
+// severityRank returns the rank of a severity, or -1 if it is unknown
+func severityRank(severity string) int {
+	for i, s := range severities {
+		if s == severity {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// maxSeverity returns the higher severity
+func maxSeverity(a string, b string) string {
+	if severityRank(b) > severityRank(a) {
+		return b
+	}
+
+	return a
+}
+
+// contains checks if a string is in a slice
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+// findingRows formats the columns of the findings
+func findingRows(findings []FirewallFinding) [][]string {
+	rows := make([][]string, 0, len(findings))
+	for _, f := range findings {
+		instances := make([]string, 0, len(f.Instances))
+		for _, i := range f.Instances {
+			instances = append(instances, fmt.Sprintf("%s/%s/%s", i.Project, i.Zone, i.Name))
+		}
+		rows = append(rows, []string{
+			f.Severity,
+			f.Firewall.Project,
+			lastSegment(f.Firewall.Network),
+			f.Firewall.Name,
+			strings.Join(f.Exposed, ","),
+			formatIPs(f.Firewall.SourceRanges),
+			formatFirewallTargets(f.Firewall),
+			formatIPs(instances),
+		})
+	}
+
+	return rows
+}
+
+// The header of the finding rows
+var findingHeader = []string{"SEVERITY", "PROJECT", "NETWORK", "FIREWALL", "EXPOSED", "SOURCE RANGES", "TARGETS", "INSTANCES"}
@@ -0,0 +1,238 @@
+/*
+Copyright © 2022 tchiunam
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package compute
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestAuditFirewalls tests the AuditFirewalls function
+func TestAuditFirewalls(t *testing.T) {
+	network := "projects/proj1/global/networks/default"
+	instances := []InstanceInfo{
+		{Project: "proj1", Zone: "asia-east1-a", Name: "web-1", Networks: []string{network}, Tags: []string{"web"}},
+		{Project: "proj1", Zone: "asia-east1-a", Name: "bastion-1", Networks: []string{network}, Tags: []string{"ssh"}},
+		{Project: "proj1", Zone: "asia-east1-b", Name: "db-1", Networks: []string{network}, ServiceAccounts: []string{"db@proj1.iam.gserviceaccount.com"}},
+		{Project: "proj1", Zone: "asia-east1-b", Name: "other-1", Networks: []string{"projects/proj1/global/networks/vpc1"}, Tags: []string{"ssh"}},
+	}
+	open := func(name string, rules ...FirewallRule) FirewallInfo {
+		return FirewallInfo{Project: "proj1", Name: name, Network: network, Direction: "INGRESS", Action: "ALLOW", SourceRanges: []string{"0.0.0.0/0"}, Rules: rules}
+	}
+
+	cases := map[string]struct {
+		firewall    FirewallInfo
+		minSeverity string
+		severity    string
+		exposed     []string
+		instances   []string
+	}{
+		"ssh by target tag": {
+			firewall: func() FirewallInfo {
+				f := open("allow-ssh", FirewallRule{Protocol: "tcp", Ports: []string{"22"}})
+				f.TargetTags = []string{"ssh"}
+				return f
+			}(),
+			severity:  "HIGH",
+			exposed:   []string{"tcp:22 (SSH)"},
+			instances: []string{"bastion-1"},
+		},
+		"database by target service account": {
+			firewall: func() FirewallInfo {
+				f := open("allow-db", FirewallRule{Protocol: "6", Ports: []string{"5000-6000"}})
+				f.TargetServiceAccounts = []string{"db@proj1.iam.gserviceaccount.com"}
+				return f
+			}(),
+			severity:  "HIGH",
+			exposed:   []string{"tcp:5432 (PostgreSQL)", "tcp:5900 (VNC)"},
+			instances: []string{"db-1"},
+		},
+		"all ports without target": {
+			firewall:  open("allow-all", FirewallRule{Protocol: "all"}),
+			severity:  "CRITICAL",
+			exposed:   []string{"all:all"},
+			instances: []string{"web-1", "bastion-1", "db-1"},
+		},
+		"all tcp ports on ipv6": {
+			firewall: func() FirewallInfo {
+				f := open("allow-tcp", FirewallRule{Protocol: "tcp"})
+				f.SourceRanges = []string{"::/0"}
+				f.TargetTags = []string{"none"}
+				return f
+			}(),
+			severity: "CRITICAL",
+			exposed:  []string{"tcp:all"},
+		},
+		"default allow icmp": {
+			firewall: open("default-allow-icmp", FirewallRule{Protocol: "icmp"}),
+		},
+		"icmp and all udp ports": {
+			firewall:  open("allow-icmp-udp", FirewallRule{Protocol: "icmp"}, FirewallRule{Protocol: "17"}),
+			severity:  "CRITICAL",
+			exposed:   []string{"udp:all"},
+			instances: []string{"web-1", "bastion-1", "db-1"},
+		},
+		"esp and ah": {
+			firewall: open("allow-ipsec", FirewallRule{Protocol: "esp"}, FirewallRule{Protocol: "51"}),
+		},
+		"below min severity": {
+			firewall:    open("allow-ftp", FirewallRule{Protocol: "tcp", Ports: []string{"21"}}),
+			minSeverity: "HIGH",
+		},
+		"web ports": {
+			firewall: open("allow-web", FirewallRule{Protocol: "tcp", Ports: []string{"80", "443"}}),
+		},
+		"private source range": {
+			firewall: func() FirewallInfo {
+				f := open("allow-internal", FirewallRule{Protocol: "all"})
+				f.SourceRanges = []string{"10.0.0.0/8"}
+				return f
+			}(),
+		},
+		"disabled rule": {
+			firewall: func() FirewallInfo {
+				f := open("allow-rdp", FirewallRule{Protocol: "tcp", Ports: []string{"3389"}})
+				f.Disabled = true
+				return f
+			}(),
+		},
+		"deny rule": {
+			firewall: func() FirewallInfo {
+				f := open("deny-ssh", FirewallRule{Protocol: "tcp", Ports: []string{"22"}})
+				f.Action = "DENY"
+				return f
+			}(),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			minSeverity := tc.minSeverity
+			if minSeverity == "" {
+				minSeverity = "LOW"
+			}
+			findings := AuditFirewalls([]FirewallInfo{tc.firewall}, instances, defaultSensitivePorts, minSeverity)
+			if tc.severity == "" {
+				assert.Empty(t, findings)
+				return
+			}
+			assert.Len(t, findings, 1)
+			assert.Equal(t, tc.severity, findings[0].Severity)
+			assert.Equal(t, tc.exposed, findings[0].Exposed)
+			var names []string
+			for _, i := range findings[0].Instances {
+				names = append(names, i.Name)
+			}
+			assert.Equal(t, tc.instances, names)
+		})
+	}
+}
+
+// TestAuditFirewallsOrder tests that the findings are sorted by severity
+func TestAuditFirewallsOrder(t *testing.T) {
+	firewalls := []FirewallInfo{
+		{Name: "allow-ftp", Direction: "INGRESS", Action: "ALLOW", SourceRanges: []string{"0.0.0.0/0"}, Rules: []FirewallRule{{Protocol: "tcp", Ports: []string{"21"}}}},
+		{Name: "allow-all", Direction: "INGRESS", Action: "ALLOW", SourceRanges: []string{"0.0.0.0/0"}, Rules: []FirewallRule{{Protocol: "all"}}},
+		{Name: "allow-ssh", Direction: "INGRESS", Action: "ALLOW", SourceRanges: []string{"0.0.0.0/0"}, Rules: []FirewallRule{{Protocol: "tcp", Ports: []string{"22"}}}},
+	}
+
+	findings := AuditFirewalls(firewalls, nil, defaultSensitivePorts, "LOW")
+	var names []string
+	for _, f := range findings {
+		names = append(names, f.Firewall.Name)
+	}
+	assert.Equal(t, []string{"allow-all", "allow-ssh", "allow-ftp"}, names)
+
+	rows := findingRows(findings)
+	assert.Equal(t, []string{"CRITICAL", "", "", "allow-all", "all:all", "0.0.0.0/0", "all instances", "-"}, rows[0])
+	assert.Len(t, rows[0], len(findingHeader))
+}
+
+// TestParseSensitivePort tests the ParseSensitivePort function
+func TestParseSensitivePort(t *testing.T) {
+	cases := map[string]struct {
+		port     string
+		protocol string
+		number   int
+		valid    bool
+	}{
+		"default protocol": {
+			port:     "8443",
+			protocol: "tcp",
+			number:   8443,
+			valid:    true,
+		},
+		"udp": {
+			port:     "53/UDP",
+			protocol: "udp",
+			number:   53,
+			valid:    true,
+		},
+		"invalid protocol": {
+			port: "53/icmp",
+		},
+		"out of range": {
+			port: "70000",
+		},
+		"not a number": {
+			port: "ssh",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			p, err := ParseSensitivePort(tc.port)
+			if tc.valid {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.protocol, p.Protocol)
+				assert.Equal(t, tc.number, p.Port)
+				assert.Equal(t, "HIGH", p.Severity)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
+
+// TestPortInRanges tests the portInRanges function
+func TestPortInRanges(t *testing.T) {
+	cases := map[string]struct {
+		port   int
+		ranges []string
+		result bool
+	}{
+		"single port":    {port: 22, ranges: []string{"80", "22"}, result: true},
+		"port range":     {port: 3389, ranges: []string{"3000-4000"}, result: true},
+		"range boundary": {port: 4000, ranges: []string{"3000-4000"}, result: true},
+		"not in range":   {port: 22, ranges: []string{"80", "1000-2000"}},
+		"invalid range":  {port: 22, ranges: []string{"a-b"}},
+		"no port at all": {port: 22},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.result, portInRanges(tc.port, tc.ranges))
+		})
+	}
+}
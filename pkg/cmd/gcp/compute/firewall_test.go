@@ -0,0 +1,160 @@
+/*
+Copyright © 2022 tchiunam
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package compute
+
+import (
+	"testing"
+
+	"cloud.google.com/go/compute/apiv1/computepb"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+)
+
+// TestFirewallInfo tests the firewallInfo function
+func TestFirewallInfo(t *testing.T) {
+	cases := map[string]struct {
+		firewall *computepb.Firewall
+		info     FirewallInfo
+	}{
+		"allow rule": {
+			firewall: &computepb.Firewall{
+				Name:         proto.String("allow-ssh"),
+				Network:      proto.String("https://www.googleapis.com/compute/v1/projects/proj1/global/networks/default"),
+				Direction:    proto.String("INGRESS"),
+				Priority:     proto.Int32(1000),
+				SourceRanges: []string{"0.0.0.0/0"},
+				TargetTags:   []string{"ssh"},
+				Allowed: []*computepb.Allowed{
+					{IPProtocol: proto.String("tcp"), Ports: []string{"22"}},
+					{IPProtocol: proto.String("icmp")},
+				},
+			},
+			info: FirewallInfo{
+				Project:      "proj1",
+				Name:         "allow-ssh",
+				Network:      "projects/proj1/global/networks/default",
+				Direction:    "INGRESS",
+				Priority:     1000,
+				Action:       "ALLOW",
+				SourceRanges: []string{"0.0.0.0/0"},
+				TargetTags:   []string{"ssh"},
+				Rules: []FirewallRule{
+					{Protocol: "tcp", Ports: []string{"22"}},
+					{Protocol: "icmp"},
+				},
+			},
+		},
+		"disabled deny rule": {
+			firewall: &computepb.Firewall{
+				Name:              proto.String("deny-smtp"),
+				Network:           proto.String("https://www.googleapis.com/compute/v1/projects/proj1/global/networks/vpc1"),
+				Direction:         proto.String("EGRESS"),
+				Priority:          proto.Int32(100),
+				DestinationRanges: []string{"0.0.0.0/0"},
+				Disabled:          proto.Bool(true),
+				Denied:            []*computepb.Denied{{IPProtocol: proto.String("tcp"), Ports: []string{"25"}}},
+			},
+			info: FirewallInfo{
+				Project:           "proj1",
+				Name:              "deny-smtp",
+				Network:           "projects/proj1/global/networks/vpc1",
+				Direction:         "EGRESS",
+				Priority:          100,
+				Action:            "DENY",
+				DestinationRanges: []string{"0.0.0.0/0"},
+				Disabled:          true,
+				Rules:             []FirewallRule{{Protocol: "tcp", Ports: []string{"25"}}},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.info, firewallInfo("proj1", tc.firewall))
+		})
+	}
+}
+
+// TestFirewallRows tests the firewallRows function
+func TestFirewallRows(t *testing.T) {
+	cases := map[string]struct {
+		firewall FirewallInfo
+		row      []string
+	}{
+		"ingress rule with targets": {
+			firewall: FirewallInfo{
+				Project:               "proj1",
+				Name:                  "allow-web",
+				Network:               "projects/proj1/global/networks/default",
+				Direction:             "INGRESS",
+				Priority:              1000,
+				Action:                "ALLOW",
+				Rules:                 []FirewallRule{{Protocol: "tcp", Ports: []string{"80", "443"}}, {Protocol: "udp", Ports: []string{"8000-8999"}}},
+				SourceRanges:          []string{"0.0.0.0/0"},
+				TargetTags:            []string{"web"},
+				TargetServiceAccounts: []string{"web@proj1.iam.gserviceaccount.com"},
+			},
+			row: []string{"proj1", "default", "allow-web", "INGRESS", "1000", "ALLOW", "tcp:80,443;udp:8000-8999", "0.0.0.0/0", "tag:web,serviceAccount:web@proj1.iam.gserviceaccount.com", "false"},
+		},
+		"egress rule without targets": {
+			firewall: FirewallInfo{
+				Project:           "proj1",
+				Name:              "deny-all",
+				Network:           "projects/proj1/global/networks/vpc1",
+				Direction:         "EGRESS",
+				Priority:          65534,
+				Action:            "DENY",
+				Rules:             []FirewallRule{{Protocol: "all"}},
+				DestinationRanges: []string{"0.0.0.0/0"},
+				Disabled:          true,
+			},
+			row: []string{"proj1", "vpc1", "deny-all", "EGRESS", "65534", "DENY", "all", "0.0.0.0/0", "all instances", "true"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			rows := firewallRows([]FirewallInfo{tc.firewall})
+			assert.Equal(t, [][]string{tc.row}, rows)
+			assert.Len(t, tc.row, len(firewallHeader))
+		})
+	}
+}
+
+// TestSortFirewalls tests the sortFirewalls function
+func TestSortFirewalls(t *testing.T) {
+	firewalls := []FirewallInfo{
+		{Project: "proj2", Network: "n1", Priority: 1, Name: "a"},
+		{Project: "proj1", Network: "n1", Priority: 1000, Name: "a"},
+		{Project: "proj1", Network: "n1", Priority: 100, Name: "b"},
+		{Project: "proj1", Network: "n1", Priority: 100, Name: "a"},
+	}
+	sortFirewalls(firewalls)
+
+	names := make([]string, 0, len(firewalls))
+	for _, f := range firewalls {
+		names = append(names, f.Project+"/"+f.Name)
+	}
+	assert.Equal(t, []string{"proj1/a", "proj1/b", "proj1/a", "proj2/a"}, names)
+	assert.Equal(t, int32(1000), firewalls[2].Priority)
+}
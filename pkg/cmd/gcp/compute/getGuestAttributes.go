@@ -0,0 +1,102 @@
+/*
+Copyright © 2022 tchiunam
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package compute
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/tchiunam/axolgo-cli/pkg/util"
+)
+
+var (
+	getGuestAttributesLong = `Print the guest attributes of a compute engine instance. They are
+written by the guest environment of the instance, e.g. its SSH host
+keys, and by applications. The metadata enable-guest-attributes must be
+TRUE on the instance or its project.
+`
+	getGuestAttributesExample = `  # Print all the guest attributes of an instance
+  axolgo gcp compute getGuestAttributes web-1 --project proj1 --zone asia-east1-a
+
+  # Print the SSH host keys of an instance as JSON
+  axolgo gcp compute getGuestAttributes web-1 --zone asia-east1-a --query-path hostkeys/ -o json
+`
+)
+
+// GetGuestAttributesOptions defines flags and other configuration parameters for the `getGuestAttributes` command
+type GetGuestAttributesOptions struct {
+	Project   string
+	Zone      string
+	QueryPath string
+	Output    string
+}
+
+// NewCmdGetGuestAttributes creates the `getGuestAttributes` command
+func NewCmdGetGuestAttributes(ctx *context.Context) *cobra.Command {
+	o := GetGuestAttributesOptions{}
+
+	cmd := &cobra.Command{
+		Use:                   "getGuestAttributes NAME [-p] [-z] [--query-path] [-o]",
+		DisableFlagsInUseLine: true,
+		Short:                 "Print the guest attributes of an instance.",
+		Long:                  getGuestAttributesLong,
+		Example:               getGuestAttributesExample,
+		Args:                  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := o.complete(ctx, cmd, args); err != nil {
+				panic(err)
+			}
+		},
+	}
+
+	cmd.Flags().StringVarP(&o.Project, "project", "p", "", "Project ID. Default is the project in axolgo configuration.")
+	cmd.Flags().StringVarP(&o.Zone, "zone", "z", "", "Zone.")
+	cmd.Flags().StringVar(&o.QueryPath, "query-path", "", "Namespace, e.g. hostkeys/, or full key of the attributes. Default is all the attributes.")
+	cmd.Flags().StringVarP(&o.Output, "output", "o", util.OutputTable, fmt.Sprintf("Output format. One of %v.", util.OutputFormats))
+
+	return cmd
+}
+
+// Complete takes the command arguments and execute.
+func (o *GetGuestAttributesOptions) complete(ctx *context.Context, _ *cobra.Command, args []string) error {
+	project, err := util.GCPProject(o.Project)
+	if err != nil {
+		return err
+	}
+	zone := util.GCPZone(o.Zone)
+
+	c, err := newInstancesClient(context.TODO())
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+
+	attributes, err := GetGuestAttributes(context.TODO(), c, project, zone, args[0], o.QueryPath)
+	if err != nil {
+		return fmt.Errorf("failed to get the guest attributes of %s: %w", args[0], err)
+	}
+
+	return util.PrintRecords(os.Stdout, o.Output, guestAttributeHeader, guestAttributeRows(attributes))
+}
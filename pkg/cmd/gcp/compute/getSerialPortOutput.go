@@ -0,0 +1,125 @@
+/*
+Copyright © 2022 tchiunam
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package compute
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/tchiunam/axolgo-cli/pkg/util"
+	"k8s.io/klog/v2"
+)
+
+var (
+	getSerialPortOutputLong = `Print the output of a serial port of a compute engine instance, which
+is useful to find out why an instance fails to boot. Port 1 is the
+console of the instance. An instance keeps the last 1 MiB of output
+only.
+
+With --follow, the new output is printed as it comes, like tail -f,
+until it is interrupted. A failed read is logged and retried in the
+next interval.
+`
+	getSerialPortOutputExample = `  # Print the console output of an instance
+  axolgo gcp compute getSerialPortOutput web-1 --project proj1 --zone asia-east1-a
+
+  # Follow the output of serial port 2 from the start
+  axolgo gcp compute getSerialPortOutput web-1 --zone asia-east1-a --port 2 --start 0 --follow
+`
+)
+
+// GetSerialPortOutputOptions defines flags and other configuration parameters for the `getSerialPortOutput` command
+type GetSerialPortOutputOptions struct {
+	Project  string
+	Zone     string
+	Port     int32
+	Start    int64
+	Follow   bool
+	Interval time.Duration
+}
+
+// NewCmdGetSerialPortOutput creates the `getSerialPortOutput` command
+func NewCmdGetSerialPortOutput(ctx *context.Context) *cobra.Command {
+	o := GetSerialPortOutputOptions{}
+
+	cmd := &cobra.Command{
+		Use:                   "getSerialPortOutput NAME [-p] [-z] [--port] [--start] [--follow] [--interval]",
+		DisableFlagsInUseLine: true,
+		Short:                 "Print the output of a serial port of an instance.",
+		Long:                  getSerialPortOutputLong,
+		Example:               getSerialPortOutputExample,
+		Args:                  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := o.complete(ctx, cmd, args); err != nil {
+				panic(err)
+			}
+		},
+	}
+
+	cmd.Flags().StringVarP(&o.Project, "project", "p", "", "Project ID. Default is the project in axolgo configuration.")
+	cmd.Flags().StringVarP(&o.Zone, "zone", "z", "", "Zone.")
+	cmd.Flags().Int32Var(&o.Port, "port", 1, fmt.Sprintf("Serial port. One of %d to %d.", minSerialPort, maxSerialPort))
+	cmd.Flags().Int64Var(&o.Start, "start", 0, "Byte offset of the output to start from.")
+	cmd.Flags().BoolVar(&o.Follow, "follow", false, "Keep printing the new output until it is interrupted.")
+	cmd.Flags().DurationVar(&o.Interval, "interval", 5*time.Second, "Interval to read the new output in follow mode.")
+
+	return cmd
+}
+
+// Complete takes the command arguments and execute.
+func (o *GetSerialPortOutputOptions) complete(ctx *context.Context, _ *cobra.Command, args []string) error {
+	if err := validateSerialPort(o.Port); err != nil {
+		return err
+	}
+	if o.Start < 0 {
+		return fmt.Errorf("invalid start: %d", o.Start)
+	}
+	if o.Interval <= 0 {
+		return fmt.Errorf("invalid interval: %s", o.Interval)
+	}
+	project, err := util.GCPProject(o.Project)
+	if err != nil {
+		return err
+	}
+	zone := util.GCPZone(o.Zone)
+
+	c, err := newInstancesClient(context.TODO())
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+
+	tailCtx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	next, err := TailSerialPortOutput(tailCtx, NewSerialPortReader(c, project, zone, args[0], o.Port), o.Start, o.Follow, o.Interval, os.Stdout)
+	if err != nil {
+		return fmt.Errorf("failed to get the output of serial port %d of %s: %w", o.Port, args[0], err)
+	}
+	klog.V(1).Infof("The next byte offset of serial port %d is %d", o.Port, next)
+
+	return nil
+}
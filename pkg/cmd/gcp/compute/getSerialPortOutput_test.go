@@ -0,0 +1,84 @@
+/*
+Copyright © 2022 tchiunam
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package compute
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestNewCmdGetSerialPortOutput tests the NewCmdGetSerialPortOutput function
+// to make sure it returns a valid command.
+func TestNewCmdGetSerialPortOutput(t *testing.T) {
+	cases := map[string]struct {
+		use      string
+		short    string
+		hasFlags bool
+	}{
+		"valid command": {
+			use:      "getSerialPortOutput NAME [-p] [-z] [--port] [--start] [--follow] [--interval]",
+			short:    "Print the output of a serial port of an instance.",
+			hasFlags: true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cmd := NewCmdGetSerialPortOutput(nil)
+			assert.Equal(t, tc.use, cmd.Use)
+			assert.Equal(t, tc.short, cmd.Short)
+			assert.Equal(t, tc.hasFlags, cmd.Flags().HasFlags())
+		})
+	}
+}
+
+// TestNewCmdGetSerialPortOutputInvalid calls the NewCmdGetSerialPortOutput function
+// with invalid input and makes sure it returns an error.
+func TestNewCmdGetSerialPortOutputInvalid(t *testing.T) {
+	cases := map[string]struct {
+		args []string
+	}{
+		"invalid port": {
+			args: []string{"web-1", "--project", "proj1", "--zone", "asia-east1-a", "--port", "5"},
+		},
+		"invalid start": {
+			args: []string{"web-1", "--project", "proj1", "--zone", "asia-east1-a", "--start", "-1"},
+		},
+		"invalid interval": {
+			args: []string{"web-1", "--project", "proj1", "--zone", "asia-east1-a", "--follow", "--interval", "0s"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			oldArgs := os.Args
+			defer func() { os.Args = oldArgs }()
+			os.Args = append([]string{"cmd"}, tc.args...)
+
+			cmd := NewCmdGetSerialPortOutput(nil)
+			assert.Panics(t, func() { cmd.Execute() })
+		})
+	}
+}
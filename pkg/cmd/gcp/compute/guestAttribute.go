@@ -0,0 +1,95 @@
+/*
+Copyright © 2022 tchiunam
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package compute
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	compute "cloud.google.com/go/compute/apiv1"
+	"cloud.google.com/go/compute/apiv1/computepb"
+)
+
+// GuestAttribute is a guest attribute which the guest environment of
+// an instance writes, e.g. the SSH host keys in namespace hostkeys
+type GuestAttribute struct {
+	Namespace string
+	Key       string
+	Value     string
+}
+
+// GetGuestAttributes gets the guest attributes of an instance under a
+// query path, which is a namespace such as hostkeys/ or a full key. All
+// the guest attributes are returned if the query path is empty. They
+// are sorted by namespace and key.
+func GetGuestAttributes(ctx context.Context, c *compute.InstancesClient, project string, zone string, instance string, queryPath string) ([]GuestAttribute, error) {
+	req := &computepb.GetGuestAttributesInstanceRequest{
+		Project:  project,
+		Zone:     zone,
+		Instance: instance,
+	}
+	if queryPath != "" {
+		req.QueryPath = &queryPath
+	}
+	resp, err := c.GetGuestAttributes(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return guestAttributes(resp), nil
+}
+
+// guestAttributes converts the guest attributes of a query
+func guestAttributes(resp *computepb.GuestAttributes) []GuestAttribute {
+	attributes := make([]GuestAttribute, 0, len(resp.GetQueryValue().GetItems()))
+	for _, item := range resp.GetQueryValue().GetItems() {
+		attributes = append(attributes, GuestAttribute{Namespace: item.GetNamespace(), Key: item.GetKey(), Value: item.GetValue()})
+	}
+	// A variable key has its value only
+	if key := resp.GetVariableKey(); key != "" && len(attributes) == 0 {
+		namespace, name, _ := strings.Cut(key, "/")
+		attributes = append(attributes, GuestAttribute{Namespace: namespace, Key: name, Value: resp.GetVariableValue()})
+	}
+	sort.SliceStable(attributes, func(i, j int) bool {
+		if attributes[i].Namespace != attributes[j].Namespace {
+			return attributes[i].Namespace < attributes[j].Namespace
+		}
+		return attributes[i].Key < attributes[j].Key
+	})
+
+	return attributes
+}
+
+// guestAttributeRows formats the columns of the guest attributes
+func guestAttributeRows(attributes []GuestAttribute) [][]string {
+	rows := make([][]string, 0, len(attributes))
+	for _, a := range attributes {
+		rows = append(rows, []string{a.Namespace, a.Key, a.Value})
+	}
+
+	return rows
+}
+
+// The header of the guest attribute rows
+var guestAttributeHeader = []string{"NAMESPACE", "KEY", "VALUE"}
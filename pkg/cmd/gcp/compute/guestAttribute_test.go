@@ -0,0 +1,74 @@
+/*
+Copyright © 2022 tchiunam
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package compute
+
+import (
+	"testing"
+
+	"cloud.google.com/go/compute/apiv1/computepb"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+)
+
+// TestGuestAttributes tests the guestAttributes and guestAttributeRows functions
+func TestGuestAttributes(t *testing.T) {
+	cases := map[string]struct {
+		resp *computepb.GuestAttributes
+		rows [][]string
+	}{
+		"query path": {
+			resp: &computepb.GuestAttributes{
+				QueryPath: proto.String("hostkeys/"),
+				QueryValue: &computepb.GuestAttributesValue{Items: []*computepb.GuestAttributesEntry{
+					{Namespace: proto.String("hostkeys"), Key: proto.String("ssh-rsa"), Value: proto.String("AAAAB3")},
+					{Namespace: proto.String("hostkeys"), Key: proto.String("ssh-ed25519"), Value: proto.String("AAAAC3")},
+				}},
+			},
+			rows: [][]string{
+				{"hostkeys", "ssh-ed25519", "AAAAC3"},
+				{"hostkeys", "ssh-rsa", "AAAAB3"},
+			},
+		},
+		"variable key": {
+			resp: &computepb.GuestAttributes{
+				VariableKey:   proto.String("app/ready"),
+				VariableValue: proto.String("true"),
+			},
+			rows: [][]string{{"app", "ready", "true"}},
+		},
+		"no attributes": {
+			resp: &computepb.GuestAttributes{},
+			rows: [][]string{},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			rows := guestAttributeRows(guestAttributes(tc.resp))
+			assert.Equal(t, tc.rows, rows)
+			for _, r := range rows {
+				assert.Len(t, r, len(guestAttributeHeader))
+			}
+		})
+	}
+}
@@ -0,0 +1,274 @@
+/*
+Copyright © 2022 tchiunam
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package compute
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	compute "cloud.google.com/go/compute/apiv1"
+	"cloud.google.com/go/compute/apiv1/computepb"
+	"github.com/tchiunam/axolgo-cli/pkg/util"
+	"google.golang.org/api/iterator"
+	"k8s.io/klog/v2"
+)
+
+// InstanceInfo is a compute engine instance
+type InstanceInfo struct {
+	Project     string
+	Zone        string
+	Name        string
+	ID          uint64
+	Status      string
+	MachineType string
+	InternalIPs []string
+	ExternalIPs []string
+	Labels      map[string]string
+	// Networks are the network paths of the network interfaces, e.g.
+	// projects/proj1/global/networks/default
+	Networks        []string
+	Tags            []string
+	ServiceAccounts []string
+	// Disks are the paths of the attached disks, e.g.
+	// projects/proj1/zones/asia-east1-a/disks/web-1
+	Disks []string
+}
+
+// ProjectInstances is the result of listing the instances of a project
+type ProjectInstances struct {
+	Project   string
+	Instances []InstanceInfo
+	Err       error
+}
+
+// ListInstances lists the instances of a project in a zone, or in all
+// zones with the aggregated list if allZones is true
+func ListInstances(ctx context.Context, client *compute.InstancesClient, project string, zone string, filter string, maxResults int32, allZones bool) ([]InstanceInfo, error) {
+	var max *uint32
+	if maxResults > 0 {
+		m := uint32(maxResults)
+		max = &m
+	}
+	var f *string
+	if filter != "" {
+		f = &filter
+	}
+
+	var instances []InstanceInfo
+	if allZones {
+		it := client.AggregatedList(ctx, &computepb.AggregatedListInstancesRequest{
+			Project:    project,
+			Filter:     f,
+			MaxResults: max,
+		})
+		for {
+			pair, err := it.Next()
+			if err == iterator.Done {
+				break
+			}
+			if err != nil {
+				return nil, err
+			}
+			for _, instance := range pair.Value.GetInstances() {
+				instances = append(instances, instanceInfo(project, instance))
+			}
+		}
+	} else {
+		it := client.List(ctx, &computepb.ListInstancesRequest{
+			Project:    project,
+			Zone:       zone,
+			Filter:     f,
+			MaxResults: max,
+		})
+		for {
+			instance, err := it.Next()
+			if err == iterator.Done {
+				break
+			}
+			if err != nil {
+				return nil, err
+			}
+			instances = append(instances, instanceInfo(project, instance))
+		}
+	}
+	sortInstances(instances)
+
+	return instances, nil
+}
+
+// ListProjectsInstances runs list for each project concurrently with at
+// most concurrency projects at a time. The result of a project has the
+// error if it fails so that a project does not abort the others.
+func ListProjectsInstances(ctx context.Context, projects []string, concurrency int, list func(context.Context, string) ([]InstanceInfo, error)) []ProjectInstances {
+	results := make([]ProjectInstances, len(projects))
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i, project := range projects {
+		wg.Add(1)
+		go func(i int, project string) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			klog.V(3).InfoS("List instances", "project", project)
+			instances, err := list(ctx, project)
+			results[i] = ProjectInstances{Project: project, Instances: instances, Err: err}
+		}(i, project)
+	}
+	wg.Wait()
+
+	return results
+}
+
+// instanceInfo converts an instance
+func instanceInfo(project string, instance *computepb.Instance) InstanceInfo {
+	info := InstanceInfo{
+		Project:     project,
+		Zone:        lastSegment(instance.GetZone()),
+		Name:        instance.GetName(),
+		ID:          instance.GetId(),
+		Status:      instance.GetStatus(),
+		MachineType: lastSegment(instance.GetMachineType()),
+		Labels:      instance.GetLabels(),
+		Tags:        instance.GetTags().GetItems(),
+	}
+	for _, sa := range instance.GetServiceAccounts() {
+		info.ServiceAccounts = append(info.ServiceAccounts, sa.GetEmail())
+	}
+	for _, d := range instance.GetDisks() {
+		if source := d.GetSource(); source != "" {
+			info.Disks = append(info.Disks, resourcePath(source))
+		}
+	}
+	for _, nic := range instance.GetNetworkInterfaces() {
+		if network := nic.GetNetwork(); network != "" {
+			info.Networks = append(info.Networks, resourcePath(network))
+		}
+		if ip := nic.GetNetworkIP(); ip != "" {
+			info.InternalIPs = append(info.InternalIPs, ip)
+		}
+		for _, ac := range nic.GetAccessConfigs() {
+			if ip := ac.GetNatIP(); ip != "" {
+				info.ExternalIPs = append(info.ExternalIPs, ip)
+			}
+		}
+	}
+
+	return info
+}
+
+// sortInstances sorts the instances by project, zone and name
+func sortInstances(instances []InstanceInfo) {
+	sort.SliceStable(instances, func(i, j int) bool {
+		a, b := instances[i], instances[j]
+		if a.Project != b.Project {
+			return a.Project < b.Project
+		}
+		if a.Zone != b.Zone {
+			return a.Zone < b.Zone
+		}
+		return a.Name < b.Name
+	})
+}
+
+// lastSegment returns the last segment of a resource URL, e.g. the
+// zone name of a zone URL
+func lastSegment(url string) string {
+	return url[strings.LastIndex(url, "/")+1:]
+}
+
+// resourcePath returns the path of a resource URL from the projects
+// segment, e.g. projects/proj1/global/networks/default, so that URLs
+// of different API versions are comparable
+func resourcePath(url string) string {
+	if i := strings.Index(url, "projects/"); i >= 0 {
+		return url[i:]
+	}
+
+	return url
+}
+
+// formatLabels formats labels as KEY=VALUE pairs sorted by key
+func formatLabels(labels map[string]string) string {
+	if len(labels) == 0 {
+		return "-"
+	}
+	pairs := make([]string, 0, len(labels))
+	for k, v := range labels {
+		pairs = append(pairs, fmt.Sprintf("%s=%s", k, v))
+	}
+	sort.Strings(pairs)
+
+	return strings.Join(pairs, ",")
+}
+
+// formatIPs formats IP addresses
+func formatIPs(ips []string) string {
+	if len(ips) == 0 {
+		return "-"
+	}
+
+	return strings.Join(ips, ",")
+}
+
+// instanceRows formats the columns of the instances
+func instanceRows(instances []InstanceInfo) [][]string {
+	rows := make([][]string, 0, len(instances))
+	for _, i := range instances {
+		rows = append(rows, []string{
+			i.Project,
+			i.Zone,
+			i.Name,
+			strconv.FormatUint(i.ID, 10),
+			i.Status,
+			i.MachineType,
+			formatIPs(i.InternalIPs),
+			formatIPs(i.ExternalIPs),
+			formatLabels(i.Labels),
+		})
+	}
+
+	return rows
+}
+
+// The header of the instance rows
+var instanceHeader = []string{"PROJECT", "ZONE", "NAME", "ID", "STATUS", "MACHINE TYPE", "INTERNAL IP", "EXTERNAL IP", "LABELS"}
+
+// newInstancesClient creates a compute engine instances client with the
+// GCP client options
+func newInstancesClient(ctx context.Context) (*compute.InstancesClient, error) {
+	opts, err := util.GCPClientOptions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	c, err := compute.NewInstancesRESTClient(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create compute engine client: %w", err)
+	}
+
+	return c, nil
+}
@@ -0,0 +1,261 @@
+/*
+Copyright © 2022 tchiunam
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package compute
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	compute "cloud.google.com/go/compute/apiv1"
+	"cloud.google.com/go/compute/apiv1/computepb"
+	"github.com/spf13/cobra"
+	"github.com/tchiunam/axolgo-cli/pkg/util"
+	"k8s.io/klog/v2"
+)
+
+// instanceAction is a lifecycle action on an instance
+type instanceAction struct {
+	// Verb of the action, e.g. stop
+	verb string
+	// do requests the action and returns the zone operation
+	do func(ctx context.Context, c *compute.InstancesClient, project string, zone string, name string) (*compute.Operation, error)
+}
+
+// The lifecycle actions of instances
+var (
+	startAction = instanceAction{
+		verb: "start",
+		do: func(ctx context.Context, c *compute.InstancesClient, project string, zone string, name string) (*compute.Operation, error) {
+			return c.Start(ctx, &computepb.StartInstanceRequest{Project: project, Zone: zone, Instance: name})
+		},
+	}
+	stopAction = instanceAction{
+		verb: "stop",
+		do: func(ctx context.Context, c *compute.InstancesClient, project string, zone string, name string) (*compute.Operation, error) {
+			return c.Stop(ctx, &computepb.StopInstanceRequest{Project: project, Zone: zone, Instance: name})
+		},
+	}
+	resetAction = instanceAction{
+		verb: "reset",
+		do: func(ctx context.Context, c *compute.InstancesClient, project string, zone string, name string) (*compute.Operation, error) {
+			return c.Reset(ctx, &computepb.ResetInstanceRequest{Project: project, Zone: zone, Instance: name})
+		},
+	}
+	suspendAction = instanceAction{
+		verb: "suspend",
+		do: func(ctx context.Context, c *compute.InstancesClient, project string, zone string, name string) (*compute.Operation, error) {
+			return c.Suspend(ctx, &computepb.SuspendInstanceRequest{Project: project, Zone: zone, Instance: name})
+		},
+	}
+	resumeAction = instanceAction{
+		verb: "resume",
+		do: func(ctx context.Context, c *compute.InstancesClient, project string, zone string, name string) (*compute.Operation, error) {
+			return c.Resume(ctx, &computepb.ResumeInstanceRequest{Project: project, Zone: zone, Instance: name})
+		},
+	}
+	deleteAction = instanceAction{
+		verb: "delete",
+		do: func(ctx context.Context, c *compute.InstancesClient, project string, zone string, name string) (*compute.Operation, error) {
+			return c.Delete(ctx, &computepb.DeleteInstanceRequest{Project: project, Zone: zone, Instance: name})
+		},
+	}
+)
+
+// InstanceActionOptions defines flags and other configuration parameters for the instance lifecycle commands
+type InstanceActionOptions struct {
+	Project     string
+	Zone        string
+	AllZones    bool
+	Filter      InstanceFilter
+	Yes         bool
+	Wait        time.Duration
+	Concurrency int
+
+	action instanceAction
+}
+
+// The usage of the flags of the instance lifecycle commands
+const instanceActionFlagsUsage = "[-p] [-z] [--all-zones] [-i] [-n] [-l] [-s] [-m] [--network-ip] [-f] [-y] [--wait] [--concurrency]"
+
+// newInstanceActionCmd creates an instance lifecycle command
+func newInstanceActionCmd(ctx *context.Context, action instanceAction, use string, short string, long string, example string) *cobra.Command {
+	o := InstanceActionOptions{action: action}
+
+	cmd := &cobra.Command{
+		Use:                   use + " " + instanceActionFlagsUsage,
+		DisableFlagsInUseLine: true,
+		Short:                 short,
+		Long:                  long,
+		Example:               example,
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := o.complete(ctx, cmd, args); err != nil {
+				panic(err)
+			}
+		},
+	}
+
+	cmd.Flags().StringVarP(&o.Project, "project", "p", "", "Project ID. Default is the project in axolgo configuration.")
+	cmd.Flags().StringVarP(&o.Zone, "zone", "z", "", "Zone.")
+	cmd.Flags().BoolVar(&o.AllZones, "all-zones", false, "Select the instances in all zones.")
+	addFilterFlags(cmd, &o.Filter)
+	cmd.Flags().BoolVarP(&o.Yes, "yes", "y", false, "Do not ask for confirmation.")
+	cmd.Flags().DurationVar(&o.Wait, "wait", 5*time.Minute, "Max. time to wait for an operation to be done. 0 is not to wait.")
+	cmd.Flags().IntVar(&o.Concurrency, "concurrency", 4, "Max. no. of instances to act on at the same time.")
+
+	return cmd
+}
+
+// Complete takes the command arguments and execute.
+func (o *InstanceActionOptions) complete(ctx *context.Context, _ *cobra.Command, args []string) error {
+	if o.AllZones && o.Zone != "" {
+		return fmt.Errorf("--zone cannot be used with --all-zones")
+	}
+	if o.Concurrency < 1 {
+		return fmt.Errorf("invalid concurrency: %d", o.Concurrency)
+	}
+	if o.Wait < 0 {
+		return fmt.Errorf("invalid wait: %s", o.Wait)
+	}
+	f, err := o.Filter.String()
+	if err != nil {
+		return err
+	}
+	// Never act on all the instances of a zone by accident
+	if f == "" {
+		return fmt.Errorf("a filter is required to select the instances to %s", o.action.verb)
+	}
+
+	project, err := util.GCPProject(o.Project)
+	if err != nil {
+		return err
+	}
+	zone := ""
+	if !o.AllZones {
+		zone = util.GCPZone(o.Zone)
+	}
+
+	c, err := newInstancesClient(context.TODO())
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+
+	instances, err := ListInstances(context.TODO(), c, project, zone, f, 0, o.AllZones)
+	if err != nil {
+		return fmt.Errorf("failed to list compute engine instances: %w", err)
+	}
+	if len(instances) == 0 {
+		klog.Infof("No instance matches the filter: %s", f)
+		return nil
+	}
+	if err := util.PrintTable(os.Stdout, instanceHeader, instanceRows(instances)); err != nil {
+		return err
+	}
+
+	if !o.Yes {
+		confirmed, err := util.Confirm(os.Stdin, os.Stdout, fmt.Sprintf("Do you want to %s %d instance(s)?", o.action.verb, len(instances)))
+		if err != nil {
+			return err
+		}
+		if !confirmed {
+			klog.Info("Cancelled")
+			return nil
+		}
+	}
+
+	errs := RunInstanceActions(context.TODO(), instances, o.Concurrency, func(ctx context.Context, i InstanceInfo) error {
+		return o.run(ctx, c, i)
+	})
+	var failures []string
+	for i, err := range errs {
+		if err != nil {
+			klog.Errorf("Failed to %s instance %s: %v", o.action.verb, instances[i].Name, err)
+			failures = append(failures, instances[i].Name)
+		}
+	}
+	if len(failures) > 0 {
+		return fmt.Errorf("failed to %s instance(s): %s", o.action.verb, strings.Join(failures, ", "))
+	}
+
+	return nil
+}
+
+// run acts on an instance and waits for the operation to be done
+func (o *InstanceActionOptions) run(ctx context.Context, c *compute.InstancesClient, i InstanceInfo) error {
+	op, err := o.action.do(ctx, c, i.Project, i.Zone, i.Name)
+	if err != nil {
+		return err
+	}
+	if o.Wait == 0 {
+		klog.Infof("Requested to %s instance %s", o.action.verb, i.Name)
+		return nil
+	}
+
+	if err := waitOperation(ctx, op, o.Wait); err != nil {
+		return err
+	}
+	klog.Infof("Instance %s: %s done", i.Name, o.action.verb)
+
+	return nil
+}
+
+// waitOperation waits for an operation to be done within a timeout and
+// returns the error of the operation if it fails
+func waitOperation(ctx context.Context, op *compute.Operation, timeout time.Duration) error {
+	waitCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	if err := op.Wait(waitCtx); err != nil {
+		return fmt.Errorf("operation %s is not done: %w", op.Name(), err)
+	}
+	if opErr := op.Proto().GetError(); opErr != nil && len(opErr.GetErrors()) > 0 {
+		return fmt.Errorf("operation %s failed: %s", op.Name(), opErr.GetErrors()[0].GetMessage())
+	}
+
+	return nil
+}
+
+// RunInstanceActions runs an action on each instance concurrently with
+// at most concurrency instances at a time. The error of each instance
+// is returned in the same order as the instances.
+func RunInstanceActions(ctx context.Context, instances []InstanceInfo, concurrency int, action func(context.Context, InstanceInfo) error) []error {
+	errs := make([]error, len(instances))
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i, instance := range instances {
+		wg.Add(1)
+		go func(i int, instance InstanceInfo) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			errs[i] = action(ctx, instance)
+		}(i, instance)
+	}
+	wg.Wait()
+
+	return errs
+}
@@ -0,0 +1,158 @@
+/*
+Copyright © 2022 tchiunam
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package compute
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync/atomic"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+)
+
+// instanceActionCmds are the constructors of the instance action commands
+var instanceActionCmds = map[string]func(*context.Context) *cobra.Command{
+	"startInstances":   NewCmdStartInstances,
+	"stopInstances":    NewCmdStopInstances,
+	"resetInstances":   NewCmdResetInstances,
+	"suspendInstances": NewCmdSuspendInstances,
+	"resumeInstances":  NewCmdResumeInstances,
+	"deleteInstances":  NewCmdDeleteInstances,
+}
+
+// TestNewCmdInstanceActions tests the constructors of the instance
+// action commands to make sure they return valid commands.
+func TestNewCmdInstanceActions(t *testing.T) {
+	cases := map[string]struct {
+		short string
+	}{
+		"startInstances":   {short: "Start compute engine instances."},
+		"stopInstances":    {short: "Stop compute engine instances."},
+		"resetInstances":   {short: "Reset compute engine instances."},
+		"suspendInstances": {short: "Suspend compute engine instances."},
+		"resumeInstances":  {short: "Resume compute engine instances."},
+		"deleteInstances":  {short: "Delete compute engine instances."},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cmd := instanceActionCmds[name](nil)
+			assert.Equal(t, name+" [-p] [-z] [--all-zones] [-i] [-n] [-l] [-s] [-m] [--network-ip] [-f] [-y] [--wait] [--concurrency]", cmd.Use)
+			assert.Equal(t, tc.short, cmd.Short)
+			assert.True(t, cmd.Flags().HasFlags())
+		})
+	}
+}
+
+// TestNewCmdInstanceActionsInvalid calls the constructors of the instance
+// action commands with invalid input and makes sure they return an error.
+func TestNewCmdInstanceActionsInvalid(t *testing.T) {
+	cases := map[string]struct {
+		args []string
+	}{
+		"no filter": {
+			args: []string{"--project", "proj1", "--zone", "asia-east1-a"},
+		},
+		"zone with all zones": {
+			args: []string{"--project", "proj1", "--zone", "asia-east1-a", "--all-zones", "--name", "web-1"},
+		},
+		"invalid status": {
+			args: []string{"--project", "proj1", "--status", "DELETED"},
+		},
+		"invalid concurrency": {
+			args: []string{"--project", "proj1", "--name", "web-1", "--concurrency", "0"},
+		},
+	}
+
+	for action, newCmd := range instanceActionCmds {
+		for name, tc := range cases {
+			t.Run(action+"/"+name, func(t *testing.T) {
+				oldArgs := os.Args
+				defer func() { os.Args = oldArgs }()
+				os.Args = append([]string{"cmd"}, tc.args...)
+
+				cmd := newCmd(nil)
+				assert.Panics(t, func() { cmd.Execute() })
+			})
+		}
+	}
+}
+
+// TestRunInstanceActions tests the RunInstanceActions function
+func TestRunInstanceActions(t *testing.T) {
+	cases := map[string]struct {
+		names       []string
+		concurrency int
+		failed      map[string]bool
+	}{
+		"all instances succeed": {
+			names:       []string{"web-1", "web-2", "web-3", "web-4"},
+			concurrency: 2,
+		},
+		"an instance fails": {
+			names:       []string{"web-1", "web-2", "web-3"},
+			concurrency: 1,
+			failed:      map[string]bool{"web-2": true},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			instances := make([]InstanceInfo, 0, len(tc.names))
+			for _, n := range tc.names {
+				instances = append(instances, InstanceInfo{Project: "proj1", Zone: "asia-east1-a", Name: n})
+			}
+
+			var running, maxRunning, calls int32
+			errs := RunInstanceActions(context.TODO(), instances, tc.concurrency, func(_ context.Context, i InstanceInfo) error {
+				atomic.AddInt32(&calls, 1)
+				n := atomic.AddInt32(&running, 1)
+				defer atomic.AddInt32(&running, -1)
+				for {
+					m := atomic.LoadInt32(&maxRunning)
+					if n <= m || atomic.CompareAndSwapInt32(&maxRunning, m, n) {
+						break
+					}
+				}
+				if tc.failed[i.Name] {
+					return fmt.Errorf("operation failed")
+				}
+				return nil
+			})
+
+			assert.Equal(t, int32(len(instances)), calls)
+			assert.LessOrEqual(t, int(maxRunning), tc.concurrency)
+			assert.Len(t, errs, len(instances))
+			for i, err := range errs {
+				if tc.failed[instances[i].Name] {
+					assert.Error(t, err)
+				} else {
+					assert.NoError(t, err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,190 @@
+/*
+Copyright © 2022 tchiunam
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package compute
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"testing"
+
+	"cloud.google.com/go/compute/apiv1/computepb"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+)
+
+// TestInstanceInfo tests the instanceInfo function
+func TestInstanceInfo(t *testing.T) {
+	instance := &computepb.Instance{
+		Name:        proto.String("web-1"),
+		Id:          proto.Uint64(7452065390813417482),
+		Status:      proto.String("RUNNING"),
+		Zone:        proto.String("https://www.googleapis.com/compute/v1/projects/proj1/zones/asia-east1-a"),
+		MachineType: proto.String("https://www.googleapis.com/compute/v1/projects/proj1/zones/asia-east1-a/machineTypes/e2-medium"),
+		Labels:      map[string]string{"env": "prod"},
+		Tags:        &computepb.Tags{Items: []string{"web", "ssh"}},
+		ServiceAccounts: []*computepb.ServiceAccount{
+			{Email: proto.String("web@proj1.iam.gserviceaccount.com")},
+		},
+		Disks: []*computepb.AttachedDisk{
+			{Source: proto.String("https://www.googleapis.com/compute/v1/projects/proj1/zones/asia-east1-a/disks/web-1")},
+			{Source: proto.String("https://www.googleapis.com/compute/v1/projects/proj1/zones/asia-east1-a/disks/web-1-data")},
+		},
+		NetworkInterfaces: []*computepb.NetworkInterface{
+			{
+				Network:       proto.String("https://www.googleapis.com/compute/v1/projects/proj1/global/networks/default"),
+				NetworkIP:     proto.String("10.0.0.2"),
+				AccessConfigs: []*computepb.AccessConfig{{NatIP: proto.String("34.80.0.1")}},
+			},
+		},
+	}
+
+	info := instanceInfo("proj1", instance)
+	assert.Equal(t, InstanceInfo{
+		Project:         "proj1",
+		Zone:            "asia-east1-a",
+		Name:            "web-1",
+		ID:              7452065390813417482,
+		Status:          "RUNNING",
+		MachineType:     "e2-medium",
+		InternalIPs:     []string{"10.0.0.2"},
+		ExternalIPs:     []string{"34.80.0.1"},
+		Labels:          map[string]string{"env": "prod"},
+		Networks:        []string{"projects/proj1/global/networks/default"},
+		Tags:            []string{"web", "ssh"},
+		ServiceAccounts: []string{"web@proj1.iam.gserviceaccount.com"},
+		Disks: []string{
+			"projects/proj1/zones/asia-east1-a/disks/web-1",
+			"projects/proj1/zones/asia-east1-a/disks/web-1-data",
+		},
+	}, info)
+}
+
+// TestListProjectsInstances tests the ListProjectsInstances function
+func TestListProjectsInstances(t *testing.T) {
+	cases := map[string]struct {
+		projects    []string
+		concurrency int
+		failed      map[string]bool
+	}{
+		"all projects succeed": {
+			projects:    []string{"proj1", "proj2", "proj3"},
+			concurrency: 2,
+		},
+		"a project fails": {
+			projects:    []string{"proj1", "proj2", "proj3"},
+			concurrency: 1,
+			failed:      map[string]bool{"proj2": true},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var running, maxRunning int32
+			results := ListProjectsInstances(context.TODO(), tc.projects, tc.concurrency, func(_ context.Context, project string) ([]InstanceInfo, error) {
+				n := atomic.AddInt32(&running, 1)
+				defer atomic.AddInt32(&running, -1)
+				for {
+					m := atomic.LoadInt32(&maxRunning)
+					if n <= m || atomic.CompareAndSwapInt32(&maxRunning, m, n) {
+						break
+					}
+				}
+				if tc.failed[project] {
+					return nil, fmt.Errorf("permission denied")
+				}
+				return []InstanceInfo{{Project: project, Name: "web-1"}}, nil
+			})
+
+			assert.LessOrEqual(t, int(maxRunning), tc.concurrency)
+			assert.Len(t, results, len(tc.projects))
+			for i, r := range results {
+				assert.Equal(t, tc.projects[i], r.Project)
+				if tc.failed[r.Project] {
+					assert.Error(t, r.Err)
+					assert.Empty(t, r.Instances)
+				} else {
+					assert.NoError(t, r.Err)
+					assert.Equal(t, []InstanceInfo{{Project: r.Project, Name: "web-1"}}, r.Instances)
+				}
+			}
+		})
+	}
+}
+
+// TestInstanceRows tests the instanceRows function
+func TestInstanceRows(t *testing.T) {
+	cases := map[string]struct {
+		instance InstanceInfo
+		row      []string
+	}{
+		"full instance": {
+			instance: InstanceInfo{
+				Project:     "proj1",
+				Zone:        "asia-east1-a",
+				Name:        "web-1",
+				ID:          1,
+				Status:      "RUNNING",
+				MachineType: "e2-medium",
+				InternalIPs: []string{"10.0.0.2", "10.1.0.2"},
+				ExternalIPs: []string{"34.80.0.1"},
+				Labels:      map[string]string{"team": "web", "env": "prod"},
+			},
+			row: []string{"proj1", "asia-east1-a", "web-1", "1", "RUNNING", "e2-medium", "10.0.0.2,10.1.0.2", "34.80.0.1", "env=prod,team=web"},
+		},
+		"no address and label": {
+			instance: InstanceInfo{Project: "proj1", Zone: "asia-east1-b", Name: "batch-1", ID: 2, Status: "TERMINATED", MachineType: "n2-standard-4"},
+			row:      []string{"proj1", "asia-east1-b", "batch-1", "2", "TERMINATED", "n2-standard-4", "-", "-", "-"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			rows := instanceRows([]InstanceInfo{tc.instance})
+			assert.Equal(t, [][]string{tc.row}, rows)
+			assert.Len(t, tc.row, len(instanceHeader))
+		})
+	}
+}
+
+// TestSortInstances tests the sortInstances function
+func TestSortInstances(t *testing.T) {
+	instances := []InstanceInfo{
+		{Project: "proj2", Zone: "asia-east1-a", Name: "a"},
+		{Project: "proj1", Zone: "asia-east1-b", Name: "a"},
+		{Project: "proj1", Zone: "asia-east1-a", Name: "b"},
+		{Project: "proj1", Zone: "asia-east1-a", Name: "a"},
+	}
+	sortInstances(instances)
+
+	names := make([]string, 0, len(instances))
+	for _, i := range instances {
+		names = append(names, i.Project+"/"+i.Zone+"/"+i.Name)
+	}
+	assert.Equal(t, []string{
+		"proj1/asia-east1-a/a",
+		"proj1/asia-east1-a/b",
+		"proj1/asia-east1-b/a",
+		"proj2/asia-east1-a/a",
+	}, names)
+}
@@ -0,0 +1,275 @@
+/*
+Copyright © 2022 tchiunam
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package compute
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"google.golang.org/api/googleapi"
+	"gopkg.in/yaml.v3"
+	"k8s.io/klog/v2"
+)
+
+// The max. no. of labels of a resource
+const maxLabels = 64
+
+var (
+	labelKeyPattern   = regexp.MustCompile(`^[a-z][a-z0-9_-]{0,62}$`)
+	labelValuePattern = regexp.MustCompile(`^[a-z0-9_-]{0,63}$`)
+)
+
+// The actions of a label change
+const (
+	labelAdd    = "add"
+	labelModify = "modify"
+	labelRemove = "remove"
+)
+
+// LabelSelector selects instances in the same way as the filter flags
+// of listInstances
+type LabelSelector struct {
+	IDs          []string `yaml:"ids,omitempty"`
+	Names        []string `yaml:"names,omitempty"`
+	Labels       []string `yaml:"labels,omitempty"`
+	Statuses     []string `yaml:"statuses,omitempty"`
+	MachineTypes []string `yaml:"machineTypes,omitempty"`
+	NetworkIPs   []string `yaml:"networkIPs,omitempty"`
+	Filter       string   `yaml:"filter,omitempty"`
+}
+
+// LabelFile is the layout of a label file which selects instances and
+// declares the labels to set and to remove
+type LabelFile struct {
+	Selector LabelSelector     `yaml:"selector"`
+	Set      map[string]string `yaml:"set,omitempty"`
+	Remove   []string          `yaml:"remove,omitempty"`
+}
+
+// LabelChange is a label to add, modify or remove
+type LabelChange struct {
+	Action  string
+	Key     string
+	Current string
+	Desired string
+}
+
+// InstanceFilter converts the selector to an instance filter
+func (s LabelSelector) InstanceFilter() InstanceFilter {
+	return InstanceFilter{
+		IDs:          s.IDs,
+		Names:        s.Names,
+		Labels:       s.Labels,
+		Statuses:     s.Statuses,
+		MachineTypes: s.MachineTypes,
+		NetworkIPs:   s.NetworkIPs,
+		Raw:          s.Filter,
+	}
+}
+
+// ReadLabelFile reads and validates a label file. Unknown fields are
+// rejected so that a typo does not select all the instances.
+func ReadLabelFile(path string) (LabelFile, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return LabelFile{}, fmt.Errorf("failed to read label file: %w", err)
+	}
+	defer f.Close()
+
+	var file LabelFile
+	decoder := yaml.NewDecoder(f)
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&file); err != nil {
+		return LabelFile{}, fmt.Errorf("failed to parse label file %s: %w", path, err)
+	}
+	if err := file.validate(); err != nil {
+		return LabelFile{}, fmt.Errorf("invalid label file %s: %w", path, err)
+	}
+
+	return file, nil
+}
+
+// validate checks that the labels to set and remove are valid
+func (f LabelFile) validate() error {
+	if len(f.Set) == 0 && len(f.Remove) == 0 {
+		return fmt.Errorf("no label to set or remove")
+	}
+	for k, v := range f.Set {
+		if !labelKeyPattern.MatchString(k) {
+			return fmt.Errorf("invalid label key: %s", k)
+		}
+		if !labelValuePattern.MatchString(v) {
+			return fmt.Errorf("invalid value of label %s: %s", k, v)
+		}
+	}
+	for _, k := range f.Remove {
+		if !labelKeyPattern.MatchString(k) {
+			return fmt.Errorf("invalid label key: %s", k)
+		}
+		if _, ok := f.Set[k]; ok {
+			return fmt.Errorf("label %s is both set and removed", k)
+		}
+	}
+
+	return nil
+}
+
+// PlanLabels returns the changes to set and remove labels on the
+// current labels, sorted by key
+func PlanLabels(current map[string]string, set map[string]string, remove []string) []LabelChange {
+	changes := make([]LabelChange, 0)
+	for k, v := range set {
+		c, ok := current[k]
+		switch {
+		case !ok:
+			changes = append(changes, LabelChange{Action: labelAdd, Key: k, Desired: v})
+		case c != v:
+			changes = append(changes, LabelChange{Action: labelModify, Key: k, Current: c, Desired: v})
+		}
+	}
+	for _, k := range remove {
+		if c, ok := current[k]; ok {
+			changes = append(changes, LabelChange{Action: labelRemove, Key: k, Current: c})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Key < changes[j].Key })
+
+	return changes
+}
+
+// ApplyLabelChanges returns the labels with the changes applied. The
+// current labels are not modified.
+func ApplyLabelChanges(current map[string]string, changes []LabelChange) map[string]string {
+	labels := make(map[string]string, len(current)+len(changes))
+	for k, v := range current {
+		labels[k] = v
+	}
+	for _, c := range changes {
+		if c.Action == labelRemove {
+			delete(labels, c.Key)
+		} else {
+			labels[c.Key] = c.Desired
+		}
+	}
+
+	return labels
+}
+
+// LabelGetter gets the current labels and label fingerprint of a resource
+type LabelGetter func(ctx context.Context) (map[string]string, string, error)
+
+// LabelSetter sets the labels of a resource with the label fingerprint
+// which the labels are based on
+type LabelSetter func(ctx context.Context, labels map[string]string, fingerprint string) error
+
+// SetLabels sets and removes labels of a resource. The labels are read
+// with their fingerprint and the changes are applied on them. If the
+// labels are changed by others in between, the fingerprint does not
+// match and it is retried with the latest labels for at most retries
+// times. It returns the changes which are applied.
+func SetLabels(ctx context.Context, get LabelGetter, set LabelSetter, labels map[string]string, remove []string, retries int, backoff time.Duration) ([]LabelChange, error) {
+	for attempt := 0; ; attempt++ {
+		current, fingerprint, err := get(ctx)
+		if err != nil {
+			return nil, err
+		}
+		changes := PlanLabels(current, labels, remove)
+		if len(changes) == 0 {
+			return changes, nil
+		}
+		desired := ApplyLabelChanges(current, changes)
+		if len(desired) > maxLabels {
+			return nil, fmt.Errorf("%d labels exceed the limit of %d", len(desired), maxLabels)
+		}
+		err = set(ctx, desired, fingerprint)
+		if err == nil {
+			return changes, nil
+		}
+		if !isFingerprintConflict(err) || attempt >= retries {
+			return nil, err
+		}
+		klog.V(3).InfoS("Retry to set labels for a fingerprint conflict", "attempt", attempt+1, "error", err)
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(backoff << attempt):
+		}
+	}
+}
+
+// isFingerprintConflict tells whether an error is caused by a label
+// fingerprint which is out of date
+func isFingerprintConflict(err error) bool {
+	var apiErr *googleapi.Error
+	if errors.As(err, &apiErr) {
+		return apiErr.Code == http.StatusPreconditionFailed || apiErr.Code == http.StatusConflict
+	}
+
+	return false
+}
+
+// LabelPlan is the label changes of an instance or one of its disks
+type LabelPlan struct {
+	Instance InstanceInfo
+	// Kind is instance or disk
+	Kind     string
+	Project  string
+	Location string
+	Name     string
+	// Path is the path of a disk
+	Path    string
+	Changes []LabelChange
+	// Labels are the labels to set
+	Labels map[string]string
+}
+
+// labelPlanRows formats the columns of the label changes as a diff
+func labelPlanRows(plans []LabelPlan) [][]string {
+	var rows [][]string
+	for _, p := range plans {
+		for _, c := range p.Changes {
+			var diff string
+			switch c.Action {
+			case labelAdd:
+				diff = fmt.Sprintf("+ %s=%s", c.Key, c.Desired)
+			case labelModify:
+				diff = fmt.Sprintf("~ %s=%s -> %s", c.Key, c.Current, c.Desired)
+			default:
+				diff = fmt.Sprintf("- %s=%s", c.Key, c.Current)
+			}
+			rows = append(rows, []string{p.Kind, p.Project, p.Location, p.Name, strings.ToUpper(c.Action), diff})
+		}
+	}
+
+	return rows
+}
+
+// The header of the label plan rows
+var labelPlanHeader = []string{"KIND", "PROJECT", "LOCATION", "NAME", "ACTION", "DIFF"}
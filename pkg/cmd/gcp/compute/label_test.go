@@ -0,0 +1,237 @@
+/*
+Copyright © 2022 tchiunam
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package compute
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/api/googleapi"
+)
+
+// TestReadLabelFile tests the ReadLabelFile function
+func TestReadLabelFile(t *testing.T) {
+	cases := map[string]struct {
+		content string
+		want    LabelFile
+		valid   bool
+	}{
+		"valid file": {
+			content: "selector:\n  names: [web-1]\n  labels: [env=prod]\nset:\n  team: web\nremove: [owner]\n",
+			want: LabelFile{
+				Selector: LabelSelector{Names: []string{"web-1"}, Labels: []string{"env=prod"}},
+				Set:      map[string]string{"team": "web"},
+				Remove:   []string{"owner"},
+			},
+			valid: true,
+		},
+		"unknown field": {
+			content: "selecter:\n  names: [web-1]\nset:\n  team: web\n",
+		},
+		"no change": {
+			content: "selector:\n  names: [web-1]\n",
+		},
+		"invalid key": {
+			content: "selector:\n  names: [web-1]\nset:\n  Team: web\n",
+		},
+		"invalid value": {
+			content: "selector:\n  names: [web-1]\nset:\n  team: Web Team\n",
+		},
+		"set and removed": {
+			content: "selector:\n  names: [web-1]\nset:\n  team: web\nremove: [team]\n",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "labels.yaml")
+			assert.NoError(t, os.WriteFile(path, []byte(tc.content), 0600))
+			file, err := ReadLabelFile(path)
+			if !tc.valid {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.want, file)
+		})
+	}
+}
+
+// TestLabelSelectorInstanceFilter tests the InstanceFilter method of LabelSelector
+func TestLabelSelectorInstanceFilter(t *testing.T) {
+	s := LabelSelector{Names: []string{"web-1", "web-2"}, Statuses: []string{"RUNNING"}, Filter: "cpuPlatform = \"Intel Broadwell\""}
+	filter := s.InstanceFilter()
+	f, err := filter.String()
+	assert.NoError(t, err)
+	assert.Equal(t, `((name = "web-1") OR (name = "web-2")) AND (status = RUNNING) AND (cpuPlatform = "Intel Broadwell")`, f)
+}
+
+// TestPlanLabels tests the PlanLabels and ApplyLabelChanges functions
+func TestPlanLabels(t *testing.T) {
+	current := map[string]string{"env": "prod", "team": "db", "owner": "alice"}
+	cases := map[string]struct {
+		set     map[string]string
+		remove  []string
+		changes []LabelChange
+		labels  map[string]string
+	}{
+		"add, modify and remove": {
+			set:    map[string]string{"env": "prod", "team": "web", "tier": "frontend"},
+			remove: []string{"owner", "missing"},
+			changes: []LabelChange{
+				{Action: labelRemove, Key: "owner", Current: "alice"},
+				{Action: labelModify, Key: "team", Current: "db", Desired: "web"},
+				{Action: labelAdd, Key: "tier", Desired: "frontend"},
+			},
+			labels: map[string]string{"env": "prod", "team": "web", "tier": "frontend"},
+		},
+		"up to date": {
+			set:     map[string]string{"env": "prod"},
+			remove:  []string{"missing"},
+			changes: []LabelChange{},
+			labels:  current,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			changes := PlanLabels(current, tc.set, tc.remove)
+			assert.Equal(t, tc.changes, changes)
+			assert.Equal(t, tc.labels, ApplyLabelChanges(current, changes))
+			assert.Equal(t, "alice", current["owner"])
+		})
+	}
+}
+
+// TestSetLabels tests the SetLabels function with fingerprint conflicts
+func TestSetLabels(t *testing.T) {
+	conflict := &googleapi.Error{Code: http.StatusPreconditionFailed, Message: "Labels fingerprint either invalid or resource labels have changed"}
+	cases := map[string]struct {
+		setErrs []error
+		retries int
+		sets    int
+		labels  map[string]string
+		valid   bool
+	}{
+		"no conflict": {
+			retries: 3,
+			sets:    1,
+			labels:  map[string]string{"env": "prod", "team": "web"},
+			valid:   true,
+		},
+		"conflict then success": {
+			setErrs: []error{conflict, conflict},
+			retries: 3,
+			sets:    3,
+			labels:  map[string]string{"env": "prod", "team": "web", "other": "2"},
+			valid:   true,
+		},
+		"too many conflicts": {
+			setErrs: []error{conflict, conflict},
+			retries: 1,
+			sets:    2,
+		},
+		"other error": {
+			setErrs: []error{errors.New("permission denied")},
+			retries: 3,
+			sets:    1,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			gets, sets := 0, 0
+			var got map[string]string
+			// Another writer adds a label between each read
+			get := func(context.Context) (map[string]string, string, error) {
+				gets++
+				labels := map[string]string{"env": "prod", "owner": "alice"}
+				if gets > 1 {
+					labels["other"] = "2"
+				}
+				return labels, "fp" + string(rune('0'+gets)), nil
+			}
+			set := func(_ context.Context, labels map[string]string, fingerprint string) error {
+				sets++
+				assert.Equal(t, "fp"+string(rune('0'+gets)), fingerprint)
+				if sets <= len(tc.setErrs) {
+					return tc.setErrs[sets-1]
+				}
+				got = labels
+				return nil
+			}
+			changes, err := SetLabels(context.TODO(), get, set, map[string]string{"team": "web"}, []string{"owner"}, tc.retries, 0)
+			assert.Equal(t, tc.sets, sets)
+			if !tc.valid {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Len(t, changes, 2)
+			assert.Equal(t, tc.labels, got)
+		})
+	}
+}
+
+// TestSetLabelsUpToDate tests that SetLabels does not set labels which are up to date
+func TestSetLabelsUpToDate(t *testing.T) {
+	get := func(context.Context) (map[string]string, string, error) {
+		return map[string]string{"team": "web"}, "fp", nil
+	}
+	set := func(context.Context, map[string]string, string) error {
+		t.Error("labels are set")
+		return nil
+	}
+	changes, err := SetLabels(context.TODO(), get, set, map[string]string{"team": "web"}, []string{"owner"}, 3, 0)
+	assert.NoError(t, err)
+	assert.Empty(t, changes)
+}
+
+// TestLabelPlanRows tests the labelPlanRows function
+func TestLabelPlanRows(t *testing.T) {
+	plans := []LabelPlan{{
+		Kind:     labelKindInstance,
+		Project:  "proj1",
+		Location: "asia-east1-a",
+		Name:     "web-1",
+		Changes: []LabelChange{
+			{Action: labelRemove, Key: "owner", Current: "alice"},
+			{Action: labelModify, Key: "team", Current: "db", Desired: "web"},
+			{Action: labelAdd, Key: "tier", Desired: "frontend"},
+		},
+	}}
+	rows := labelPlanRows(plans)
+	assert.Equal(t, [][]string{
+		{"instance", "proj1", "asia-east1-a", "web-1", "REMOVE", "- owner=alice"},
+		{"instance", "proj1", "asia-east1-a", "web-1", "MODIFY", "~ team=db -> web"},
+		{"instance", "proj1", "asia-east1-a", "web-1", "ADD", "+ tier=frontend"},
+	}, rows)
+	for _, r := range rows {
+		assert.Len(t, r, len(labelPlanHeader))
+	}
+}
@@ -0,0 +1,160 @@
+/*
+Copyright © 2022 tchiunam
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package compute
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	compute "cloud.google.com/go/compute/apiv1"
+	"github.com/spf13/cobra"
+	"github.com/tchiunam/axolgo-cli/pkg/util"
+	"k8s.io/klog/v2"
+)
+
+var (
+	listDisksLong = `List persistent disks with their size, type, status and the
+instances which they are attached to. The zonal disks in the zone and
+the regional disks in the region are listed. The region defaults to
+the region of --zone if it is given. With --all-zones, the
+zonal and regional disks in all zones and regions are listed. With
+--unattached, only the disks which are not attached to any instance
+are listed, e.g. to find the disks which are left behind.
+`
+	listDisksExample = `  # List the zonal disks in a zone and the regional disks in its region
+  axolgo gcp compute listDisks --project proj1 --zone asia-east1-a
+
+  # List the zonal disks in a zone and the regional disks in a region
+  axolgo gcp compute listDisks --project proj1 --zone asia-east1-a --region asia-east2
+
+  # List the unattached disks in all zones
+  axolgo gcp compute listDisks --project proj1 --all-zones --unattached
+`
+)
+
+// ListDisksOptions defines flags and other configuration parameters for the `listDisks` command
+type ListDisksOptions struct {
+	Project    string
+	Zone       string
+	Region     string
+	AllZones   bool
+	Unattached bool
+	Filter     string
+	Output     string
+}
+
+// NewCmdListDisks creates the `listDisks` command
+func NewCmdListDisks(ctx *context.Context) *cobra.Command {
+	o := ListDisksOptions{}
+
+	cmd := &cobra.Command{
+		Use:                   "listDisks [-p] [-z] [-r] [--all-zones] [--unattached] [-f] [-o]",
+		DisableFlagsInUseLine: true,
+		Short:                 "List persistent disks.",
+		Long:                  listDisksLong,
+		Example:               listDisksExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := o.complete(ctx, cmd, args); err != nil {
+				panic(err)
+			}
+		},
+	}
+
+	cmd.Flags().StringVarP(&o.Project, "project", "p", "", "Project ID. Default is the project in axolgo configuration.")
+	cmd.Flags().StringVarP(&o.Zone, "zone", "z", "", "Zone.")
+	cmd.Flags().StringVarP(&o.Region, "region", "r", "", "Region of the regional disks. Default is the region in axolgo configuration.")
+	cmd.Flags().BoolVar(&o.AllZones, "all-zones", false, "List the disks in all zones and regions.")
+	cmd.Flags().BoolVar(&o.Unattached, "unattached", false, "List the disks which are not attached to any instance only.")
+	cmd.Flags().StringVarP(&o.Filter, "filter", "f", "", "Filter expression which is passed to the API as it is.")
+	cmd.Flags().StringVarP(&o.Output, "output", "o", util.OutputTable, fmt.Sprintf("Output format. One of %v.", util.OutputFormats))
+
+	return cmd
+}
+
+// Complete takes the command arguments and execute.
+func (o *ListDisksOptions) complete(ctx *context.Context, _ *cobra.Command, args []string) error {
+	if o.AllZones && (o.Zone != "" || o.Region != "") {
+		return fmt.Errorf("--zone and --region cannot be used with --all-zones")
+	}
+
+	project, err := util.GCPProject(o.Project)
+	if err != nil {
+		return err
+	}
+	zone, region := "", ""
+	if !o.AllZones {
+		zone = util.GCPZone(o.Zone)
+		region = o.Region
+		if region == "" && o.Zone != "" {
+			region = util.GCPZoneRegion(o.Zone)
+		}
+		region = util.GCPRegion(region)
+	}
+
+	opts, err := util.GCPClientOptions(context.TODO())
+	if err != nil {
+		return err
+	}
+	c, err := compute.NewDisksRESTClient(context.TODO(), opts...)
+	if err != nil {
+		return fmt.Errorf("failed to create disk client: %w", err)
+	}
+	defer c.Close()
+
+	disks, err := ListDisks(context.TODO(), c, project, zone, o.Filter, o.AllZones)
+	if err != nil {
+		return fmt.Errorf("failed to list disks: %w", err)
+	}
+	if region != "" {
+		rc, err := compute.NewRegionDisksRESTClient(context.TODO(), opts...)
+		if err != nil {
+			return fmt.Errorf("failed to create regional disk client: %w", err)
+		}
+		defer rc.Close()
+		regionDisks, err := ListRegionDisks(context.TODO(), rc, project, region, o.Filter)
+		if err != nil {
+			return fmt.Errorf("failed to list regional disks: %w", err)
+		}
+		disks = append(disks, regionDisks...)
+		sortDisks(disks)
+	}
+	if o.Unattached {
+		disks = unattachedDisks(disks)
+	}
+	klog.V(3).InfoS("List disks", "project", project, "zone", zone, "region", region, "disks", len(disks))
+
+	return util.PrintRecords(os.Stdout, o.Output, diskHeader, diskRows(disks))
+}
+
+// unattachedDisks returns the disks which are not attached to any instance
+func unattachedDisks(disks []DiskInfo) []DiskInfo {
+	unattached := make([]DiskInfo, 0, len(disks))
+	for _, d := range disks {
+		if len(d.Users) == 0 {
+			unattached = append(unattached, d)
+		}
+	}
+
+	return unattached
+}
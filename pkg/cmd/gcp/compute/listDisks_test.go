@@ -0,0 +1,93 @@
+/*
+Copyright © 2022 tchiunam
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package compute
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestNewCmdListDisks tests the NewCmdListDisks function
+// to make sure it returns a valid command.
+func TestNewCmdListDisks(t *testing.T) {
+	cases := map[string]struct {
+		use      string
+		short    string
+		hasFlags bool
+	}{
+		"valid command": {
+			use:      "listDisks [-p] [-z] [-r] [--all-zones] [--unattached] [-f] [-o]",
+			short:    "List persistent disks.",
+			hasFlags: true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cmd := NewCmdListDisks(nil)
+			assert.Equal(t, tc.use, cmd.Use)
+			assert.Equal(t, tc.short, cmd.Short)
+			assert.Equal(t, tc.hasFlags, cmd.Flags().HasFlags())
+		})
+	}
+}
+
+// TestNewCmdListDisksInvalid calls the NewCmdListDisks function
+// with invalid input and makes sure it returns an error.
+func TestNewCmdListDisksInvalid(t *testing.T) {
+	cases := map[string]struct {
+		args []string
+	}{
+		"zone with all zones": {
+			args: []string{"--project", "proj1", "--zone", "asia-east1-a", "--all-zones"},
+		},
+		"region with all zones": {
+			args: []string{"--project", "proj1", "--region", "asia-east1", "--all-zones"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			oldArgs := os.Args
+			defer func() { os.Args = oldArgs }()
+			os.Args = append([]string{"cmd"}, tc.args...)
+
+			cmd := NewCmdListDisks(nil)
+			assert.Panics(t, func() { cmd.Execute() })
+		})
+	}
+}
+
+// TestUnattachedDisks tests the unattachedDisks function
+func TestUnattachedDisks(t *testing.T) {
+	disks := []DiskInfo{
+		{Name: "web-1", Users: []string{"web-1"}},
+		{Name: "orphan-1"},
+		{Name: "shared-1", Users: []string{"web-1", "web-2"}},
+	}
+
+	unattached := unattachedDisks(disks)
+	assert.Equal(t, []DiskInfo{{Name: "orphan-1"}}, unattached)
+}
@@ -0,0 +1,122 @@
+/*
+Copyright © 2022 tchiunam
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package compute
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	compute "cloud.google.com/go/compute/apiv1"
+	"github.com/spf13/cobra"
+	"github.com/tchiunam/axolgo-cli/pkg/util"
+	"k8s.io/klog/v2"
+)
+
+var (
+	listFirewallsLong = `List VPC firewall rules. A filter expression can be given with
+--filter and is passed to the API as it is. More than one project can
+be given with --projects. The project in axolgo configuration, or else
+the project of the credentials file, is used if none is given. A
+project which fails is reported without aborting the others.
+
+The targets of a rule are its target tags and target service accounts.
+A rule without targets applies to all instances of its network.
+`
+	listFirewallsExample = `  # List the firewall rules of a project
+  axolgo gcp compute listFirewalls --project proj1
+
+  # List the ingress firewall rules of two projects
+  axolgo gcp compute listFirewalls --projects proj1,proj2 --filter 'direction = "INGRESS"'
+`
+)
+
+// ListFirewallsOptions defines flags and other configuration parameters for the `listFirewalls` command
+type ListFirewallsOptions struct {
+	Project    string
+	Projects   []string
+	Filter     string
+	MaxResults int32
+	Output     string
+}
+
+// NewCmdListFirewalls creates the `listFirewalls` command
+func NewCmdListFirewalls(ctx *context.Context) *cobra.Command {
+	o := ListFirewallsOptions{}
+
+	cmd := &cobra.Command{
+		Use:                   "listFirewalls [-p] [--projects] [-f] [-r] [-o]",
+		DisableFlagsInUseLine: true,
+		Short:                 "List VPC firewall rules.",
+		Long:                  listFirewallsLong,
+		Example:               listFirewallsExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := o.complete(ctx, cmd, args); err != nil {
+				panic(err)
+			}
+		},
+	}
+
+	cmd.Flags().StringVarP(&o.Project, "project", "p", "", "Project ID. Default is the project in axolgo configuration.")
+	cmd.Flags().StringSliceVar(&o.Projects, "projects", nil, "Comma separated project IDs.")
+	cmd.Flags().StringVarP(&o.Filter, "filter", "f", "", "Filter expression which is passed to the API as it is.")
+	cmd.Flags().Int32VarP(&o.MaxResults, "max-results", "r", 0, "Max. no. of records per batch.")
+	cmd.Flags().StringVarP(&o.Output, "output", "o", util.OutputTable, fmt.Sprintf("Output format. One of %v.", util.OutputFormats))
+
+	return cmd
+}
+
+// Complete takes the command arguments and execute.
+func (o *ListFirewallsOptions) complete(ctx *context.Context, _ *cobra.Command, args []string) error {
+	projects, err := defaultProjects(uniqueProjects(append([]string{o.Project}, o.Projects...)))
+	if err != nil {
+		return err
+	}
+
+	opts, err := util.GCPClientOptions(context.TODO())
+	if err != nil {
+		return err
+	}
+	c, err := compute.NewFirewallsRESTClient(context.TODO(), opts...)
+	if err != nil {
+		return fmt.Errorf("failed to create firewall client: %w", err)
+	}
+	defer c.Close()
+
+	var firewalls []FirewallInfo
+	failed := 0
+	for _, project := range projects {
+		f, err := ListFirewalls(context.TODO(), c, project, o.Filter, o.MaxResults)
+		if err != nil {
+			klog.Errorf("Failed to list firewall rules in project %s: %v", project, err)
+			failed++
+			continue
+		}
+		firewalls = append(firewalls, f...)
+	}
+	if failed == len(projects) {
+		return fmt.Errorf("failed to list firewall rules in all projects")
+	}
+
+	return util.PrintRecords(os.Stdout, o.Output, firewallHeader, firewallRows(firewalls))
+}
@@ -25,36 +25,49 @@ package compute
 import (
 	"context"
 	"fmt"
+	"os"
 	"strings"
 
 	"k8s.io/klog/v2"
 
 	"github.com/spf13/cobra"
-	"github.com/spf13/viper"
-	"github.com/tchiunam/axolgo-cli/pkg/types"
-	"github.com/tchiunam/axolgo-cloud/gcp/compute"
-	"github.com/tchiunam/axolgo-cloud/gcp/util"
-	axolgolibutil "github.com/tchiunam/axolgo-lib/util"
-	"google.golang.org/api/iterator"
-	computepb "google.golang.org/genproto/googleapis/cloud/compute/v1"
+	"github.com/tchiunam/axolgo-cli/pkg/util"
 )
 
 var (
 	listInstancesLong = `List compute engine instances which are filtered by
-given criteria.
+given criteria. Different criteria are joined with AND and the values
+of a criterion are joined with OR. A raw filter expression can be
+given with --filter and is joined with AND.
+
+With --all-zones, the instances in all zones are listed with the
+aggregated list. More than one project can be given with --projects
+and they are listed concurrently. The project in axolgo configuration,
+or else the project of the credentials file, is used if none is given.
+A project which fails, e.g. for a lack of permission, is reported
+without aborting the others.
 `
-	listInstancesExample = `  # List comput engine instance
+	listInstancesExample = `  # List a compute engine instance by ID
   axolgo gcp compute listInstances --project proj1 --zone asia-east1-a --id 7452065390813417482
+
+  # List the running production instances named web-1 or web-2
+  axolgo gcp compute listInstances --project proj1 --name web-1 --name web-2 --label env=prod --status RUNNING
+
+  # List the instances in all zones of two projects
+  axolgo gcp compute listInstances --projects proj1,proj2 --all-zones
 `
 )
 
 // ListInstancesOptions defines flags and other configuration parameters for the `listInstances` command
 type ListInstancesOptions struct {
-	Project    string
-	Zone       string
-	IDs        []string
-	Names      []string
-	MaxResults int32
+	Project     string
+	Projects    []string
+	Zone        string
+	AllZones    bool
+	Filter      InstanceFilter
+	MaxResults  int32
+	Concurrency int
+	Output      string
 }
 
 // NewCmdListInstances creates the `listInstances` command
@@ -62,7 +75,7 @@ func NewCmdListInstances(ctx *context.Context) *cobra.Command {
 	o := ListInstancesOptions{}
 
 	cmd := &cobra.Command{
-		Use:                   "listInstances -p PROJECT [-z] [-i] [-n] [-r]",
+		Use:                   "listInstances [-p] [--projects] [-z] [--all-zones] [-i] [-n] [-l] [-s] [-m] [--network-ip] [-f] [-r] [--concurrency] [-o]",
 		DisableFlagsInUseLine: true,
 		Short:                 "List compute engine instances.",
 		Long:                  listInstancesLong,
@@ -74,13 +87,14 @@ func NewCmdListInstances(ctx *context.Context) *cobra.Command {
 		},
 	}
 
-	cmd.Flags().StringVarP(&o.Project, "project", "p", "", "Project ID.")
+	cmd.Flags().StringVarP(&o.Project, "project", "p", "", "Project ID. Default is the project in axolgo configuration.")
+	cmd.Flags().StringSliceVar(&o.Projects, "projects", nil, "Comma separated project IDs.")
 	cmd.Flags().StringVarP(&o.Zone, "zone", "z", "", "Zone.")
-	cmd.Flags().StringArrayVarP(&o.IDs, "id", "i", nil, "Instance IDs.")
-	cmd.Flags().StringArrayVarP(&o.Names, "name", "n", nil, "Instance Names.")
+	cmd.Flags().BoolVar(&o.AllZones, "all-zones", false, "List the instances in all zones.")
+	addFilterFlags(cmd, &o.Filter)
 	cmd.Flags().Int32VarP(&o.MaxResults, "max-results", "r", 0, "Max. no. of records per batch.")
-
-	cmd.MarkFlagRequired("project")
+	cmd.Flags().IntVar(&o.Concurrency, "concurrency", 4, "Max. no. of projects to list at the same time.")
+	cmd.Flags().StringVarP(&o.Output, "output", "o", util.OutputTable, fmt.Sprintf("Output format. One of %v.", util.OutputFormats))
 
 	return cmd
 
@@ -88,65 +102,85 @@ func NewCmdListInstances(ctx *context.Context) *cobra.Command {
 
 // Complete takes the command arguments and execute.
 func (o *ListInstancesOptions) complete(ctx *context.Context, _ *cobra.Command, args []string) error {
-	filterNVs := map[string][]string{
-		"id":   o.IDs,
-		"name": o.Names,
+	if o.AllZones && o.Zone != "" {
+		return fmt.Errorf("--zone cannot be used with --all-zones")
 	}
-
-	var filters []string
-	for filterName, filterValues := range filterNVs {
-		klog.V(6).InfoS("process filter", "filterName", filterName, "filterValues", filterValues, "len(filterValues)", len(filterValues))
-		if len(filterValues) != 0 {
-			filters = append(
-				filters,
-				strings.Join(axolgolibutil.Map4s(filterValues, func(s string) string {
-					return fmt.Sprintf("(%v = %v)", filterName, s)
-				}), " or "))
-		}
+	if o.Concurrency < 1 {
+		return fmt.Errorf("invalid concurrency: %d", o.Concurrency)
 	}
-	f := strings.Join(filters, " or ")
 
-	axolgoConfig := viper.Get("axolgo-config").(types.AxolgoConfig)
-	klog.V(3).InfoS("axolgoConfig", "GCP.GoogleApplicationCredentials", axolgoConfig.GCP.GoogleApplicationCredentials)
+	f, err := o.Filter.String()
+	if err != nil {
+		return err
+	}
+	klog.V(3).InfoS("Filter instances", "filter", f)
 
-	// Use zone configured in the config file if not specified
-	zone := o.Zone
-	if zone == "" {
-		zone = axolgoConfig.GCP.Zone
+	// Use project and zone configured in the config file if not specified
+	projects, err := defaultProjects(o.projects())
+	if err != nil {
+		return err
 	}
-	req := &computepb.ListInstancesRequest{
-		Project: o.Project,
-		Zone:    zone,
-		Filter:  &f,
+	zone := ""
+	if !o.AllZones {
+		zone = util.GCPZone(o.Zone)
 	}
 
-	c, it, err := compute.RunListInstances(req, util.WithCredentialsFile(axolgolibutil.ExpandPath(axolgoConfig.GCP.GoogleApplicationCredentials)))
+	c, err := newInstancesClient(context.TODO())
 	if err != nil {
-		klog.Fatalf("Failed to list compute engine instances: %v", err)
+		return err
 	}
 	defer c.Close()
 
-	for {
-		instance, err := it.Next()
-		if err == iterator.Done {
-			break
-		}
-		if err != nil {
-			klog.Fatalf("Failed to extract instance: %v", err)
+	results := ListProjectsInstances(context.TODO(), projects, o.Concurrency, func(ctx context.Context, project string) ([]InstanceInfo, error) {
+		return ListInstances(ctx, c, project, zone, f, o.MaxResults, o.AllZones)
+	})
+
+	var instances []InstanceInfo
+	failed := 0
+	for _, r := range results {
+		if r.Err != nil {
+			klog.Errorf("Failed to list compute engine instances in project %s: %v", r.Project, r.Err)
+			failed++
+			continue
 		}
-		klog.Infof("Name: %v", instance.GetName())
-		klog.Infof("    ID: %v", instance.GetId())
-		klog.Infof("    Zone: %v", instance.GetZone())
-		for _, nic := range instance.GetNetworkInterfaces() {
-			klog.Infof("    NetworkInterface: %v", nic.GetName())
-			for _, ip := range nic.GetAccessConfigs() {
-				klog.Infof("        IP: %v", ip.GetNatIP())
-			}
-		}
-		for _, label := range instance.GetLabels() {
-			klog.Infof("    Label: %v", label)
+		instances = append(instances, r.Instances...)
+	}
+	if failed == len(results) {
+		return fmt.Errorf("failed to list compute engine instances in all projects")
+	}
+
+	return util.PrintRecords(os.Stdout, o.Output, instanceHeader, instanceRows(instances))
+}
+
+// projects returns the projects of --project and --projects without duplicates
+func (o *ListInstancesOptions) projects() []string {
+	return uniqueProjects(append([]string{o.Project}, o.Projects...))
+}
+
+// uniqueProjects returns the non-empty projects without duplicates
+func uniqueProjects(projects []string) []string {
+	var unique []string
+	seen := map[string]bool{}
+	for _, p := range projects {
+		if p = strings.TrimSpace(p); p != "" && !seen[p] {
+			seen[p] = true
+			unique = append(unique, p)
 		}
 	}
 
-	return err
+	return unique
+}
+
+// defaultProjects returns the projects, or the project in axolgo
+// configuration if none is given
+func defaultProjects(projects []string) ([]string, error) {
+	if len(projects) > 0 {
+		return projects, nil
+	}
+	project, err := util.GCPProject("")
+	if err != nil {
+		return nil, err
+	}
+
+	return []string{project}, nil
 }
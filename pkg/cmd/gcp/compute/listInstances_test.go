@@ -23,6 +23,7 @@ THE SOFTWARE.
 package compute
 
 import (
+	"os"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -37,7 +38,7 @@ func TestNewCmdListInstances(t *testing.T) {
 		hasFlags bool
 	}{
 		"valid command": {
-			use:      "listInstances -p PROJECT [-z] [-i] [-n] [-r]",
+			use:      "listInstances [-p] [--projects] [-z] [--all-zones] [-i] [-n] [-l] [-s] [-m] [--network-ip] [-f] [-r] [--concurrency] [-o]",
 			short:    "List compute engine instances.",
 			hasFlags: true,
 		},
@@ -52,3 +53,63 @@ func TestNewCmdListInstances(t *testing.T) {
 		})
 	}
 }
+
+// TestNewCmdListInstancesInvalid calls the NewCmdListInstances function
+// with invalid input and makes sure it returns an error.
+func TestNewCmdListInstancesInvalid(t *testing.T) {
+	cases := map[string]struct {
+		args []string
+	}{
+		"zone with all zones": {
+			args: []string{"--project", "proj1", "--zone", "asia-east1-a", "--all-zones"},
+		},
+		"invalid status": {
+			args: []string{"--project", "proj1", "--status", "DELETED"},
+		},
+		"invalid concurrency": {
+			args: []string{"--projects", "proj1,proj2", "--concurrency", "0"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			oldArgs := os.Args
+			defer func() { os.Args = oldArgs }()
+			os.Args = append([]string{"cmd"}, tc.args...)
+
+			cmd := NewCmdListInstances(nil)
+			assert.Panics(t, func() { cmd.Execute() })
+		})
+	}
+}
+
+// TestListInstancesOptionsProjects tests the projects method of ListInstancesOptions
+func TestListInstancesOptionsProjects(t *testing.T) {
+	cases := map[string]struct {
+		project  string
+		projects []string
+		result   []string
+	}{
+		"project only": {
+			project: "proj1",
+			result:  []string{"proj1"},
+		},
+		"projects only": {
+			projects: []string{"proj1", "proj2"},
+			result:   []string{"proj1", "proj2"},
+		},
+		"duplicated projects": {
+			project:  "proj1",
+			projects: []string{"proj2", " proj1", ""},
+			result:   []string{"proj1", "proj2"},
+		},
+		"no project": {},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			o := ListInstancesOptions{Project: tc.project, Projects: tc.projects}
+			assert.Equal(t, tc.result, o.projects())
+		})
+	}
+}
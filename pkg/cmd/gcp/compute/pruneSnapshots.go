@@ -0,0 +1,193 @@
+/*
+Copyright © 2022 tchiunam
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package compute
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	compute "cloud.google.com/go/compute/apiv1"
+	"cloud.google.com/go/compute/apiv1/computepb"
+	"github.com/spf13/cobra"
+	"github.com/tchiunam/axolgo-cli/pkg/util"
+	"k8s.io/klog/v2"
+)
+
+var (
+	pruneSnapshotsLong = `Prune disk snapshots with a retention policy which is applied to the
+snapshots of each source disk. A snapshot is retained if it is one of
+the newest --keep snapshots of its disk or if it is younger than
+--max-age. Snapshots which are not ready are always retained.
+Snapshots which are created by a snapshot schedule are managed by the
+schedule and are never pruned.
+
+The snapshots are selected by source disk names, by the instances of
+the axolgo-instance label which createSnapshots sets, by a name
+prefix, or by a filter expression. At least one of them is required.
+
+The plan is printed without deleting anything unless --apply is given.
+`
+	pruneSnapshotsExample = `  # Show which snapshots of an instance would be removed to keep the newest 7 of each disk
+  axolgo gcp compute pruneSnapshots --project proj1 --instance web-1 --keep 7
+
+  # Remove the snapshots of a disk older than 30 days but keep at least 3
+  axolgo gcp compute pruneSnapshots --project proj1 --disk db-1-data --keep 3 --max-age 30d --apply
+`
+)
+
+// PruneSnapshotsOptions defines flags and other configuration parameters for the `pruneSnapshots` command
+type PruneSnapshotsOptions struct {
+	Project   string
+	Disks     []string
+	Instances []string
+	Prefix    string
+	Filter    string
+	Keep      int
+	MaxAge    string
+	Apply     bool
+}
+
+// NewCmdPruneSnapshots creates the `pruneSnapshots` command
+func NewCmdPruneSnapshots(ctx *context.Context) *cobra.Command {
+	o := PruneSnapshotsOptions{}
+
+	cmd := &cobra.Command{
+		Use:                   "pruneSnapshots [-p] [--disk] [--instance] [--prefix] [-f] [--keep] [--max-age] [--apply]",
+		DisableFlagsInUseLine: true,
+		Short:                 "Prune disk snapshots with a retention policy.",
+		Long:                  pruneSnapshotsLong,
+		Example:               pruneSnapshotsExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := o.complete(ctx, cmd, args); err != nil {
+				panic(err)
+			}
+		},
+	}
+
+	cmd.Flags().StringVarP(&o.Project, "project", "p", "", "Project ID. Default is the project in axolgo configuration.")
+	cmd.Flags().StringArrayVar(&o.Disks, "disk", nil, "Name of the source disk.")
+	cmd.Flags().StringArrayVar(&o.Instances, "instance", nil, "Name of the source instance in the axolgo-instance label.")
+	cmd.Flags().StringVar(&o.Prefix, "prefix", "", "Only prune the snapshots with this name prefix.")
+	cmd.Flags().StringVarP(&o.Filter, "filter", "f", "", "Filter expression which is passed to the API as it is.")
+	cmd.Flags().IntVar(&o.Keep, "keep", 0, "Number of the newest snapshots of each disk to retain.")
+	cmd.Flags().StringVar(&o.MaxAge, "max-age", "", "Retain the snapshots younger than this age, e.g. 30d or 12h.")
+	cmd.Flags().BoolVar(&o.Apply, "apply", false, "Delete the snapshots. Default is to print the plan only.")
+
+	return cmd
+}
+
+// Complete takes the command arguments and execute.
+func (o *PruneSnapshotsOptions) complete(ctx *context.Context, _ *cobra.Command, args []string) error {
+	maxAge, err := util.ParseAge(o.MaxAge)
+	if err != nil {
+		return err
+	}
+	if o.Keep < 0 {
+		return fmt.Errorf("invalid number of snapshots to keep: %d", o.Keep)
+	}
+	if o.Keep == 0 && maxAge == 0 {
+		return fmt.Errorf("a retention policy of --keep or --max-age is required")
+	}
+	if len(o.Disks) == 0 && len(o.Instances) == 0 && o.Prefix == "" && o.Filter == "" {
+		return fmt.Errorf("one of --disk, --instance, --prefix or --filter is required to select the snapshots")
+	}
+
+	project, err := util.GCPProject(o.Project)
+	if err != nil {
+		return err
+	}
+
+	opts, err := util.GCPClientOptions(context.TODO())
+	if err != nil {
+		return err
+	}
+	c, err := compute.NewSnapshotsRESTClient(context.TODO(), opts...)
+	if err != nil {
+		return fmt.Errorf("failed to create snapshot client: %w", err)
+	}
+	defer c.Close()
+
+	snapshots, err := ListSnapshots(context.TODO(), c, project, o.Filter)
+	if err != nil {
+		return fmt.Errorf("failed to list snapshots: %w", err)
+	}
+	candidates := o.selectSnapshots(snapshots)
+
+	now := time.Now()
+	retain, remove := PlanSnapshotPrune(candidates, o.Keep, maxAge, now)
+	if err := printSnapshotPrunePlan(retain, remove, now); err != nil {
+		return err
+	}
+
+	if !o.Apply {
+		klog.Infof("Dry run: %d snapshot(s) would be removed. Use --apply to delete them.", len(remove))
+		return nil
+	}
+
+	for _, s := range remove {
+		if _, err := c.Delete(context.TODO(), &computepb.DeleteSnapshotRequest{Project: project, Snapshot: s.Name}); err != nil {
+			return fmt.Errorf("failed to delete snapshot %s: %w", s.Name, err)
+		}
+		klog.Infof("Requested to delete snapshot %s", s.Name)
+	}
+
+	return nil
+}
+
+// selectSnapshots returns the snapshots which match the disks, the
+// instances and the prefix. Snapshots of a snapshot schedule are
+// excluded.
+func (o *PruneSnapshotsOptions) selectSnapshots(snapshots []SnapshotInfo) []SnapshotInfo {
+	selected := make([]SnapshotInfo, 0, len(snapshots))
+	for _, s := range snapshots {
+		if s.AutoCreated || !strings.HasPrefix(s.Name, o.Prefix) {
+			continue
+		}
+		if len(o.Disks) > 0 && !contains(o.Disks, lastSegment(s.SourceDisk)) {
+			continue
+		}
+		if len(o.Instances) > 0 && !contains(o.Instances, s.Labels[snapshotInstanceLabel]) {
+			continue
+		}
+		selected = append(selected, s)
+	}
+
+	return selected
+}
+
+// printSnapshotPrunePlan prints the snapshots with the action to take
+func printSnapshotPrunePlan(retain []SnapshotInfo, remove []SnapshotInfo, now time.Time) error {
+	header := append([]string{"ACTION"}, snapshotHeader...)
+	rows := make([][]string, 0, len(retain)+len(remove))
+	for _, row := range snapshotRows(remove, now) {
+		rows = append(rows, append([]string{"remove"}, row...))
+	}
+	for _, row := range snapshotRows(retain, now) {
+		rows = append(rows, append([]string{"retain"}, row...))
+	}
+
+	return util.PrintTable(os.Stdout, header, rows)
+}
@@ -0,0 +1,131 @@
+/*
+Copyright © 2022 tchiunam
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package compute
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestNewCmdPruneSnapshots tests the NewCmdPruneSnapshots function
+// to make sure it returns a valid command.
+func TestNewCmdPruneSnapshots(t *testing.T) {
+	cases := map[string]struct {
+		use      string
+		short    string
+		hasFlags bool
+	}{
+		"valid command": {
+			use:      "pruneSnapshots [-p] [--disk] [--instance] [--prefix] [-f] [--keep] [--max-age] [--apply]",
+			short:    "Prune disk snapshots with a retention policy.",
+			hasFlags: true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cmd := NewCmdPruneSnapshots(nil)
+			assert.Equal(t, tc.use, cmd.Use)
+			assert.Equal(t, tc.short, cmd.Short)
+			assert.Equal(t, tc.hasFlags, cmd.Flags().HasFlags())
+		})
+	}
+}
+
+// TestNewCmdPruneSnapshotsInvalid calls the NewCmdPruneSnapshots function
+// with invalid input and makes sure it returns an error.
+func TestNewCmdPruneSnapshotsInvalid(t *testing.T) {
+	cases := map[string]struct {
+		args []string
+	}{
+		"no retention policy": {
+			args: []string{"--project", "proj1", "--disk", "web-1"},
+		},
+		"no selector": {
+			args: []string{"--project", "proj1", "--keep", "3"},
+		},
+		"invalid max age": {
+			args: []string{"--project", "proj1", "--disk", "web-1", "--max-age", "30days"},
+		},
+		"negative keep": {
+			args: []string{"--project", "proj1", "--disk", "web-1", "--keep", "-1"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			oldArgs := os.Args
+			defer func() { os.Args = oldArgs }()
+			os.Args = append([]string{"cmd"}, tc.args...)
+
+			cmd := NewCmdPruneSnapshots(nil)
+			assert.Panics(t, func() { cmd.Execute() })
+		})
+	}
+}
+
+// TestSelectSnapshots tests the selectSnapshots function
+func TestSelectSnapshots(t *testing.T) {
+	snapshots := []SnapshotInfo{
+		{Name: "web-1-20221230000000", SourceDisk: "projects/proj1/zones/asia-east1-a/disks/web-1", Labels: map[string]string{"axolgo-instance": "web-1"}},
+		{Name: "web-1-data-20221230000000", SourceDisk: "projects/proj1/zones/asia-east1-a/disks/web-1-data", Labels: map[string]string{"axolgo-instance": "web-1"}},
+		{Name: "db-1-20221230000000", SourceDisk: "projects/proj1/zones/asia-east1-a/disks/db-1", Labels: map[string]string{"axolgo-instance": "db-1"}},
+		{Name: "web-1-schedule", SourceDisk: "projects/proj1/zones/asia-east1-a/disks/web-1", AutoCreated: true},
+		{Name: "manual-1", SourceDisk: "projects/proj1/zones/asia-east1-a/disks/web-1"},
+	}
+
+	cases := map[string]struct {
+		options PruneSnapshotsOptions
+		names   []string
+	}{
+		"by disk": {
+			options: PruneSnapshotsOptions{Disks: []string{"web-1"}},
+			names:   []string{"web-1-20221230000000", "manual-1"},
+		},
+		"by instance": {
+			options: PruneSnapshotsOptions{Instances: []string{"web-1"}},
+			names:   []string{"web-1-20221230000000", "web-1-data-20221230000000"},
+		},
+		"by disk and prefix": {
+			options: PruneSnapshotsOptions{Disks: []string{"web-1", "db-1"}, Prefix: "web-"},
+			names:   []string{"web-1-20221230000000"},
+		},
+		"by filter only": {
+			options: PruneSnapshotsOptions{Filter: "name = web-*"},
+			names:   []string{"web-1-20221230000000", "web-1-data-20221230000000", "db-1-20221230000000", "manual-1"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			selected := tc.options.selectSnapshots(snapshots)
+			names := make([]string, 0, len(selected))
+			for _, s := range selected {
+				names = append(names, s.Name)
+			}
+			assert.Equal(t, tc.names, names)
+		})
+	}
+}
@@ -0,0 +1,47 @@
+/*
+Copyright © 2022 tchiunam
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package compute
+
+import (
+	"context"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	resumeInstancesLong = `Resume the suspended compute engine instances which are selected by
+the filters of listInstances.
+
+The matching instances are printed and a confirmation is asked before
+acting on them unless --yes is given. The operations are polled until
+they are done or the --wait timeout is reached.
+`
+	resumeInstancesExample = `  # Resume the suspended batch instances
+  axolgo gcp compute resumeInstances --project proj1 --zone asia-east1-a --label role=batch --status SUSPENDED
+`
+)
+
+// NewCmdResumeInstances creates the `resumeInstances` command
+func NewCmdResumeInstances(ctx *context.Context) *cobra.Command {
+	return newInstanceActionCmd(ctx, resumeAction, "resumeInstances", "Resume compute engine instances.", resumeInstancesLong, resumeInstancesExample)
+}
@@ -0,0 +1,108 @@
+/*
+Copyright © 2022 tchiunam
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package compute
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	compute "cloud.google.com/go/compute/apiv1"
+	"cloud.google.com/go/compute/apiv1/computepb"
+	"google.golang.org/protobuf/proto"
+	"k8s.io/klog/v2"
+)
+
+// The serial ports of an instance
+const (
+	minSerialPort = 1
+	maxSerialPort = 4
+)
+
+// SerialPortReader reads the output of a serial port from a byte offset
+type SerialPortReader func(ctx context.Context, start int64) (*computepb.SerialPortOutput, error)
+
+// NewSerialPortReader creates a reader of a serial port of an instance
+func NewSerialPortReader(c *compute.InstancesClient, project string, zone string, instance string, port int32) SerialPortReader {
+	return func(ctx context.Context, start int64) (*computepb.SerialPortOutput, error) {
+		return c.GetSerialPortOutput(ctx, &computepb.GetSerialPortOutputInstanceRequest{
+			Project:  project,
+			Zone:     zone,
+			Instance: instance,
+			Port:     proto.Int32(port),
+			Start:    proto.Int64(start),
+		})
+	}
+}
+
+// TailSerialPortOutput writes the output of a serial port from a byte
+// offset. The instance keeps 1 MiB of output only, so the output before
+// it is skipped, with a warning if it is after the start offset. In
+// follow mode, the output is read again from the next offset every
+// interval until ctx is done, like tail -f. An error after the first
+// read is logged and the read is retried in the next interval. It
+// returns the next offset.
+func TailSerialPortOutput(ctx context.Context, read SerialPortReader, start int64, follow bool, interval time.Duration, w io.Writer) (int64, error) {
+	next := start
+	started := false
+	for {
+		output, err := read(ctx, next)
+		switch {
+		case err != nil && follow && errors.Is(ctx.Err(), context.Canceled):
+			return next, nil
+		case err != nil && follow && started:
+			klog.Warningf("Failed to read serial port output, retry in %s: %v", interval, err)
+		case err != nil:
+			return next, err
+		default:
+			started = true
+			if next > 0 && output.GetStart() > next {
+				klog.Warningf("%d bytes of serial port output are skipped because they are no longer available", output.GetStart()-next)
+			}
+			if _, err := io.WriteString(w, output.GetContents()); err != nil {
+				return next, err
+			}
+			next = output.GetNext()
+		}
+		if !follow {
+			return next, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return next, nil
+		case <-time.After(interval):
+		}
+	}
+}
+
+// validateSerialPort checks that a port is a serial port of an instance
+func validateSerialPort(port int32) error {
+	if port < minSerialPort || port > maxSerialPort {
+		return fmt.Errorf("invalid serial port %d, must be %d to %d", port, minSerialPort, maxSerialPort)
+	}
+
+	return nil
+}
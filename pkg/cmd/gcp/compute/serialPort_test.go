@@ -0,0 +1,201 @@
+/*
+Copyright © 2022 tchiunam
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package compute
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/compute/apiv1/computepb"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+)
+
+// fakeSerialPort is a serial port whose output grows on each read
+type fakeSerialPort struct {
+	// chunks are the output which is appended on each read
+	chunks []string
+	// discarded is the no. of bytes which are no longer kept
+	discarded int64
+	reads     []int64
+	cancel    context.CancelFunc
+	err       error
+	// errs are the errors of the reads, keyed by the no. of the read
+	errs map[int]error
+}
+
+// read returns the output from start like the API
+func (p *fakeSerialPort) read(_ context.Context, start int64) (*computepb.SerialPortOutput, error) {
+	p.reads = append(p.reads, start)
+	if p.err != nil {
+		return nil, p.err
+	}
+	if err, ok := p.errs[len(p.reads)]; ok {
+		return nil, err
+	}
+	var output string
+	n := len(p.reads)
+	if n > len(p.chunks) {
+		n = len(p.chunks)
+	}
+	for _, c := range p.chunks[:n] {
+		output += c
+	}
+	if len(p.reads) >= len(p.chunks) && p.cancel != nil {
+		p.cancel()
+	}
+	if start < p.discarded {
+		start = p.discarded
+	}
+	if start > int64(len(output)) {
+		start = int64(len(output))
+	}
+
+	return &computepb.SerialPortOutput{
+		Contents: proto.String(output[start:]),
+		Start:    proto.Int64(start),
+		Next:     proto.Int64(int64(len(output))),
+	}, nil
+}
+
+// TestTailSerialPortOutput tests the TailSerialPortOutput function
+func TestTailSerialPortOutput(t *testing.T) {
+	cases := map[string]struct {
+		port   *fakeSerialPort
+		start  int64
+		follow bool
+		output string
+		reads  []int64
+		next   int64
+		valid  bool
+	}{
+		"read once": {
+			port:   &fakeSerialPort{chunks: []string{"booting\n", "login: "}},
+			output: "booting\n",
+			reads:  []int64{0},
+			next:   8,
+			valid:  true,
+		},
+		"read from offset": {
+			port:   &fakeSerialPort{chunks: []string{"booting\n"}},
+			start:  4,
+			output: "ing\n",
+			reads:  []int64{4},
+			next:   8,
+			valid:  true,
+		},
+		"follow": {
+			port:   &fakeSerialPort{chunks: []string{"booting\n", "", "login: "}},
+			follow: true,
+			output: "booting\nlogin: ",
+			reads:  []int64{0, 8, 8},
+			next:   15,
+			valid:  true,
+		},
+		"skip discarded output": {
+			port:   &fakeSerialPort{chunks: []string{"booting\n"}, discarded: 4},
+			start:  2,
+			output: "ing\n",
+			reads:  []int64{2},
+			next:   8,
+			valid:  true,
+		},
+		"follow with transient error": {
+			port: &fakeSerialPort{
+				chunks: []string{"booting\n", "", "login: "},
+				errs:   map[int]error{2: errors.New("service unavailable")},
+			},
+			follow: true,
+			output: "booting\nlogin: ",
+			reads:  []int64{0, 8, 8},
+			next:   15,
+			valid:  true,
+		},
+		"error": {
+			port:  &fakeSerialPort{err: errors.New("instance not found")},
+			reads: []int64{0},
+		},
+		"follow with error on first read": {
+			port:   &fakeSerialPort{err: errors.New("instance not found")},
+			follow: true,
+			reads:  []int64{0},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.TODO())
+			defer cancel()
+			if tc.follow {
+				tc.port.cancel = cancel
+			}
+			var buf bytes.Buffer
+			next, err := TailSerialPortOutput(ctx, tc.port.read, tc.start, tc.follow, time.Millisecond, &buf)
+			assert.Equal(t, tc.reads, tc.port.reads)
+			if !tc.valid {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.output, buf.String())
+			assert.Equal(t, tc.next, next)
+		})
+	}
+}
+
+// TestValidateSerialPort tests the validateSerialPort function
+func TestValidateSerialPort(t *testing.T) {
+	cases := map[string]struct {
+		port  int32
+		valid bool
+	}{
+		"console": {
+			port:  1,
+			valid: true,
+		},
+		"port 4": {
+			port:  4,
+			valid: true,
+		},
+		"port 0": {
+			port: 0,
+		},
+		"port 5": {
+			port: 5,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := validateSerialPort(tc.port)
+			if tc.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
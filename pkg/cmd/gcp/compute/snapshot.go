@@ -0,0 +1,213 @@
+/*
+Copyright © 2022 tchiunam
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package compute
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	compute "cloud.google.com/go/compute/apiv1"
+	"cloud.google.com/go/compute/apiv1/computepb"
+	"github.com/tchiunam/axolgo-cli/pkg/util"
+	"google.golang.org/api/iterator"
+)
+
+// The label of a snapshot with the name of its source instance
+const snapshotInstanceLabel = "axolgo-instance"
+
+// The max. length of a resource name
+const maxNameLength = 63
+
+// SnapshotInfo is a disk snapshot
+type SnapshotInfo struct {
+	Project string
+	Name    string
+	// SourceDisk is the path of the source disk, e.g.
+	// projects/proj1/zones/asia-east1-a/disks/web-1
+	SourceDisk   string
+	Status       string
+	DiskSizeGB   int64
+	StorageBytes int64
+	// AutoCreated is true if the snapshot is created by a snapshot schedule
+	AutoCreated bool
+	Created     time.Time
+	Labels      map[string]string
+}
+
+// ListSnapshots lists the snapshots of a project
+func ListSnapshots(ctx context.Context, client *compute.SnapshotsClient, project string, filter string) ([]SnapshotInfo, error) {
+	req := &computepb.ListSnapshotsRequest{Project: project}
+	if filter != "" {
+		req.Filter = &filter
+	}
+
+	var snapshots []SnapshotInfo
+	it := client.List(ctx, req)
+	for {
+		snapshot, err := it.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		snapshots = append(snapshots, snapshotInfo(project, snapshot))
+	}
+	sortSnapshots(snapshots)
+
+	return snapshots, nil
+}
+
+// snapshotInfo converts a snapshot
+func snapshotInfo(project string, snapshot *computepb.Snapshot) SnapshotInfo {
+	// The creation time is in RFC 3339 and is zero if it cannot be parsed
+	created, _ := time.Parse(time.RFC3339, snapshot.GetCreationTimestamp())
+
+	return SnapshotInfo{
+		Project:      project,
+		Name:         snapshot.GetName(),
+		SourceDisk:   resourcePath(snapshot.GetSourceDisk()),
+		Status:       snapshot.GetStatus(),
+		DiskSizeGB:   snapshot.GetDiskSizeGb(),
+		StorageBytes: snapshot.GetStorageBytes(),
+		AutoCreated:  snapshot.GetAutoCreated(),
+		Created:      created,
+		Labels:       snapshot.GetLabels(),
+	}
+}
+
+// sortSnapshots sorts the snapshots with the newest first
+func sortSnapshots(snapshots []SnapshotInfo) {
+	sort.SliceStable(snapshots, func(i, j int) bool {
+		return snapshots[i].Created.After(snapshots[j].Created)
+	})
+}
+
+// PlanSnapshotPrune decides which snapshots to remove under a retention
+// policy which is applied to the snapshots of each source disk. A
+// snapshot is retained if it is one of the newest keep snapshots of its
+// disk or if it is younger than maxAge. A policy of zero is not
+// applied. Snapshots which are not ready are always retained.
+func PlanSnapshotPrune(snapshots []SnapshotInfo, keep int, maxAge time.Duration, now time.Time) ([]SnapshotInfo, []SnapshotInfo) {
+	sorted := make([]SnapshotInfo, len(snapshots))
+	copy(sorted, snapshots)
+	sortSnapshots(sorted)
+
+	retain := make([]SnapshotInfo, 0)
+	remove := make([]SnapshotInfo, 0)
+	ready := map[string]int{}
+	for _, s := range sorted {
+		if s.Status != "READY" {
+			retain = append(retain, s)
+			continue
+		}
+		ready[s.SourceDisk]++
+		withinCount := keep > 0 && ready[s.SourceDisk] <= keep
+		withinAge := maxAge > 0 && now.Sub(s.Created) < maxAge
+		if withinCount || withinAge || (keep == 0 && maxAge == 0) {
+			retain = append(retain, s)
+		} else {
+			remove = append(remove, s)
+		}
+	}
+
+	return retain, remove
+}
+
+// SnapshotName returns the name of a snapshot of a disk path which is
+// the disk name with a short hash of the path and the creation time,
+// e.g. web-1-3f2a1c-20221231235959. Snapshot names are global to a
+// project, so the hash tells apart the disks of the same name in
+// different zones and regions. The disk name is truncated to fit the
+// max. length of a name.
+func SnapshotName(disk string, now time.Time) string {
+	sum := sha256.Sum256([]byte(disk))
+	suffix := "-" + hex.EncodeToString(sum[:3]) + "-" + now.UTC().Format("20060102150405")
+	name := lastSegment(disk)
+	if len(name) > maxNameLength-len(suffix) {
+		name = strings.TrimRight(name[:maxNameLength-len(suffix)], "-")
+	}
+
+	return name + suffix
+}
+
+// SnapshotLabels returns the labels of a snapshot of an instance disk.
+// The labels of the instance are copied with a label of the instance
+// name, and the extra labels take precedence.
+func SnapshotLabels(instance InstanceInfo, extra map[string]string) map[string]string {
+	labels := make(map[string]string, len(instance.Labels)+len(extra)+1)
+	for k, v := range instance.Labels {
+		labels[k] = v
+	}
+	labels[snapshotInstanceLabel] = instance.Name
+	for k, v := range extra {
+		labels[k] = v
+	}
+
+	return labels
+}
+
+// diskLocation splits a disk path into the project, the zone or region,
+// and the name, and tells if it is a regional disk
+func diskLocation(path string) (project string, location string, name string, regional bool, err error) {
+	parts := strings.Split(path, "/")
+	if len(parts) != 6 || parts[0] != "projects" || parts[4] != "disks" || (parts[2] != "zones" && parts[2] != "regions") {
+		return "", "", "", false, fmt.Errorf("invalid disk: %s", path)
+	}
+
+	return parts[1], parts[3], parts[5], parts[2] == "regions", nil
+}
+
+// snapshotRows formats the columns of the snapshots
+func snapshotRows(snapshots []SnapshotInfo, now time.Time) [][]string {
+	rows := make([][]string, 0, len(snapshots))
+	for _, s := range snapshots {
+		created, age := "-", "-"
+		if !s.Created.IsZero() {
+			created = s.Created.Format(time.RFC3339)
+			age = util.FormatAge(now.Sub(s.Created))
+		}
+		rows = append(rows, []string{
+			s.Project,
+			s.Name,
+			lastSegment(s.SourceDisk),
+			s.Status,
+			strconv.FormatInt(s.DiskSizeGB, 10),
+			fmt.Sprintf("%.1f", float64(s.StorageBytes)/(1<<30)),
+			created,
+			age,
+			formatLabels(s.Labels),
+		})
+	}
+
+	return rows
+}
+
+// The header of the snapshot rows
+var snapshotHeader = []string{"PROJECT", "NAME", "SOURCE DISK", "STATUS", "DISK SIZE (GB)", "STORAGE (GB)", "CREATED", "AGE", "LABELS"}
@@ -0,0 +1,204 @@
+/*
+Copyright © 2022 tchiunam
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package compute
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/compute/apiv1/computepb"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+)
+
+// TestSnapshotInfo tests the snapshotInfo function
+func TestSnapshotInfo(t *testing.T) {
+	snapshot := &computepb.Snapshot{
+		Name:              proto.String("web-1-20221231235959"),
+		SourceDisk:        proto.String("https://www.googleapis.com/compute/v1/projects/proj1/zones/asia-east1-a/disks/web-1"),
+		Status:            proto.String("READY"),
+		DiskSizeGb:        proto.Int64(20),
+		StorageBytes:      proto.Int64(1 << 30),
+		CreationTimestamp: proto.String("2022-12-31T23:59:59.000-08:00"),
+		Labels:            map[string]string{"axolgo-instance": "web-1"},
+	}
+
+	info := snapshotInfo("proj1", snapshot)
+	assert.Equal(t, "web-1-20221231235959", info.Name)
+	assert.Equal(t, "projects/proj1/zones/asia-east1-a/disks/web-1", info.SourceDisk)
+	assert.Equal(t, "READY", info.Status)
+	assert.Equal(t, int64(20), info.DiskSizeGB)
+	assert.False(t, info.AutoCreated)
+	assert.True(t, time.Date(2023, 1, 1, 7, 59, 59, 0, time.UTC).Equal(info.Created))
+
+	rows := snapshotRows([]SnapshotInfo{info}, info.Created.Add(50*time.Hour))
+	assert.Equal(t, []string{"proj1", "web-1-20221231235959", "web-1", "READY", "20", "1.0", "2022-12-31T23:59:59-08:00", "2d2h", "axolgo-instance=web-1"}, rows[0])
+	assert.Len(t, rows[0], len(snapshotHeader))
+}
+
+// TestPlanSnapshotPrune tests the PlanSnapshotPrune function
+func TestPlanSnapshotPrune(t *testing.T) {
+	now := time.Date(2023, 1, 31, 0, 0, 0, 0, time.UTC)
+	web := "projects/proj1/zones/asia-east1-a/disks/web-1"
+	db := "projects/proj1/zones/asia-east1-a/disks/db-1"
+	snapshots := []SnapshotInfo{
+		{Name: "web-1-d1", SourceDisk: web, Status: "READY", Created: now.Add(-1 * 24 * time.Hour)},
+		{Name: "web-1-d10", SourceDisk: web, Status: "READY", Created: now.Add(-10 * 24 * time.Hour)},
+		{Name: "web-1-d40", SourceDisk: web, Status: "READY", Created: now.Add(-40 * 24 * time.Hour)},
+		{Name: "web-1-d50", SourceDisk: web, Status: "UPLOADING", Created: now.Add(-50 * 24 * time.Hour)},
+		{Name: "db-1-d20", SourceDisk: db, Status: "READY", Created: now.Add(-20 * 24 * time.Hour)},
+		{Name: "db-1-d60", SourceDisk: db, Status: "READY", Created: now.Add(-60 * 24 * time.Hour)},
+	}
+
+	cases := map[string]struct {
+		keep   int
+		maxAge time.Duration
+		retain []string
+		remove []string
+	}{
+		"keep the newest of each disk": {
+			keep:   1,
+			retain: []string{"web-1-d1", "db-1-d20", "web-1-d50"},
+			remove: []string{"web-1-d10", "web-1-d40", "db-1-d60"},
+		},
+		"max age": {
+			maxAge: 30 * 24 * time.Hour,
+			retain: []string{"web-1-d1", "web-1-d10", "db-1-d20", "web-1-d50"},
+			remove: []string{"web-1-d40", "db-1-d60"},
+		},
+		"keep or max age": {
+			keep:   2,
+			maxAge: 5 * 24 * time.Hour,
+			retain: []string{"web-1-d1", "web-1-d10", "db-1-d20", "web-1-d50", "db-1-d60"},
+			remove: []string{"web-1-d40"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			retain, remove := PlanSnapshotPrune(snapshots, tc.keep, tc.maxAge, now)
+			assert.Equal(t, tc.retain, snapshotNames(retain))
+			assert.Equal(t, tc.remove, snapshotNames(remove))
+		})
+	}
+}
+
+// TestSnapshotName tests the SnapshotName function
+func TestSnapshotName(t *testing.T) {
+	now := time.Date(2022, 12, 31, 23, 59, 59, 0, time.UTC)
+	cases := map[string]struct {
+		disk string
+		name string
+	}{
+		"short disk name": {
+			disk: "projects/proj1/zones/asia-east1-a/disks/web-1",
+			name: "web-1-97ca4d-20221231235959",
+		},
+		"same disk name in another zone": {
+			disk: "projects/proj1/zones/asia-east1-b/disks/web-1",
+			name: "web-1-bcad4b-20221231235959",
+		},
+		"same disk name in a region": {
+			disk: "projects/proj1/regions/asia-east1/disks/web-1",
+			name: "web-1-663d35-20221231235959",
+		},
+		"long disk name": {
+			disk: "projects/proj1/zones/asia-east1-a/disks/" + strings.Repeat("a", 47) + "-data",
+			name: strings.Repeat("a", 41) + "-983ca0-20221231235959",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			n := SnapshotName(tc.disk, now)
+			assert.Equal(t, tc.name, n)
+			assert.LessOrEqual(t, len(n), maxNameLength)
+		})
+	}
+}
+
+// TestSnapshotLabels tests the SnapshotLabels function
+func TestSnapshotLabels(t *testing.T) {
+	instance := InstanceInfo{Name: "web-1", Labels: map[string]string{"env": "prod", "team": "web"}}
+
+	labels := SnapshotLabels(instance, map[string]string{"team": "sre"})
+	assert.Equal(t, map[string]string{"env": "prod", "team": "sre", "axolgo-instance": "web-1"}, labels)
+	assert.Equal(t, map[string]string{"env": "prod", "team": "web"}, instance.Labels)
+}
+
+// TestDiskLocation tests the diskLocation function
+func TestDiskLocation(t *testing.T) {
+	cases := map[string]struct {
+		path     string
+		project  string
+		location string
+		name     string
+		regional bool
+		valid    bool
+	}{
+		"zonal disk": {
+			path:     "projects/proj1/zones/asia-east1-a/disks/web-1",
+			project:  "proj1",
+			location: "asia-east1-a",
+			name:     "web-1",
+			valid:    true,
+		},
+		"regional disk": {
+			path:     "projects/proj1/regions/asia-east1/disks/shared-1",
+			project:  "proj1",
+			location: "asia-east1",
+			name:     "shared-1",
+			regional: true,
+			valid:    true,
+		},
+		"not a disk": {
+			path: "projects/proj1/zones/asia-east1-a/instances/web-1",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			project, location, n, regional, err := diskLocation(tc.path)
+			if tc.valid {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.project, project)
+				assert.Equal(t, tc.location, location)
+				assert.Equal(t, tc.name, n)
+				assert.Equal(t, tc.regional, regional)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
+
+// snapshotNames returns the names of the snapshots
+func snapshotNames(snapshots []SnapshotInfo) []string {
+	names := make([]string, 0, len(snapshots))
+	for _, s := range snapshots {
+		names = append(names, s.Name)
+	}
+
+	return names
+}
@@ -0,0 +1,174 @@
+/*
+Copyright © 2022 tchiunam
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package container
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/tchiunam/axolgo-cli/pkg/util"
+	container "google.golang.org/api/container/v1"
+	"k8s.io/klog/v2"
+)
+
+// ClusterInfo is a GKE cluster
+type ClusterInfo struct {
+	Project         string
+	Name            string
+	Location        string
+	MasterVersion   string
+	Status          string
+	NodeCount       int64
+	Autopilot       bool
+	Endpoint        string
+	PrivateEndpoint string
+	// CACertificate is the base64 encoded CA certificate of the cluster
+	CACertificate string
+}
+
+// NewService creates a GKE client
+func NewService(ctx context.Context) (*container.Service, error) {
+	opts, err := util.GCPClientOptions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	s, err := container.NewService(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create GKE client: %w", err)
+	}
+
+	return s, nil
+}
+
+// ListClusters lists the GKE clusters of a project in a location. If
+// location is empty, the clusters in all locations are listed.
+func ListClusters(ctx context.Context, s *container.Service, project string, location string) ([]ClusterInfo, error) {
+	if location == "" {
+		location = "-"
+	}
+	resp, err := s.Projects.Locations.Clusters.List(fmt.Sprintf("projects/%s/locations/%s", project, location)).Context(ctx).Do()
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.MissingZones) > 0 {
+		klog.Warningf("The clusters in %s are not listed because the zones are unavailable", strings.Join(resp.MissingZones, ","))
+	}
+
+	clusters := make([]ClusterInfo, 0, len(resp.Clusters))
+	for _, c := range resp.Clusters {
+		clusters = append(clusters, clusterInfo(project, c))
+	}
+	sort.SliceStable(clusters, func(i, j int) bool {
+		if clusters[i].Name != clusters[j].Name {
+			return clusters[i].Name < clusters[j].Name
+		}
+		return clusters[i].Location < clusters[j].Location
+	})
+
+	return clusters, nil
+}
+
+// FindCluster gets a GKE cluster by name. If location is empty, the
+// cluster is looked up in all locations and the name must be unique.
+func FindCluster(ctx context.Context, s *container.Service, project string, location string, name string) (ClusterInfo, error) {
+	if location != "" {
+		c, err := s.Projects.Locations.Clusters.Get(fmt.Sprintf("projects/%s/locations/%s/clusters/%s", project, location, name)).Context(ctx).Do()
+		if err != nil {
+			return ClusterInfo{}, fmt.Errorf("failed to get cluster %s in %s: %w", name, location, err)
+		}
+		return clusterInfo(project, c), nil
+	}
+
+	clusters, err := ListClusters(ctx, s, project, "")
+	if err != nil {
+		return ClusterInfo{}, fmt.Errorf("failed to list clusters: %w", err)
+	}
+	var found []ClusterInfo
+	var locations []string
+	for _, c := range clusters {
+		if c.Name == name {
+			found = append(found, c)
+			locations = append(locations, c.Location)
+		}
+	}
+	switch len(found) {
+	case 0:
+		return ClusterInfo{}, fmt.Errorf("cluster %s is not found in project %s", name, project)
+	case 1:
+		return found[0], nil
+	default:
+		return ClusterInfo{}, fmt.Errorf("cluster %s is found in %s, specify the location", name, strings.Join(locations, ","))
+	}
+}
+
+// ContextName returns the name of the kubeconfig context of a cluster,
+// which is the same as gcloud's
+func ContextName(c ClusterInfo) string {
+	return fmt.Sprintf("gke_%s_%s_%s", c.Project, c.Location, c.Name)
+}
+
+// clusterInfo converts a GKE cluster
+func clusterInfo(project string, c *container.Cluster) ClusterInfo {
+	info := ClusterInfo{
+		Project:       project,
+		Name:          c.Name,
+		Location:      c.Location,
+		MasterVersion: c.CurrentMasterVersion,
+		Status:        c.Status,
+		NodeCount:     c.CurrentNodeCount,
+		Endpoint:      c.Endpoint,
+		Autopilot:     c.Autopilot != nil && c.Autopilot.Enabled,
+	}
+	if c.PrivateClusterConfig != nil {
+		info.PrivateEndpoint = c.PrivateClusterConfig.PrivateEndpoint
+	}
+	if c.MasterAuth != nil {
+		info.CACertificate = c.MasterAuth.ClusterCaCertificate
+	}
+
+	return info
+}
+
+// clusterRows formats the columns of the clusters
+func clusterRows(clusters []ClusterInfo) [][]string {
+	rows := make([][]string, 0, len(clusters))
+	for _, c := range clusters {
+		mode := "Standard"
+		if c.Autopilot {
+			mode = "Autopilot"
+		}
+		endpoint := c.Endpoint
+		if endpoint == "" {
+			endpoint = "-"
+		}
+		rows = append(rows, []string{c.Project, c.Name, c.Location, mode, c.MasterVersion, c.Status, strconv.FormatInt(c.NodeCount, 10), endpoint})
+	}
+
+	return rows
+}
+
+// The header of the cluster rows
+var clusterHeader = []string{"PROJECT", "NAME", "LOCATION", "MODE", "MASTER VERSION", "STATUS", "NODES", "ENDPOINT"}
@@ -0,0 +1,171 @@
+/*
+Copyright © 2022 tchiunam
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package container
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	container "google.golang.org/api/container/v1"
+	"google.golang.org/api/option"
+)
+
+// newTestService creates a GKE client of a test server with clusters
+func newTestService(t *testing.T, clusters ...*container.Cluster) *container.Service {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/projects/proj1/locations/-/clusters":
+			json.NewEncoder(w).Encode(container.ListClustersResponse{Clusters: clusters, MissingZones: []string{"asia-east1-c"}})
+		case "/v1/projects/proj1/locations/asia-east1/clusters":
+			var found []*container.Cluster
+			for _, c := range clusters {
+				if c.Location == "asia-east1" {
+					found = append(found, c)
+				}
+			}
+			json.NewEncoder(w).Encode(container.ListClustersResponse{Clusters: found})
+		case "/v1/projects/proj1/locations/asia-east1/clusters/web":
+			json.NewEncoder(w).Encode(clusters[0])
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+	s, err := container.NewService(context.TODO(), option.WithEndpoint(server.URL), option.WithoutAuthentication())
+	assert.NoError(t, err)
+
+	return s
+}
+
+// The clusters of the test server
+var testClusters = []*container.Cluster{
+	{
+		Name:                 "web",
+		Location:             "asia-east1",
+		CurrentMasterVersion: "1.24.8-gke.2000",
+		Status:               "RUNNING",
+		CurrentNodeCount:     3,
+		Endpoint:             "34.80.0.1",
+		Autopilot:            &container.Autopilot{Enabled: true},
+		PrivateClusterConfig: &container.PrivateClusterConfig{PrivateEndpoint: "10.0.0.2"},
+		MasterAuth:           &container.MasterAuth{ClusterCaCertificate: "Q0E="},
+	},
+	{
+		Name:                 "batch",
+		Location:             "asia-east1-a",
+		CurrentMasterVersion: "1.23.14-gke.1800",
+		Status:               "PROVISIONING",
+	},
+	{
+		Name:     "batch",
+		Location: "us-central1",
+		Status:   "RUNNING",
+		Endpoint: "35.0.0.1",
+	},
+}
+
+// TestListClusters tests the ListClusters function and the cluster rows
+func TestListClusters(t *testing.T) {
+	s := newTestService(t, testClusters...)
+	cases := map[string]struct {
+		location string
+		rows     [][]string
+	}{
+		"all locations": {
+			rows: [][]string{
+				{"proj1", "batch", "asia-east1-a", "Standard", "1.23.14-gke.1800", "PROVISIONING", "0", "-"},
+				{"proj1", "batch", "us-central1", "Standard", "", "RUNNING", "0", "35.0.0.1"},
+				{"proj1", "web", "asia-east1", "Autopilot", "1.24.8-gke.2000", "RUNNING", "3", "34.80.0.1"},
+			},
+		},
+		"region": {
+			location: "asia-east1",
+			rows: [][]string{
+				{"proj1", "web", "asia-east1", "Autopilot", "1.24.8-gke.2000", "RUNNING", "3", "34.80.0.1"},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			clusters, err := ListClusters(context.TODO(), s, "proj1", tc.location)
+			assert.NoError(t, err)
+			rows := clusterRows(clusters)
+			assert.Equal(t, tc.rows, rows)
+			assert.Len(t, rows[0], len(clusterHeader))
+		})
+	}
+}
+
+// TestFindCluster tests the FindCluster function
+func TestFindCluster(t *testing.T) {
+	s := newTestService(t, testClusters...)
+	cases := map[string]struct {
+		location string
+		name     string
+		found    string
+		valid    bool
+	}{
+		"location": {
+			location: "asia-east1",
+			name:     "web",
+			found:    "asia-east1",
+			valid:    true,
+		},
+		"unique name": {
+			name:  "web",
+			found: "asia-east1",
+			valid: true,
+		},
+		"duplicated name": {
+			name: "batch",
+		},
+		"not found": {
+			name: "api",
+		},
+		"not found in location": {
+			location: "asia-east1",
+			name:     "batch",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cluster, err := FindCluster(context.TODO(), s, "proj1", tc.location, tc.name)
+			if tc.valid {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.name, cluster.Name)
+				assert.Equal(t, tc.found, cluster.Location)
+				assert.Equal(t, "Q0E=", cluster.CACertificate)
+				assert.Equal(t, "10.0.0.2", cluster.PrivateEndpoint)
+				assert.Equal(t, "gke_proj1_asia-east1_web", ContextName(cluster))
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
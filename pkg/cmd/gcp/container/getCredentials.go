@@ -0,0 +1,142 @@
+/*
+Copyright © 2022 tchiunam
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package container
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/tchiunam/axolgo-cli/pkg/util"
+	"k8s.io/klog/v2"
+)
+
+var (
+	getCredentialsLong = `Add or update the cluster, user and context of a GKE cluster in a
+kubeconfig file and make it the current context. The other entries of
+the file are kept. The user gets tokens by running this axolgo binary
+as the exec credential plugin with the same credentials as the other
+GCP commands, so gcloud is not required.
+`
+	getCredentialsExample = `  # Get the credentials of a cluster in any location
+  axolgo gcp container getCredentials web --project proj1
+
+  # Get the credentials to use the private endpoint into a kubeconfig file
+  axolgo gcp container getCredentials web --location asia-east1 --internal-ip --kubeconfig ~/.kube/gke
+`
+)
+
+// GetCredentialsOptions defines flags and other configuration parameters for the `getCredentials` command
+type GetCredentialsOptions struct {
+	Project    string
+	Location   string
+	Kubeconfig string
+	InternalIP bool
+}
+
+// NewCmdGetCredentials creates the `getCredentials` command
+func NewCmdGetCredentials(ctx *context.Context) *cobra.Command {
+	o := GetCredentialsOptions{}
+
+	cmd := &cobra.Command{
+		Use:                   "getCredentials CLUSTER [-p] [-l] [--kubeconfig] [--internal-ip]",
+		DisableFlagsInUseLine: true,
+		Short:                 "Write the credentials of a GKE cluster to a kubeconfig file.",
+		Long:                  getCredentialsLong,
+		Example:               getCredentialsExample,
+		Args:                  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := o.complete(ctx, cmd, args); err != nil {
+				panic(err)
+			}
+		},
+	}
+
+	cmd.Flags().StringVarP(&o.Project, "project", "p", "", "Project ID. Default is the project in axolgo configuration.")
+	cmd.Flags().StringVarP(&o.Location, "location", "l", "", "Region or zone of the cluster. Default is to look up the cluster in all locations.")
+	cmd.Flags().StringVar(&o.Kubeconfig, "kubeconfig", "", "Kubeconfig file. Default is the first file in KUBECONFIG or ~/.kube/config.")
+	cmd.Flags().BoolVar(&o.InternalIP, "internal-ip", false, "Use the private endpoint of the cluster.")
+
+	return cmd
+}
+
+// Complete takes the command arguments and execute.
+func (o *GetCredentialsOptions) complete(ctx *context.Context, _ *cobra.Command, args []string) error {
+	project, err := util.GCPProject(o.Project)
+	if err != nil {
+		return err
+	}
+
+	s, err := NewService(context.TODO())
+	if err != nil {
+		return err
+	}
+	cluster, err := FindCluster(context.TODO(), s, project, o.Location, args[0])
+	if err != nil {
+		return err
+	}
+
+	exec, err := util.GCPExec("container", "getToken")
+	if err != nil {
+		return err
+	}
+
+	path := util.KubeconfigPath(o.Kubeconfig)
+	name, err := UpdateKubeconfig(path, cluster, o.InternalIP, exec)
+	if err != nil {
+		return err
+	}
+	klog.Infof("Added context %s to kubeconfig %s", name, path)
+
+	return nil
+}
+
+// UpdateKubeconfig adds or updates the cluster, user and context of a
+// GKE cluster in a kubeconfig file and makes the context current. It
+// returns the name of the context.
+func UpdateKubeconfig(path string, cluster ClusterInfo, internalIP bool, exec util.KubeExec) (string, error) {
+	endpoint := cluster.Endpoint
+	if internalIP {
+		if endpoint = cluster.PrivateEndpoint; endpoint == "" {
+			return "", fmt.Errorf("cluster %s has no private endpoint", cluster.Name)
+		}
+	}
+	if endpoint == "" {
+		return "", fmt.Errorf("cluster %s has no endpoint, its status is %s", cluster.Name, cluster.Status)
+	}
+
+	k, err := util.LoadKubeconfig(path)
+	if err != nil {
+		return "", err
+	}
+	name := ContextName(cluster)
+	k.SetCluster(name, "https://"+endpoint, cluster.CACertificate)
+	k.SetUser(name, exec)
+	k.SetContext(name, name, name)
+	k.CurrentContext = name
+	if err := k.Save(path); err != nil {
+		return "", fmt.Errorf("failed to write kubeconfig %s: %w", path, err)
+	}
+
+	return name, nil
+}
@@ -0,0 +1,116 @@
+/*
+Copyright © 2022 tchiunam
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package container
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tchiunam/axolgo-cli/pkg/util"
+)
+
+// TestNewCmdGetCredentials tests the NewCmdGetCredentials function
+// to make sure it returns a valid command.
+func TestNewCmdGetCredentials(t *testing.T) {
+	cases := map[string]struct {
+		use      string
+		short    string
+		hasFlags bool
+	}{
+		"valid command": {
+			use:      "getCredentials CLUSTER [-p] [-l] [--kubeconfig] [--internal-ip]",
+			short:    "Write the credentials of a GKE cluster to a kubeconfig file.",
+			hasFlags: true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cmd := NewCmdGetCredentials(nil)
+			assert.Equal(t, tc.use, cmd.Use)
+			assert.Equal(t, tc.short, cmd.Short)
+			assert.Equal(t, tc.hasFlags, cmd.Flags().HasFlags())
+		})
+	}
+}
+
+// TestUpdateKubeconfig tests the UpdateKubeconfig function
+func TestUpdateKubeconfig(t *testing.T) {
+	cluster := ClusterInfo{
+		Project:         "proj1",
+		Name:            "web",
+		Location:        "asia-east1",
+		Status:          "RUNNING",
+		Endpoint:        "34.80.0.1",
+		PrivateEndpoint: "10.0.0.2",
+		CACertificate:   "Q0E=",
+	}
+	exec := util.KubeExec{Command: "/usr/local/bin/axolgo", Args: []string{"gcp", "container", "getToken"}}
+	cases := map[string]struct {
+		cluster    ClusterInfo
+		internalIP bool
+		server     string
+		valid      bool
+	}{
+		"public endpoint": {
+			cluster: cluster,
+			server:  "https://34.80.0.1",
+			valid:   true,
+		},
+		"private endpoint": {
+			cluster:    cluster,
+			internalIP: true,
+			server:     "https://10.0.0.2",
+			valid:      true,
+		},
+		"no private endpoint": {
+			cluster:    ClusterInfo{Project: "proj1", Name: "web", Location: "asia-east1", Endpoint: "34.80.0.1"},
+			internalIP: true,
+		},
+		"provisioning": {
+			cluster: ClusterInfo{Project: "proj1", Name: "web", Location: "asia-east1", Status: "PROVISIONING"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "config")
+			contextName, err := UpdateKubeconfig(path, tc.cluster, tc.internalIP, exec)
+			if !tc.valid {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, "gke_proj1_asia-east1_web", contextName)
+
+			k, err := util.LoadKubeconfig(path)
+			assert.NoError(t, err)
+			assert.Equal(t, contextName, k.CurrentContext)
+			assert.Equal(t, tc.server, k.Clusters[0].Cluster["server"])
+			assert.Equal(t, "Q0E=", k.Clusters[0].Cluster["certificate-authority-data"])
+			assert.Equal(t, contextName, k.Contexts[0].Context["user"])
+			assert.Equal(t, "/usr/local/bin/axolgo", k.Users[0].User["exec"].(map[string]interface{})["command"])
+		})
+	}
+}
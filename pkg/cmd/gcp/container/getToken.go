@@ -0,0 +1,81 @@
+/*
+Copyright © 2022 tchiunam
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package container
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/tchiunam/axolgo-cli/pkg/util"
+)
+
+var (
+	getTokenLong = `Print an ExecCredential with an access token of the GCP credentials
+for kubectl. It is the exec credential plugin of the kubeconfig users
+which are written by getCredentials.
+`
+	getTokenExample = `  # Print an ExecCredential
+  axolgo gcp container getToken
+`
+)
+
+// GetTokenOptions defines flags and other configuration parameters for the `getToken` command
+type GetTokenOptions struct {
+}
+
+// NewCmdGetToken creates the `getToken` command
+func NewCmdGetToken(ctx *context.Context) *cobra.Command {
+	o := GetTokenOptions{}
+
+	cmd := &cobra.Command{
+		Use:                   "getToken",
+		DisableFlagsInUseLine: true,
+		Short:                 "Print an ExecCredential of GCP credentials for kubectl.",
+		Long:                  getTokenLong,
+		Example:               getTokenExample,
+		Args:                  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := o.complete(ctx, cmd, args); err != nil {
+				panic(err)
+			}
+		},
+	}
+
+	return cmd
+}
+
+// Complete takes the command arguments and execute.
+func (o *GetTokenOptions) complete(ctx *context.Context, _ *cobra.Command, args []string) error {
+	ts, err := util.LoadGCPAuth().TokenSource(context.TODO())
+	if err != nil {
+		return err
+	}
+	token, err := ts.Token()
+	if err != nil {
+		return fmt.Errorf("failed to get token: %w", err)
+	}
+
+	return util.PrintExecCredential(os.Stdout, token.AccessToken, token.Expiry)
+}
@@ -0,0 +1,95 @@
+/*
+Copyright © 2022 tchiunam
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package container
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/tchiunam/axolgo-cli/pkg/util"
+)
+
+var (
+	listClustersLong = `List the GKE clusters of a project in all locations, or in a region
+or zone, with their mode, master version, status, no. of nodes and
+endpoint.
+`
+	listClustersExample = `  # List the clusters of a project in all locations
+  axolgo gcp container listClusters --project proj1
+
+  # List the clusters in a region
+  axolgo gcp container listClusters --location asia-east1
+`
+)
+
+// ListClustersOptions defines flags and other configuration parameters for the `listClusters` command
+type ListClustersOptions struct {
+	Project  string
+	Location string
+	Output   string
+}
+
+// NewCmdListClusters creates the `listClusters` command
+func NewCmdListClusters(ctx *context.Context) *cobra.Command {
+	o := ListClustersOptions{}
+
+	cmd := &cobra.Command{
+		Use:                   "listClusters [-p] [-l] [-o]",
+		DisableFlagsInUseLine: true,
+		Short:                 "List GKE clusters.",
+		Long:                  listClustersLong,
+		Example:               listClustersExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := o.complete(ctx, cmd, args); err != nil {
+				panic(err)
+			}
+		},
+	}
+
+	cmd.Flags().StringVarP(&o.Project, "project", "p", "", "Project ID. Default is the project in axolgo configuration.")
+	cmd.Flags().StringVarP(&o.Location, "location", "l", "", "Region or zone of the clusters. Default is all locations.")
+	cmd.Flags().StringVarP(&o.Output, "output", "o", util.OutputTable, fmt.Sprintf("Output format. One of %v.", util.OutputFormats))
+
+	return cmd
+}
+
+// Complete takes the command arguments and execute.
+func (o *ListClustersOptions) complete(ctx *context.Context, _ *cobra.Command, args []string) error {
+	project, err := util.GCPProject(o.Project)
+	if err != nil {
+		return err
+	}
+
+	s, err := NewService(context.TODO())
+	if err != nil {
+		return err
+	}
+	clusters, err := ListClusters(context.TODO(), s, project, o.Location)
+	if err != nil {
+		return fmt.Errorf("failed to list clusters: %w", err)
+	}
+
+	return util.PrintRecords(os.Stdout, o.Output, clusterHeader, clusterRows(clusters))
+}
@@ -27,7 +27,14 @@ import (
 	"os"
 
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	cmdauth "github.com/tchiunam/axolgo-cli/pkg/cmd/gcp/auth"
 	cmdcompute "github.com/tchiunam/axolgo-cli/pkg/cmd/gcp/compute"
+	cmdcontainer "github.com/tchiunam/axolgo-cli/pkg/cmd/gcp/container"
+	cmdiam "github.com/tchiunam/axolgo-cli/pkg/cmd/gcp/iam"
+	cmdpubsub "github.com/tchiunam/axolgo-cli/pkg/cmd/gcp/pubsub"
+	cmdsql "github.com/tchiunam/axolgo-cli/pkg/cmd/gcp/sql"
+	cmdstorage "github.com/tchiunam/axolgo-cli/pkg/cmd/gcp/storage"
 )
 
 // NewGCPCmd creates the `gcp` command
@@ -42,8 +49,18 @@ func NewGCPCmd(ctx *context.Context) *cobra.Command {
 		},
 	}
 
+	// The flag overrides the service account in axolgo configuration
+	cmd.PersistentFlags().String("impersonate-service-account", "", "Service account to impersonate. A comma separated list is a delegation chain in which the last one is impersonated.")
+	viper.BindPFlag("gcp.impersonate-service-account", cmd.PersistentFlags().Lookup("impersonate-service-account"))
+
 	cmd.AddCommand(
+		cmdauth.NewAuthCmd(ctx),
 		cmdcompute.NewComputeCmd(ctx),
+		cmdcontainer.NewContainerCmd(ctx),
+		cmdiam.NewIAMCmd(ctx),
+		cmdpubsub.NewPubSubCmd(ctx),
+		cmdsql.NewSQLCmd(ctx),
+		cmdstorage.NewStorageCmd(ctx),
 	)
 
 	return cmd
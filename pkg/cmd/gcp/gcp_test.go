@@ -40,7 +40,7 @@ func TestNewGCPCmd(t *testing.T) {
 			use:      "gcp",
 			short:    "A set of GCP commands",
 			long:     "A set of GCP commands",
-			commands: 1,
+			commands: 7,
 		},
 	}
 
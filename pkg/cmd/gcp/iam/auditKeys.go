@@ -0,0 +1,166 @@
+/*
+Copyright © 2022 tchiunam
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package iam
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/tchiunam/axolgo-cli/pkg/util"
+	"k8s.io/klog/v2"
+)
+
+// ErrStaleKeys is returned when there are keys older than the max. age
+var ErrStaleKeys = errors.New("stale service account keys found")
+
+var (
+	auditKeysLong = `Audit the user-managed keys of the service accounts in the projects.
+The keys are listed with their age and the keys older than --max-age
+are flagged as stale. Google-managed keys are rotated by Google and
+are not listed.
+
+The command exits with code 1 if there is any stale key.
+`
+	auditKeysExample = `  # Audit the keys in the project in axolgo configuration
+  axolgo gcp iam auditKeys
+
+  # Report the keys older than 30 days in projects as CSV
+  axolgo gcp iam auditKeys --projects proj1,proj2 --max-age 30d --stale-only -o csv
+`
+)
+
+// AuditKeysOptions defines flags and other configuration parameters for the `auditKeys` command
+type AuditKeysOptions struct {
+	Project   string
+	Projects  []string
+	MaxAge    string
+	StaleOnly bool
+	Output    string
+}
+
+// NewCmdAuditKeys creates the `auditKeys` command
+func NewCmdAuditKeys(ctx *context.Context) *cobra.Command {
+	o := AuditKeysOptions{}
+
+	cmd := &cobra.Command{
+		Use:                   "auditKeys [-p] [--projects] [--max-age] [--stale-only] [-o]",
+		DisableFlagsInUseLine: true,
+		Short:                 "Audit the age of user-managed service account keys.",
+		Long:                  auditKeysLong,
+		Example:               auditKeysExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := o.complete(ctx, cmd, args); err != nil {
+				if errors.Is(err, ErrStaleKeys) {
+					klog.Error(err)
+					os.Exit(1)
+				}
+				panic(err)
+			}
+		},
+	}
+
+	cmd.Flags().StringVarP(&o.Project, "project", "p", "", "Project ID. Default is the project in axolgo configuration.")
+	cmd.Flags().StringSliceVar(&o.Projects, "projects", nil, "Comma separated project IDs.")
+	cmd.Flags().StringVar(&o.MaxAge, "max-age", "90d", "Max. age of a key, e.g. 90d or 720h. An older key is stale.")
+	cmd.Flags().BoolVar(&o.StaleOnly, "stale-only", false, "List the stale keys only.")
+	cmd.Flags().StringVarP(&o.Output, "output", "o", util.OutputTable, fmt.Sprintf("Output format. One of %v.", util.OutputFormats))
+
+	return cmd
+}
+
+// Complete takes the command arguments and execute.
+func (o *AuditKeysOptions) complete(ctx *context.Context, _ *cobra.Command, args []string) error {
+	maxAge, err := util.ParseAge(o.MaxAge)
+	if err != nil {
+		return err
+	}
+	if maxAge == 0 {
+		return fmt.Errorf("invalid max. age: %s", o.MaxAge)
+	}
+	projects, err := defaultProjects(uniqueProjects(append([]string{o.Project}, o.Projects...)))
+	if err != nil {
+		return err
+	}
+
+	s, err := NewService(context.TODO())
+	if err != nil {
+		return err
+	}
+	// A project which fails makes the audit incomplete, so it aborts
+	var keys []KeyInfo
+	for _, project := range projects {
+		k, err := ListProjectKeys(context.TODO(), s, project)
+		if err != nil {
+			return fmt.Errorf("failed to audit keys in project %s: %w", project, err)
+		}
+		keys = append(keys, k...)
+	}
+
+	now := time.Now()
+	stale := StaleKeys(keys, maxAge, now)
+	klog.V(3).InfoS("Audit service account keys", "keys", len(keys), "stale", len(stale))
+	if o.StaleOnly {
+		keys = stale
+	}
+	if err := util.PrintRecords(os.Stdout, o.Output, keyHeader, keyRows(keys, maxAge, now)); err != nil {
+		return err
+	}
+	if len(stale) > 0 {
+		return fmt.Errorf("%w: %d keys are older than %s", ErrStaleKeys, len(stale), o.MaxAge)
+	}
+
+	return nil
+}
+
+// uniqueProjects returns the non-empty projects without duplicates
+func uniqueProjects(projects []string) []string {
+	var unique []string
+	seen := map[string]bool{}
+	for _, p := range projects {
+		if p = strings.TrimSpace(p); p != "" && !seen[p] {
+			seen[p] = true
+			unique = append(unique, p)
+		}
+	}
+
+	return unique
+}
+
+// defaultProjects returns the projects, or the project in axolgo
+// configuration if none is given
+func defaultProjects(projects []string) ([]string, error) {
+	if len(projects) > 0 {
+		return projects, nil
+	}
+	project, err := util.GCPProject("")
+	if err != nil {
+		return nil, err
+	}
+
+	return []string{project}, nil
+}
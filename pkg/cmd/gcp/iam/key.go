@@ -0,0 +1,241 @@
+/*
+Copyright © 2022 tchiunam
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package iam
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/tchiunam/axolgo-cli/pkg/util"
+	iam "google.golang.org/api/iam/v1"
+)
+
+// A user-managed key which never expires has this expiry time
+const neverExpires = "9999-12-31T23:59:59Z"
+
+// KeyInfo is a user-managed key of a service account
+type KeyInfo struct {
+	Project        string
+	ServiceAccount string
+	ID             string
+	Algorithm      string
+	Origin         string
+	Disabled       bool
+	Created        time.Time
+	// Expires is zero if the key never expires
+	Expires time.Time
+}
+
+// Age returns the age of the key at a time
+func (k KeyInfo) Age(now time.Time) time.Duration {
+	return now.Sub(k.Created)
+}
+
+// NewService creates an IAM client
+func NewService(ctx context.Context) (*iam.Service, error) {
+	opts, err := util.GCPClientOptions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	s, err := iam.NewService(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create IAM client: %w", err)
+	}
+
+	return s, nil
+}
+
+// ServiceAccountName returns the resource name of a service account. A
+// name without a domain is an account ID in the project.
+func ServiceAccountName(project string, account string) string {
+	if !strings.Contains(account, "@") {
+		account = fmt.Sprintf("%s@%s.iam.gserviceaccount.com", account, project)
+	}
+
+	return "projects/-/serviceAccounts/" + account
+}
+
+// ListServiceAccounts lists the emails of the service accounts of a project
+func ListServiceAccounts(ctx context.Context, s *iam.Service, project string) ([]string, error) {
+	var emails []string
+	err := s.Projects.ServiceAccounts.List("projects/"+project).Pages(ctx, func(resp *iam.ListServiceAccountsResponse) error {
+		for _, a := range resp.Accounts {
+			emails = append(emails, a.Email)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(emails)
+
+	return emails, nil
+}
+
+// ListKeys lists the user-managed keys of a service account from the
+// oldest to the newest
+func ListKeys(ctx context.Context, s *iam.Service, project string, account string) ([]KeyInfo, error) {
+	resp, err := s.Projects.ServiceAccounts.Keys.List(ServiceAccountName(project, account)).KeyTypes("USER_MANAGED").Context(ctx).Do()
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]KeyInfo, 0, len(resp.Keys))
+	for _, k := range resp.Keys {
+		info, err := keyInfo(project, k)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, info)
+	}
+	sort.SliceStable(keys, func(i, j int) bool {
+		return keys[i].Created.Before(keys[j].Created)
+	})
+
+	return keys, nil
+}
+
+// ListProjectKeys lists the user-managed keys of all service accounts of a project
+func ListProjectKeys(ctx context.Context, s *iam.Service, project string) ([]KeyInfo, error) {
+	accounts, err := ListServiceAccounts(ctx, s, project)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list service accounts: %w", err)
+	}
+	var keys []KeyInfo
+	for _, a := range accounts {
+		k, err := ListKeys(ctx, s, project, a)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list keys of %s: %w", a, err)
+		}
+		keys = append(keys, k...)
+	}
+
+	return keys, nil
+}
+
+// StaleKeys returns the keys which are older than maxAge
+func StaleKeys(keys []KeyInfo, maxAge time.Duration, now time.Time) []KeyInfo {
+	var stale []KeyInfo
+	for _, k := range keys {
+		if k.Age(now) > maxAge {
+			stale = append(stale, k)
+		}
+	}
+
+	return stale
+}
+
+// CreateKey creates a key of a service account. It returns the key and
+// the content of its JSON key file.
+func CreateKey(ctx context.Context, s *iam.Service, project string, account string) (KeyInfo, []byte, error) {
+	k, err := s.Projects.ServiceAccounts.Keys.Create(ServiceAccountName(project, account), &iam.CreateServiceAccountKeyRequest{}).Context(ctx).Do()
+	if err != nil {
+		return KeyInfo{}, nil, err
+	}
+	info, err := keyInfo(project, k)
+	if err != nil {
+		return KeyInfo{}, nil, err
+	}
+	data, err := base64.StdEncoding.DecodeString(k.PrivateKeyData)
+	if err != nil {
+		return KeyInfo{}, nil, fmt.Errorf("failed to decode private key data: %w", err)
+	}
+
+	return info, data, nil
+}
+
+// DisableKey disables a key of a service account
+func DisableKey(ctx context.Context, s *iam.Service, k KeyInfo) error {
+	_, err := s.Projects.ServiceAccounts.Keys.Disable(keyName(k), &iam.DisableServiceAccountKeyRequest{}).Context(ctx).Do()
+	return err
+}
+
+// DeleteKey deletes a key of a service account
+func DeleteKey(ctx context.Context, s *iam.Service, k KeyInfo) error {
+	_, err := s.Projects.ServiceAccounts.Keys.Delete(keyName(k)).Context(ctx).Do()
+	return err
+}
+
+// keyName returns the resource name of a key
+func keyName(k KeyInfo) string {
+	return fmt.Sprintf("projects/-/serviceAccounts/%s/keys/%s", k.ServiceAccount, k.ID)
+}
+
+// keyInfo converts a service account key
+func keyInfo(project string, k *iam.ServiceAccountKey) (KeyInfo, error) {
+	// The name is projects/PROJECT/serviceAccounts/EMAIL/keys/ID
+	parts := strings.Split(k.Name, "/")
+	if len(parts) != 6 {
+		return KeyInfo{}, fmt.Errorf("invalid key name: %s", k.Name)
+	}
+	info := KeyInfo{
+		Project:        project,
+		ServiceAccount: parts[3],
+		ID:             parts[5],
+		Algorithm:      k.KeyAlgorithm,
+		Origin:         k.KeyOrigin,
+		Disabled:       k.Disabled,
+	}
+	created, err := time.Parse(time.RFC3339, k.ValidAfterTime)
+	if err != nil {
+		return KeyInfo{}, fmt.Errorf("invalid creation time of key %s: %w", info.ID, err)
+	}
+	info.Created = created
+	if k.ValidBeforeTime != "" && k.ValidBeforeTime != neverExpires {
+		if info.Expires, err = time.Parse(time.RFC3339, k.ValidBeforeTime); err != nil {
+			return KeyInfo{}, fmt.Errorf("invalid expiry time of key %s: %w", info.ID, err)
+		}
+	}
+
+	return info, nil
+}
+
+// keyRows formats the columns of the keys. A key older than maxAge is
+// flagged as stale.
+func keyRows(keys []KeyInfo, maxAge time.Duration, now time.Time) [][]string {
+	rows := make([][]string, 0, len(keys))
+	for _, k := range keys {
+		status := "ENABLED"
+		if k.Disabled {
+			status = "DISABLED"
+		}
+		expires := "never"
+		if !k.Expires.IsZero() {
+			expires = k.Expires.UTC().Format(time.RFC3339)
+		}
+		stale := "no"
+		if k.Age(now) > maxAge {
+			stale = "yes"
+		}
+		rows = append(rows, []string{k.Project, k.ServiceAccount, k.ID, k.Algorithm, status, k.Created.UTC().Format(time.RFC3339), util.FormatAge(k.Age(now)), expires, stale})
+	}
+
+	return rows
+}
+
+// The header of the key rows
+var keyHeader = []string{"PROJECT", "SERVICE ACCOUNT", "KEY ID", "ALGORITHM", "STATUS", "CREATED", "AGE", "EXPIRES", "STALE"}
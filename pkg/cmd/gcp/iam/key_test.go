@@ -0,0 +1,189 @@
+/*
+Copyright © 2022 tchiunam
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package iam
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	iam "google.golang.org/api/iam/v1"
+	"google.golang.org/api/option"
+)
+
+const (
+	deployer = "deployer@proj1.iam.gserviceaccount.com"
+	runner   = "runner@proj1.iam.gserviceaccount.com"
+)
+
+// testServer is a fake IAM server which records the key changes
+type testServer struct {
+	mu    sync.Mutex
+	keys  map[string][]*iam.ServiceAccountKey
+	calls []string
+}
+
+// newTestService creates an IAM client of a test server with two
+// service accounts
+func newTestService(t *testing.T) (*iam.Service, *testServer) {
+	ts := &testServer{keys: map[string][]*iam.ServiceAccountKey{
+		deployer: {
+			testKey(deployer, "new", "2022-12-01T00:00:00Z", false),
+			testKey(deployer, "old", "2022-01-01T00:00:00Z", false),
+			testKey(deployer, "off", "2021-06-01T00:00:00Z", true),
+		},
+		runner: nil,
+	}}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ts.mu.Lock()
+		defer ts.mu.Unlock()
+		path := strings.TrimPrefix(r.URL.Path, "/v1/")
+		switch {
+		case path == "projects/proj1/serviceAccounts":
+			json.NewEncoder(w).Encode(iam.ListServiceAccountsResponse{Accounts: []*iam.ServiceAccount{{Email: runner}, {Email: deployer}}})
+		case strings.HasSuffix(path, "/keys") && r.Method == http.MethodGet:
+			assert.Equal(t, "USER_MANAGED", r.URL.Query().Get("keyTypes"))
+			json.NewEncoder(w).Encode(iam.ListServiceAccountKeysResponse{Keys: ts.keys[strings.Split(path, "/")[3]]})
+		case strings.HasSuffix(path, "/keys") && r.Method == http.MethodPost:
+			k := testKey(strings.Split(path, "/")[3], "created", "2023-01-01T00:00:00Z", false)
+			k.PrivateKeyData = base64.StdEncoding.EncodeToString([]byte(`{"type":"service_account"}`))
+			ts.calls = append(ts.calls, "create")
+			json.NewEncoder(w).Encode(k)
+		case strings.HasSuffix(path, ":disable"):
+			ts.calls = append(ts.calls, "disable "+strings.TrimSuffix(path[strings.LastIndex(path, "/")+1:], ":disable"))
+			w.Write([]byte("{}"))
+		case r.Method == http.MethodDelete:
+			ts.calls = append(ts.calls, "delete "+path[strings.LastIndex(path, "/")+1:])
+			w.Write([]byte("{}"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+	s, err := iam.NewService(context.TODO(), option.WithEndpoint(server.URL), option.WithoutAuthentication())
+	assert.NoError(t, err)
+
+	return s, ts
+}
+
+// testKey creates a user-managed key which never expires
+func testKey(account string, id string, created string, disabled bool) *iam.ServiceAccountKey {
+	return &iam.ServiceAccountKey{
+		Name:            "projects/proj1/serviceAccounts/" + account + "/keys/" + id,
+		KeyAlgorithm:    "KEY_ALG_RSA_2048",
+		KeyOrigin:       "GOOGLE_PROVIDED",
+		KeyType:         "USER_MANAGED",
+		ValidAfterTime:  created,
+		ValidBeforeTime: neverExpires,
+		Disabled:        disabled,
+	}
+}
+
+// TestServiceAccountName tests the ServiceAccountName function
+func TestServiceAccountName(t *testing.T) {
+	cases := map[string]struct {
+		account string
+		want    string
+	}{
+		"account id": {
+			account: "deployer",
+			want:    "projects/-/serviceAccounts/" + deployer,
+		},
+		"email": {
+			account: "ci@proj2.iam.gserviceaccount.com",
+			want:    "projects/-/serviceAccounts/ci@proj2.iam.gserviceaccount.com",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.want, ServiceAccountName("proj1", tc.account))
+		})
+	}
+}
+
+// TestListProjectKeys tests the ListProjectKeys function
+func TestListProjectKeys(t *testing.T) {
+	s, _ := newTestService(t)
+	keys, err := ListProjectKeys(context.TODO(), s, "proj1")
+	if !assert.NoError(t, err) {
+		return
+	}
+	if !assert.Len(t, keys, 3) {
+		return
+	}
+	assert.Equal(t, []string{"off", "old", "new"}, []string{keys[0].ID, keys[1].ID, keys[2].ID})
+	assert.Equal(t, deployer, keys[0].ServiceAccount)
+	assert.Equal(t, "proj1", keys[0].Project)
+	assert.True(t, keys[0].Disabled)
+	assert.True(t, keys[0].Expires.IsZero())
+	assert.Equal(t, time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC), keys[0].Created)
+}
+
+// TestStaleKeys tests the StaleKeys and keyRows functions
+func TestStaleKeys(t *testing.T) {
+	now := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	keys := []KeyInfo{
+		{Project: "proj1", ServiceAccount: deployer, ID: "old", Algorithm: "KEY_ALG_RSA_2048", Created: now.Add(-100 * 24 * time.Hour)},
+		{Project: "proj1", ServiceAccount: deployer, ID: "new", Algorithm: "KEY_ALG_RSA_2048", Created: now.Add(-30 * 24 * time.Hour), Expires: now.Add(24 * time.Hour), Disabled: true},
+	}
+	cases := map[string]struct {
+		maxAge time.Duration
+		stale  []string
+	}{
+		"90 days": {
+			maxAge: 90 * 24 * time.Hour,
+			stale:  []string{"old"},
+		},
+		"7 days": {
+			maxAge: 7 * 24 * time.Hour,
+			stale:  []string{"old", "new"},
+		},
+		"1 year": {
+			maxAge: 365 * 24 * time.Hour,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var ids []string
+			for _, k := range StaleKeys(keys, tc.maxAge, now) {
+				ids = append(ids, k.ID)
+			}
+			assert.Equal(t, tc.stale, ids)
+
+			rows := keyRows(keys, tc.maxAge, now)
+			assert.Len(t, rows[0], len(keyHeader))
+			assert.Equal(t, []string{"proj1", deployer, "old", "KEY_ALG_RSA_2048", "ENABLED", "2022-09-23T00:00:00Z", "100d0h", "never"}, rows[0][:8])
+			assert.Equal(t, "DISABLED", rows[1][4])
+			assert.Equal(t, "2023-01-02T00:00:00Z", rows[1][7])
+		})
+	}
+}
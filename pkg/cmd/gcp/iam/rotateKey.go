@@ -0,0 +1,260 @@
+/*
+Copyright © 2022 tchiunam
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package iam
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	cmdcryptography "github.com/tchiunam/axolgo-cli/pkg/cmd/cryptography"
+	"github.com/tchiunam/axolgo-cli/pkg/util"
+	iam "google.golang.org/api/iam/v1"
+	"k8s.io/klog/v2"
+)
+
+// The actions on the old keys after rotation
+const (
+	retireDisable = "disable"
+	retireDelete  = "delete"
+	retireNone    = "none"
+)
+
+var retireActions = []string{retireDisable, retireDelete, retireNone}
+
+var (
+	rotateKeyLong = `Rotate the user-managed key of a service account. A new key is
+created and its JSON key file is written to --out, which must not
+exist. The key file can be encrypted with the axolgo key file given
+with --encrypt-with, in the same format as "axolgo cryptography
+encryptFile", and be decrypted with "axolgo cryptography decryptFile
+--key-file <axolgo key file> --file <out>".
+
+The old keys are all the other user-managed keys of the service
+account, or the keys given with --old-key. After confirmation, they
+are disabled, or deleted with --retire delete. A disabled key can be
+enabled again if an application still uses it.
+`
+	rotateKeyExample = `  # Rotate the key of a service account and disable the old keys
+  axolgo gcp iam rotateKey deployer --project proj1 --out deployer.json
+
+  # Rotate a key into an encrypted key file and delete the old key
+  axolgo gcp iam rotateKey deployer@proj1.iam.gserviceaccount.com --out deployer.json.enc --encrypt-with axolgo.key --old-key 0123abcd --retire delete
+`
+)
+
+// RotateKeyOptions defines flags and other configuration parameters for the `rotateKey` command
+type RotateKeyOptions struct {
+	Project     string
+	Out         string
+	EncryptWith string
+	OldKeys     []string
+	Retire      string
+	Yes         bool
+}
+
+// NewCmdRotateKey creates the `rotateKey` command
+func NewCmdRotateKey(ctx *context.Context) *cobra.Command {
+	o := RotateKeyOptions{}
+
+	cmd := &cobra.Command{
+		Use:                   "rotateKey SERVICE_ACCOUNT --out FILE [-p] [--encrypt-with] [--old-key KEY_ID]... [--retire] [-y]",
+		DisableFlagsInUseLine: true,
+		Short:                 "Create a new key of a service account and retire the old keys.",
+		Long:                  rotateKeyLong,
+		Example:               rotateKeyExample,
+		Args:                  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := o.complete(ctx, cmd, args); err != nil {
+				panic(err)
+			}
+		},
+	}
+
+	cmd.Flags().StringVarP(&o.Project, "project", "p", "", "Project ID. Default is the project in axolgo configuration.")
+	cmd.Flags().StringVar(&o.Out, "out", "", "File to write the new JSON key file to.")
+	cmd.Flags().StringVar(&o.EncryptWith, "encrypt-with", "", "Key file to encrypt the new JSON key file.")
+	cmd.Flags().StringArrayVar(&o.OldKeys, "old-key", nil, "ID of an old key to retire. Default is all the other user-managed keys.")
+	cmd.Flags().StringVar(&o.Retire, "retire", retireDisable, fmt.Sprintf("Action on the old keys. One of %v.", retireActions))
+	cmd.Flags().BoolVarP(&o.Yes, "yes", "y", false, "Retire the old keys without confirmation.")
+	cmd.MarkFlagRequired("out")
+
+	return cmd
+}
+
+// Complete takes the command arguments and execute.
+func (o *RotateKeyOptions) complete(ctx *context.Context, _ *cobra.Command, args []string) error {
+	switch o.Retire {
+	case retireDisable, retireDelete, retireNone:
+	default:
+		return fmt.Errorf("invalid retire action: %s", o.Retire)
+	}
+	if _, err := os.Stat(o.Out); err == nil {
+		return fmt.Errorf("file %s already exists", o.Out)
+	}
+	var passphrase string
+	if o.EncryptWith != "" {
+		content, err := os.ReadFile(o.EncryptWith)
+		if err != nil {
+			return fmt.Errorf("failed to read key file: %w", err)
+		}
+		passphrase = string(content)
+	}
+	project, err := util.GCPProject(o.Project)
+	if err != nil {
+		return err
+	}
+
+	s, err := NewService(context.TODO())
+	if err != nil {
+		return err
+	}
+	keys, err := ListKeys(context.TODO(), s, project, args[0])
+	if err != nil {
+		return fmt.Errorf("failed to list keys of %s: %w", args[0], err)
+	}
+	old, err := OldKeys(keys, o.OldKeys, o.Retire)
+	if err != nil {
+		return err
+	}
+
+	key, err := RotateKey(context.TODO(), s, project, args[0], o.Out, passphrase)
+	if err != nil {
+		return err
+	}
+	klog.Infof("Created key %s of %s and wrote it to %s", key.ID, key.ServiceAccount, o.Out)
+
+	if o.Retire == retireNone || len(old) == 0 {
+		return nil
+	}
+	if !o.Yes {
+		ids := make([]string, len(old))
+		for i, k := range old {
+			ids[i] = "  " + k.ID
+		}
+		fmt.Println(strings.Join(ids, "\n"))
+		ok, err := util.Confirm(os.Stdin, os.Stdout, fmt.Sprintf("Retire %d old keys with action %s?", len(old), o.Retire))
+		if err != nil {
+			return err
+		}
+		if !ok {
+			klog.Info("The old keys are kept")
+			return nil
+		}
+	}
+	for _, k := range old {
+		if o.Retire == retireDelete {
+			err = DeleteKey(context.TODO(), s, k)
+		} else {
+			err = DisableKey(context.TODO(), s, k)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to %s key %s: %w", o.Retire, k.ID, err)
+		}
+		klog.Infof("Retired key %s with action %s", k.ID, o.Retire)
+	}
+
+	return nil
+}
+
+// OldKeys selects the keys to retire. The keys are the given IDs, or
+// all the keys if none is given. The disabled keys are skipped when
+// the action is to disable.
+func OldKeys(keys []KeyInfo, ids []string, action string) ([]KeyInfo, error) {
+	selected := keys
+	if len(ids) > 0 {
+		selected = nil
+		for _, id := range ids {
+			found := false
+			for _, k := range keys {
+				if k.ID == id {
+					selected = append(selected, k)
+					found = true
+					break
+				}
+			}
+			if !found {
+				return nil, fmt.Errorf("key %s is not a user-managed key of the service account", id)
+			}
+		}
+	}
+
+	var old []KeyInfo
+	for _, k := range selected {
+		if action == retireDisable && k.Disabled {
+			continue
+		}
+		old = append(old, k)
+	}
+
+	return old, nil
+}
+
+// RotateKey creates a key of a service account and writes its JSON key
+// file, encrypted if passphrase is not empty. The key is deleted if it
+// cannot be written so that no unknown key is left.
+func RotateKey(ctx context.Context, s *iam.Service, project string, account string, path string, passphrase string) (KeyInfo, error) {
+	key, data, err := CreateKey(ctx, s, project, account)
+	if err != nil {
+		return KeyInfo{}, fmt.Errorf("failed to create key of %s: %w", account, err)
+	}
+	if err := writeKeyFile(path, data, passphrase); err != nil {
+		if derr := DeleteKey(ctx, s, key); derr != nil {
+			klog.Errorf("Failed to delete the new key %s: %v", key.ID, derr)
+		}
+		return KeyInfo{}, err
+	}
+
+	return key, nil
+}
+
+// writeKeyFile writes a new JSON key file which only the user can read.
+// It is encrypted as a stream with the default KDF if passphrase is not
+// empty.
+func writeKeyFile(path string, data []byte, passphrase string) error {
+	if passphrase != "" {
+		kdf, err := cmdcryptography.DefaultKDF(cmdcryptography.KDFArgon2id)
+		if err != nil {
+			return err
+		}
+		var buf bytes.Buffer
+		config := cmdcryptography.StreamConfig{ChunkSize: cmdcryptography.DefaultChunkSize, KDF: kdf}
+		if err := cmdcryptography.EncryptStream(&buf, bytes.NewReader(data), []byte(passphrase), config); err != nil {
+			return fmt.Errorf("failed to encrypt key file: %w", err)
+		}
+		data = buf.Bytes()
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to create key file: %w", err)
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write key file: %w", err)
+	}
+
+	return f.Close()
+}
@@ -0,0 +1,188 @@
+/*
+Copyright © 2022 tchiunam
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package iam
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	cmdcryptography "github.com/tchiunam/axolgo-cli/pkg/cmd/cryptography"
+)
+
+// TestNewCmdRotateKey tests the NewCmdRotateKey function
+// to make sure it returns a valid command.
+func TestNewCmdRotateKey(t *testing.T) {
+	cases := map[string]struct {
+		use      string
+		short    string
+		hasFlags bool
+	}{
+		"valid command": {
+			use:      "rotateKey SERVICE_ACCOUNT --out FILE [-p] [--encrypt-with] [--old-key KEY_ID]... [--retire] [-y]",
+			short:    "Create a new key of a service account and retire the old keys.",
+			hasFlags: true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cmd := NewCmdRotateKey(nil)
+			assert.Equal(t, tc.use, cmd.Use)
+			assert.Equal(t, tc.short, cmd.Short)
+			assert.Equal(t, tc.hasFlags, cmd.Flags().HasFlags())
+		})
+	}
+}
+
+// TestNewCmdRotateKeyInvalid calls the NewCmdRotateKey function
+// with invalid input and makes sure it returns an error.
+func TestNewCmdRotateKeyInvalid(t *testing.T) {
+	cases := map[string]struct {
+		args []string
+	}{
+		"invalid retire action": {
+			args: []string{"deployer", "--project", "proj1", "--out", "key.json", "--retire", "revoke"},
+		},
+		"missing key file": {
+			args: []string{"deployer", "--project", "proj1", "--out", "key.json", "--encrypt-with", "/nonexistent/axolgo.key"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			oldArgs := os.Args
+			defer func() { os.Args = oldArgs }()
+			os.Args = append([]string{"cmd"}, tc.args...)
+
+			cmd := NewCmdRotateKey(nil)
+			assert.Panics(t, func() { cmd.Execute() })
+		})
+	}
+}
+
+// TestOldKeys tests the OldKeys function
+func TestOldKeys(t *testing.T) {
+	keys := []KeyInfo{{ID: "a"}, {ID: "b", Disabled: true}, {ID: "c"}}
+	cases := map[string]struct {
+		ids    []string
+		action string
+		old    []string
+		valid  bool
+	}{
+		"disable all": {
+			action: retireDisable,
+			old:    []string{"a", "c"},
+			valid:  true,
+		},
+		"delete all": {
+			action: retireDelete,
+			old:    []string{"a", "b", "c"},
+			valid:  true,
+		},
+		"delete given": {
+			ids:    []string{"b"},
+			action: retireDelete,
+			old:    []string{"b"},
+			valid:  true,
+		},
+		"unknown key": {
+			ids:    []string{"d"},
+			action: retireDisable,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			old, err := OldKeys(keys, tc.ids, tc.action)
+			if !tc.valid {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			var ids []string
+			for _, k := range old {
+				ids = append(ids, k.ID)
+			}
+			assert.Equal(t, tc.old, ids)
+		})
+	}
+}
+
+// TestRotateKey tests the RotateKey function
+func TestRotateKey(t *testing.T) {
+	cases := map[string]struct {
+		passphrase string
+		exists     bool
+		calls      []string
+	}{
+		"plain": {
+			calls: []string{"create"},
+		},
+		"encrypted": {
+			passphrase: "axolgo-test-passphrase",
+			calls:      []string{"create"},
+		},
+		"file exists": {
+			exists: true,
+			calls:  []string{"create", "delete created"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			s, ts := newTestService(t)
+			path := filepath.Join(t.TempDir(), "key.json")
+			if tc.exists {
+				assert.NoError(t, os.WriteFile(path, []byte("{}"), 0600))
+			}
+			key, err := RotateKey(context.TODO(), s, "proj1", "deployer", path, tc.passphrase)
+			assert.Equal(t, tc.calls, ts.calls)
+			if tc.exists {
+				assert.Error(t, err)
+				return
+			}
+			if !assert.NoError(t, err) {
+				return
+			}
+			assert.Equal(t, "created", key.ID)
+			assert.Equal(t, deployer, key.ServiceAccount)
+
+			info, err := os.Stat(path)
+			assert.NoError(t, err)
+			assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+			data, err := os.ReadFile(path)
+			assert.NoError(t, err)
+			if tc.passphrase != "" {
+				assert.True(t, cmdcryptography.IsStream(data))
+				var buf bytes.Buffer
+				assert.NoError(t, cmdcryptography.DecryptStream(&buf, bytes.NewReader(data), []byte(tc.passphrase)))
+				data = buf.Bytes()
+			}
+			assert.Equal(t, `{"type":"service_account"}`, string(data))
+		})
+	}
+}
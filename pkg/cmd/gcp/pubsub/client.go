@@ -0,0 +1,185 @@
+/*
+Copyright © 2022 tchiunam
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package pubsub
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/tchiunam/axolgo-cli/pkg/util"
+	"google.golang.org/api/option"
+	pubsub "google.golang.org/api/pubsub/v1"
+)
+
+// NewService creates a Pub/Sub client. If PUBSUB_EMULATOR_HOST is set,
+// the client connects to the emulator without authentication.
+func NewService(ctx context.Context) (*pubsub.Service, error) {
+	var opts []option.ClientOption
+	if host := os.Getenv("PUBSUB_EMULATOR_HOST"); host != "" {
+		opts = []option.ClientOption{option.WithEndpoint("http://" + host + "/"), option.WithoutAuthentication()}
+	} else {
+		var err error
+		if opts, err = util.GCPClientOptions(ctx); err != nil {
+			return nil, err
+		}
+	}
+	s, err := pubsub.NewService(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Pub/Sub client: %w", err)
+	}
+
+	return s, nil
+}
+
+// TopicName returns the full name of a topic, which can be given as a
+// full name or a topic ID in the project
+func TopicName(project string, topic string) string {
+	if strings.HasPrefix(topic, "projects/") {
+		return topic
+	}
+
+	return fmt.Sprintf("projects/%s/topics/%s", project, topic)
+}
+
+// SubscriptionName returns the full name of a subscription, which can
+// be given as a full name or a subscription ID in the project
+func SubscriptionName(project string, subscription string) string {
+	if strings.HasPrefix(subscription, "projects/") {
+		return subscription
+	}
+
+	return fmt.Sprintf("projects/%s/subscriptions/%s", project, subscription)
+}
+
+// ListTopics lists the topics of a project
+func ListTopics(ctx context.Context, s *pubsub.Service, project string) ([]*pubsub.Topic, error) {
+	var topics []*pubsub.Topic
+	err := s.Projects.Topics.List("projects/"+project).Pages(ctx, func(resp *pubsub.ListTopicsResponse) error {
+		topics = append(topics, resp.Topics...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(topics, func(i, j int) bool {
+		return topics[i].Name < topics[j].Name
+	})
+
+	return topics, nil
+}
+
+// ListSubscriptions lists the subscriptions of a project. If topic is
+// not empty, only the subscriptions of the topic are listed.
+func ListSubscriptions(ctx context.Context, s *pubsub.Service, project string, topic string) ([]*pubsub.Subscription, error) {
+	var subscriptions []*pubsub.Subscription
+	err := s.Projects.Subscriptions.List("projects/"+project).Pages(ctx, func(resp *pubsub.ListSubscriptionsResponse) error {
+		for _, sub := range resp.Subscriptions {
+			if topic == "" || sub.Topic == topic {
+				subscriptions = append(subscriptions, sub)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(subscriptions, func(i, j int) bool {
+		return subscriptions[i].Name < subscriptions[j].Name
+	})
+
+	return subscriptions, nil
+}
+
+// lastSegment returns the last segment of a resource name
+func lastSegment(name string) string {
+	return name[strings.LastIndex(name, "/")+1:]
+}
+
+// orDash returns - if a column is empty
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+
+	return s
+}
+
+// formatLabels formats labels in the format of key=value, sorted by key
+func formatLabels(labels map[string]string) string {
+	if len(labels) == 0 {
+		return "-"
+	}
+	pairs := make([]string, 0, len(labels))
+	for k, v := range labels {
+		pairs = append(pairs, k+"="+v)
+	}
+	sort.Strings(pairs)
+
+	return strings.Join(pairs, ",")
+}
+
+// topicRows formats the columns of the topics
+func topicRows(topics []*pubsub.Topic) [][]string {
+	rows := make([][]string, 0, len(topics))
+	for _, t := range topics {
+		rows = append(rows, []string{lastSegment(t.Name), orDash(t.MessageRetentionDuration), orDash(t.KmsKeyName), formatLabels(t.Labels)})
+	}
+
+	return rows
+}
+
+// The header of the topic rows
+var topicHeader = []string{"TOPIC", "RETENTION", "KMS KEY", "LABELS"}
+
+// subscriptionRows formats the columns of the subscriptions
+func subscriptionRows(subscriptions []*pubsub.Subscription) [][]string {
+	rows := make([][]string, 0, len(subscriptions))
+	for _, sub := range subscriptions {
+		delivery := "pull"
+		if sub.PushConfig != nil && sub.PushConfig.PushEndpoint != "" {
+			delivery = "push:" + sub.PushConfig.PushEndpoint
+		}
+		deadLetter := "-"
+		if sub.DeadLetterPolicy != nil && sub.DeadLetterPolicy.DeadLetterTopic != "" {
+			deadLetter = lastSegment(sub.DeadLetterPolicy.DeadLetterTopic)
+		}
+		rows = append(rows, []string{
+			lastSegment(sub.Name),
+			lastSegment(sub.Topic),
+			delivery,
+			strconv.FormatInt(sub.AckDeadlineSeconds, 10),
+			strconv.FormatBool(sub.EnableMessageOrdering),
+			orDash(sub.Filter),
+			deadLetter,
+		})
+	}
+
+	return rows
+}
+
+// The header of the subscription rows
+var subscriptionHeader = []string{"SUBSCRIPTION", "TOPIC", "DELIVERY", "ACK DEADLINE", "ORDERING", "FILTER", "DEAD LETTER TOPIC"}
@@ -0,0 +1,177 @@
+/*
+Copyright © 2022 tchiunam
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package pubsub
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/api/option"
+	pubsub "google.golang.org/api/pubsub/v1"
+)
+
+// newTestService creates a Pub/Sub client of a test server
+func newTestService(t *testing.T, handler http.HandlerFunc) *pubsub.Service {
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	s, err := pubsub.NewService(context.TODO(), option.WithEndpoint(server.URL), option.WithoutAuthentication())
+	assert.NoError(t, err)
+
+	return s
+}
+
+// TestNewServiceEmulator tests the NewService function with the emulator
+func TestNewServiceEmulator(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/projects/proj1/topics", r.URL.Path)
+		assert.Empty(t, r.Header.Get("Authorization"))
+		json.NewEncoder(w).Encode(pubsub.ListTopicsResponse{Topics: []*pubsub.Topic{{Name: "projects/proj1/topics/orders"}}})
+	}))
+	defer server.Close()
+	t.Setenv("PUBSUB_EMULATOR_HOST", server.Listener.Addr().String())
+
+	s, err := NewService(context.TODO())
+	assert.NoError(t, err)
+	topics, err := ListTopics(context.TODO(), s, "proj1")
+	assert.NoError(t, err)
+	assert.Len(t, topics, 1)
+}
+
+// TestResourceNames tests the TopicName and SubscriptionName functions
+func TestResourceNames(t *testing.T) {
+	cases := map[string]struct {
+		name         string
+		topic        string
+		subscription string
+	}{
+		"ID": {
+			name:         "orders",
+			topic:        "projects/proj1/topics/orders",
+			subscription: "projects/proj1/subscriptions/orders",
+		},
+		"full name": {
+			name:         "projects/proj2/topics/orders",
+			topic:        "projects/proj2/topics/orders",
+			subscription: "projects/proj2/topics/orders",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.topic, TopicName("proj1", tc.name))
+			assert.Equal(t, tc.subscription, SubscriptionName("proj1", tc.name))
+		})
+	}
+}
+
+// TestListTopics tests the ListTopics function and the topic rows
+func TestListTopics(t *testing.T) {
+	s := newTestService(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/projects/proj1/topics", r.URL.Path)
+		if r.URL.Query().Get("pageToken") == "" {
+			json.NewEncoder(w).Encode(pubsub.ListTopicsResponse{
+				Topics:        []*pubsub.Topic{{Name: "projects/proj1/topics/payments"}},
+				NextPageToken: "page2",
+			})
+			return
+		}
+		json.NewEncoder(w).Encode(pubsub.ListTopicsResponse{
+			Topics: []*pubsub.Topic{{
+				Name:                     "projects/proj1/topics/orders",
+				MessageRetentionDuration: "86400s",
+				Labels:                   map[string]string{"team": "shop", "env": "prod"},
+			}},
+		})
+	})
+
+	topics, err := ListTopics(context.TODO(), s, "proj1")
+	assert.NoError(t, err)
+	rows := topicRows(topics)
+	assert.Equal(t, [][]string{
+		{"orders", "86400s", "-", "env=prod,team=shop"},
+		{"payments", "-", "-", "-"},
+	}, rows)
+	assert.Len(t, rows[0], len(topicHeader))
+}
+
+// TestListSubscriptions tests the ListSubscriptions function and the
+// subscription rows
+func TestListSubscriptions(t *testing.T) {
+	s := newTestService(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/projects/proj1/subscriptions", r.URL.Path)
+		json.NewEncoder(w).Encode(pubsub.ListSubscriptionsResponse{
+			Subscriptions: []*pubsub.Subscription{
+				{
+					Name:               "projects/proj1/subscriptions/orders-push",
+					Topic:              "projects/proj1/topics/orders",
+					AckDeadlineSeconds: 60,
+					PushConfig:         &pubsub.PushConfig{PushEndpoint: "https://example.com/push"},
+					DeadLetterPolicy:   &pubsub.DeadLetterPolicy{DeadLetterTopic: "projects/proj1/topics/orders-dead"},
+				},
+				{
+					Name:                  "projects/proj1/subscriptions/orders-debug",
+					Topic:                 "projects/proj1/topics/orders",
+					AckDeadlineSeconds:    10,
+					EnableMessageOrdering: true,
+					Filter:                `attributes.type = "created"`,
+				},
+				{
+					Name:               "projects/proj1/subscriptions/payments",
+					Topic:              "projects/proj1/topics/payments",
+					AckDeadlineSeconds: 10,
+				},
+			},
+		})
+	})
+
+	cases := map[string]struct {
+		topic string
+		rows  [][]string
+	}{
+		"all": {
+			rows: [][]string{
+				{"orders-debug", "orders", "pull", "10", "true", `attributes.type = "created"`, "-"},
+				{"orders-push", "orders", "push:https://example.com/push", "60", "false", "-", "orders-dead"},
+				{"payments", "payments", "pull", "10", "false", "-", "-"},
+			},
+		},
+		"topic": {
+			topic: "projects/proj1/topics/payments",
+			rows:  [][]string{{"payments", "payments", "pull", "10", "false", "-", "-"}},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			subscriptions, err := ListSubscriptions(context.TODO(), s, "proj1", tc.topic)
+			assert.NoError(t, err)
+			rows := subscriptionRows(subscriptions)
+			assert.Equal(t, tc.rows, rows)
+			assert.Len(t, rows[0], len(subscriptionHeader))
+		})
+	}
+}
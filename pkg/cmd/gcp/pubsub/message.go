@@ -0,0 +1,181 @@
+/*
+Copyright © 2022 tchiunam
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package pubsub
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+	"unicode/utf8"
+
+	pubsub "google.golang.org/api/pubsub/v1"
+	"k8s.io/klog/v2"
+)
+
+// The max. no. of messages in a publish request
+const publishBatchSize = 1000
+
+// The max. no. of messages in a pull request
+const pullBatchSize = 100
+
+// The interval to pull again after no messages are received in follow mode
+var followInterval = time.Second
+
+// Message is a received message which is printed as a JSON line
+type Message struct {
+	ID          string            `json:"id"`
+	PublishTime string            `json:"publishTime"`
+	OrderingKey string            `json:"orderingKey,omitempty"`
+	Attributes  map[string]string `json:"attributes,omitempty"`
+	// Data is the text of the message, or encoded in base64 if it is not
+	// valid UTF-8
+	Data            string `json:"data"`
+	Base64          bool   `json:"base64,omitempty"`
+	DeliveryAttempt int64  `json:"deliveryAttempt,omitempty"`
+}
+
+// messageFrom converts a received message
+func messageFrom(m *pubsub.ReceivedMessage) (Message, error) {
+	msg := Message{DeliveryAttempt: m.DeliveryAttempt}
+	if m.Message == nil {
+		return msg, nil
+	}
+	msg.ID = m.Message.MessageId
+	msg.PublishTime = m.Message.PublishTime
+	msg.OrderingKey = m.Message.OrderingKey
+	msg.Attributes = m.Message.Attributes
+
+	data, err := base64.StdEncoding.DecodeString(m.Message.Data)
+	if err != nil {
+		return msg, fmt.Errorf("invalid data of message %s: %w", msg.ID, err)
+	}
+	if utf8.Valid(data) {
+		msg.Data = string(data)
+	} else {
+		msg.Data = m.Message.Data
+		msg.Base64 = true
+	}
+
+	return msg, nil
+}
+
+// NewMessage creates a message to publish
+func NewMessage(data []byte, attributes map[string]string, orderingKey string) *pubsub.PubsubMessage {
+	return &pubsub.PubsubMessage{
+		Data:        base64.StdEncoding.EncodeToString(data),
+		Attributes:  attributes,
+		OrderingKey: orderingKey,
+	}
+}
+
+// Publish publishes messages to a topic in batches and returns the
+// message IDs
+func Publish(ctx context.Context, s *pubsub.Service, topic string, messages []*pubsub.PubsubMessage) ([]string, error) {
+	var ids []string
+	for start := 0; start < len(messages); start += publishBatchSize {
+		end := start + publishBatchSize
+		if end > len(messages) {
+			end = len(messages)
+		}
+		resp, err := s.Projects.Topics.Publish(topic, &pubsub.PublishRequest{Messages: messages[start:end]}).Context(ctx).Do()
+		if err != nil {
+			return ids, fmt.Errorf("failed to publish to %s: %w", topic, err)
+		}
+		ids = append(ids, resp.MessageIds...)
+	}
+
+	return ids, nil
+}
+
+// PullConfig is the configuration to pull messages
+type PullConfig struct {
+	// Max. no. of messages to pull. 0 is unlimited.
+	Max int
+	// Acknowledge the messages after they are written
+	Ack bool
+	// Keep pulling until the context is done or Max is reached. Otherwise
+	// pull until no messages are received.
+	Follow bool
+}
+
+// Pull pulls messages from a subscription and writes them to w as JSON
+// lines. It returns the no. of messages written.
+func Pull(ctx context.Context, s *pubsub.Service, subscription string, cfg PullConfig, w io.Writer) (int, error) {
+	enc := json.NewEncoder(w)
+	n := 0
+	for cfg.Max == 0 || n < cfg.Max {
+		size := pullBatchSize
+		if cfg.Max > 0 && cfg.Max-n < size {
+			size = cfg.Max - n
+		}
+		resp, err := s.Projects.Subscriptions.Pull(subscription, &pubsub.PullRequest{
+			MaxMessages:       int64(size),
+			ReturnImmediately: !cfg.Follow,
+		}).Context(ctx).Do()
+		if err != nil {
+			if ctx.Err() != nil {
+				return n, nil
+			}
+			return n, fmt.Errorf("failed to pull from %s: %w", subscription, err)
+		}
+
+		if len(resp.ReceivedMessages) == 0 {
+			if !cfg.Follow {
+				return n, nil
+			}
+			select {
+			case <-ctx.Done():
+				return n, nil
+			case <-time.After(followInterval):
+			}
+			continue
+		}
+
+		ackIDs := make([]string, 0, len(resp.ReceivedMessages))
+		for _, m := range resp.ReceivedMessages {
+			msg, err := messageFrom(m)
+			if err != nil {
+				return n, err
+			}
+			if err := enc.Encode(msg); err != nil {
+				return n, err
+			}
+			ackIDs = append(ackIDs, m.AckId)
+			n++
+		}
+		if cfg.Ack {
+			// The messages are acknowledged even if the context is done
+			// because they are written already
+			_, err := s.Projects.Subscriptions.Acknowledge(subscription, &pubsub.AcknowledgeRequest{AckIds: ackIDs}).Context(context.Background()).Do()
+			if err != nil {
+				return n, fmt.Errorf("failed to acknowledge messages: %w", err)
+			}
+			klog.V(3).InfoS("Acknowledged messages", "subscription", subscription, "count", len(ackIDs))
+		}
+	}
+
+	return n, nil
+}
@@ -0,0 +1,255 @@
+/*
+Copyright © 2022 tchiunam
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package pubsub
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	pubsub "google.golang.org/api/pubsub/v1"
+)
+
+// TestMessageFrom tests the messageFrom function
+func TestMessageFrom(t *testing.T) {
+	cases := map[string]struct {
+		received *pubsub.ReceivedMessage
+		message  Message
+		valid    bool
+	}{
+		"text": {
+			received: &pubsub.ReceivedMessage{
+				DeliveryAttempt: 2,
+				Message: &pubsub.PubsubMessage{
+					MessageId:   "1",
+					PublishTime: "2023-01-01T00:00:00Z",
+					Attributes:  map[string]string{"type": "created"},
+					Data:        base64.StdEncoding.EncodeToString([]byte(`{"id": 1}`)),
+				},
+			},
+			message: Message{ID: "1", PublishTime: "2023-01-01T00:00:00Z", Attributes: map[string]string{"type": "created"}, Data: `{"id": 1}`, DeliveryAttempt: 2},
+			valid:   true,
+		},
+		"binary": {
+			received: &pubsub.ReceivedMessage{
+				Message: &pubsub.PubsubMessage{MessageId: "2", Data: base64.StdEncoding.EncodeToString([]byte{0xff, 0xfe})},
+			},
+			message: Message{ID: "2", Data: "//4=", Base64: true},
+			valid:   true,
+		},
+		"invalid data": {
+			received: &pubsub.ReceivedMessage{
+				Message: &pubsub.PubsubMessage{MessageId: "3", Data: "not base64"},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			message, err := messageFrom(tc.received)
+			if tc.valid {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.message, message)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
+
+// TestPublish tests the Publish function in batches
+func TestPublish(t *testing.T) {
+	var batches []int
+	s := newTestService(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/projects/proj1/topics/orders:publish", r.URL.Path)
+		var req pubsub.PublishRequest
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		batches = append(batches, len(req.Messages))
+		ids := make([]string, len(req.Messages))
+		for i := range ids {
+			ids[i] = fmt.Sprint(len(batches), "-", i)
+		}
+		json.NewEncoder(w).Encode(pubsub.PublishResponse{MessageIds: ids})
+	})
+
+	messages := make([]*pubsub.PubsubMessage, 1500)
+	for i := range messages {
+		messages[i] = NewMessage([]byte("hello"), nil, "")
+	}
+	ids, err := Publish(context.TODO(), s, "projects/proj1/topics/orders", messages)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{1000, 500}, batches)
+	assert.Len(t, ids, 1500)
+}
+
+// fakeSubscription is a subscription of a test server. A message which
+// is pulled is not delivered again.
+type fakeSubscription struct {
+	mu      sync.Mutex
+	pending []string
+	acked   []string
+	pulls   int
+}
+
+// handle handles the pull and acknowledge requests
+func (f *fakeSubscription) handle(t *testing.T) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		switch {
+		case strings.HasSuffix(r.URL.Path, ":pull"):
+			var req pubsub.PullRequest
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+			f.pulls++
+			n := int(req.MaxMessages)
+			if n > len(f.pending) {
+				n = len(f.pending)
+			}
+			resp := pubsub.PullResponse{}
+			for _, data := range f.pending[:n] {
+				resp.ReceivedMessages = append(resp.ReceivedMessages, &pubsub.ReceivedMessage{
+					AckId:   "ack-" + data,
+					Message: &pubsub.PubsubMessage{MessageId: data, Data: base64.StdEncoding.EncodeToString([]byte(data))},
+				})
+			}
+			f.pending = f.pending[n:]
+			json.NewEncoder(w).Encode(resp)
+		case strings.HasSuffix(r.URL.Path, ":acknowledge"):
+			var req pubsub.AcknowledgeRequest
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+			f.acked = append(f.acked, req.AckIds...)
+			w.Write([]byte("{}"))
+		default:
+			t.Errorf("unexpected request %s", r.URL.Path)
+		}
+	}
+}
+
+// TestPull tests the Pull function
+func TestPull(t *testing.T) {
+	oldInterval := followInterval
+	defer func() { followInterval = oldInterval }()
+	followInterval = 10 * time.Millisecond
+
+	messages := make([]string, 150)
+	for i := range messages {
+		messages[i] = fmt.Sprint("m", i)
+	}
+	cases := map[string]struct {
+		cfg PullConfig
+		n   int
+	}{
+		"all": {
+			cfg: PullConfig{},
+			n:   150,
+		},
+		"max": {
+			cfg: PullConfig{Max: 120, Ack: true},
+			n:   120,
+		},
+		"ack": {
+			cfg: PullConfig{Ack: true},
+			n:   150,
+		},
+		"follow until max": {
+			cfg: PullConfig{Max: 150, Ack: true, Follow: true},
+			n:   150,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			f := &fakeSubscription{pending: append([]string(nil), messages...)}
+			s := newTestService(t, f.handle(t))
+			var buf bytes.Buffer
+			n, err := Pull(context.TODO(), s, "projects/proj1/subscriptions/orders-debug", tc.cfg, &buf)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.n, n)
+			if tc.cfg.Ack {
+				assert.Len(t, f.acked, n)
+			} else {
+				assert.Empty(t, f.acked)
+			}
+
+			lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+			assert.Len(t, lines, tc.n)
+			var first Message
+			assert.NoError(t, json.Unmarshal([]byte(lines[0]), &first))
+			assert.Equal(t, Message{ID: "m0", Data: "m0"}, first)
+		})
+	}
+
+	t.Run("follow until done", func(t *testing.T) {
+		f := &fakeSubscription{pending: []string{"m0"}}
+		s := newTestService(t, f.handle(t))
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+		var buf bytes.Buffer
+		n, err := Pull(ctx, s, "projects/proj1/subscriptions/orders-debug", PullConfig{Follow: true}, &buf)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, n)
+		assert.Greater(t, f.pulls, 2)
+	})
+}
+
+// TestEmulator publishes and pulls messages with the Pub/Sub emulator.
+// It is skipped if PUBSUB_EMULATOR_HOST and PUBSUB_PROJECT_ID are not set.
+func TestEmulator(t *testing.T) {
+	project := os.Getenv("PUBSUB_PROJECT_ID")
+	if os.Getenv("PUBSUB_EMULATOR_HOST") == "" || project == "" {
+		t.Skip("PUBSUB_EMULATOR_HOST and PUBSUB_PROJECT_ID are not set")
+	}
+	ctx := context.Background()
+	s, err := NewService(ctx)
+	if !assert.NoError(t, err) {
+		return
+	}
+	suffix := fmt.Sprint(time.Now().UnixNano())
+	topic := TopicName(project, "axolgo-test-"+suffix)
+	subscription := SubscriptionName(project, "axolgo-test-"+suffix)
+	_, err = s.Projects.Topics.Create(topic, &pubsub.Topic{}).Context(ctx).Do()
+	assert.NoError(t, err)
+	_, err = s.Projects.Subscriptions.Create(subscription, &pubsub.Subscription{Topic: topic}).Context(ctx).Do()
+	assert.NoError(t, err)
+
+	messages, err := ReadMessages(strings.NewReader("m0\nm1\nm2\n"), map[string]string{"type": "test"}, "")
+	assert.NoError(t, err)
+	ids, err := Publish(ctx, s, topic, messages)
+	assert.NoError(t, err)
+	assert.Len(t, ids, 3)
+
+	var buf bytes.Buffer
+	n, err := Pull(ctx, s, subscription, PullConfig{Max: 3, Ack: true, Follow: true}, &buf)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, n)
+	assert.Contains(t, buf.String(), `"attributes":{"type":"test"}`)
+}
@@ -0,0 +1,164 @@
+/*
+Copyright © 2022 tchiunam
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package pubsub
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/tchiunam/axolgo-cli/pkg/util"
+	pubsub "google.golang.org/api/pubsub/v1"
+	"k8s.io/klog/v2"
+)
+
+// The max. size of a line read from stdin, which is the max. size of
+// a Pub/Sub message
+const maxLineSize = 10 << 20
+
+var (
+	publishLong = `Publish messages to a Pub/Sub topic. The message is given by a flag
+or a file. Otherwise, each line of stdin is published as a message. All
+the messages carry the same attributes and ordering key. The IDs of the
+published messages are printed.
+`
+	publishExample = `  # Publish a message with attributes
+  axolgo gcp pubsub publish --topic orders --message '{"id": 1}' --attribute type=created
+
+  # Publish a file as a message
+  axolgo gcp pubsub publish --topic orders --file order.json
+
+  # Publish each line of a file as a message
+  axolgo gcp pubsub publish --topic orders < orders.jsonl
+`
+)
+
+// PublishOptions defines flags and other configuration parameters for the `publish` command
+type PublishOptions struct {
+	Project     string
+	Topic       string
+	Message     string
+	File        string
+	Attributes  []string
+	OrderingKey string
+}
+
+// NewCmdPublish creates the `publish` command
+func NewCmdPublish(ctx *context.Context) *cobra.Command {
+	o := PublishOptions{}
+
+	cmd := &cobra.Command{
+		Use:                   "publish -t TOPIC [-p] [-m | -f] [-a KEY=VALUE]... [--ordering-key]",
+		DisableFlagsInUseLine: true,
+		Short:                 "Publish messages to a Pub/Sub topic.",
+		Long:                  publishLong,
+		Example:               publishExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := o.complete(ctx, cmd, args); err != nil {
+				panic(err)
+			}
+		},
+	}
+
+	cmd.Flags().StringVarP(&o.Project, "project", "p", "", "Project ID. Default is the project in axolgo configuration.")
+	cmd.Flags().StringVarP(&o.Topic, "topic", "t", "", "Topic ID or full name.")
+	cmd.Flags().StringVarP(&o.Message, "message", "m", "", "Message to publish.")
+	cmd.Flags().StringVarP(&o.File, "file", "f", "", "File to publish as a message.")
+	cmd.Flags().StringArrayVarP(&o.Attributes, "attribute", "a", nil, "Attribute of the messages in the format of KEY=VALUE. It can be given multiple times.")
+	cmd.Flags().StringVar(&o.OrderingKey, "ordering-key", "", "Ordering key of the messages.")
+
+	cmd.MarkFlagRequired("topic")
+
+	return cmd
+}
+
+// Complete takes the command arguments and execute.
+func (o *PublishOptions) complete(ctx *context.Context, _ *cobra.Command, args []string) error {
+	if o.Message != "" && o.File != "" {
+		return errors.New("only one of --message and --file can be given")
+	}
+	attributes, err := util.ParseKeyValues(o.Attributes)
+	if err != nil {
+		return err
+	}
+	project, err := util.GCPProject(o.Project)
+	if err != nil {
+		return err
+	}
+
+	var messages []*pubsub.PubsubMessage
+	switch {
+	case o.Message != "":
+		messages = append(messages, NewMessage([]byte(o.Message), attributes, o.OrderingKey))
+	case o.File != "":
+		data, err := os.ReadFile(o.File)
+		if err != nil {
+			return err
+		}
+		messages = append(messages, NewMessage(data, attributes, o.OrderingKey))
+	default:
+		if messages, err = ReadMessages(os.Stdin, attributes, o.OrderingKey); err != nil {
+			return err
+		}
+	}
+	if len(messages) == 0 {
+		return errors.New("no messages to publish")
+	}
+
+	s, err := NewService(context.TODO())
+	if err != nil {
+		return err
+	}
+	ids, err := Publish(context.TODO(), s, TopicName(project, o.Topic), messages)
+	for _, id := range ids {
+		fmt.Println(id)
+	}
+	if err != nil {
+		return err
+	}
+	klog.V(1).Infof("Published %d messages to %s", len(ids), o.Topic)
+
+	return nil
+}
+
+// ReadMessages reads each non-empty line as a message
+func ReadMessages(r io.Reader, attributes map[string]string, orderingKey string) ([]*pubsub.PubsubMessage, error) {
+	var messages []*pubsub.PubsubMessage
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), maxLineSize)
+	for scanner.Scan() {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		messages = append(messages, NewMessage(scanner.Bytes(), attributes, orderingKey))
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read messages: %w", err)
+	}
+
+	return messages, nil
+}
@@ -0,0 +1,96 @@
+/*
+Copyright © 2022 tchiunam
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package pubsub
+
+import (
+	"encoding/base64"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestNewCmdPublish tests the NewCmdPublish function
+// to make sure it returns a valid command.
+func TestNewCmdPublish(t *testing.T) {
+	cases := map[string]struct {
+		use      string
+		short    string
+		hasFlags bool
+	}{
+		"valid command": {
+			use:      "publish -t TOPIC [-p] [-m | -f] [-a KEY=VALUE]... [--ordering-key]",
+			short:    "Publish messages to a Pub/Sub topic.",
+			hasFlags: true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cmd := NewCmdPublish(nil)
+			assert.Equal(t, tc.use, cmd.Use)
+			assert.Equal(t, tc.short, cmd.Short)
+			assert.Equal(t, tc.hasFlags, cmd.Flags().HasFlags())
+		})
+	}
+}
+
+// TestNewCmdPublishInvalid calls the NewCmdPublish function
+// with invalid input and makes sure it returns an error.
+func TestNewCmdPublishInvalid(t *testing.T) {
+	cases := map[string]struct {
+		args []string
+	}{
+		"message and file": {
+			args: []string{"--topic", "orders", "--message", "hello", "--file", "order.json"},
+		},
+		"invalid attribute": {
+			args: []string{"--topic", "orders", "--message", "hello", "--attribute", "type"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			oldArgs := os.Args
+			defer func() { os.Args = oldArgs }()
+			os.Args = append([]string{"cmd"}, tc.args...)
+
+			cmd := NewCmdPublish(nil)
+			assert.Panics(t, func() { cmd.Execute() })
+		})
+	}
+}
+
+// TestReadMessages tests the ReadMessages function
+func TestReadMessages(t *testing.T) {
+	attributes := map[string]string{"type": "created"}
+	messages, err := ReadMessages(strings.NewReader("{\"id\": 1}\n\n{\"id\": 2}\n"), attributes, "key1")
+	assert.NoError(t, err)
+	if assert.Len(t, messages, 2) {
+		assert.Equal(t, base64.StdEncoding.EncodeToString([]byte(`{"id": 1}`)), messages[0].Data)
+		assert.Equal(t, base64.StdEncoding.EncodeToString([]byte(`{"id": 2}`)), messages[1].Data)
+		assert.Equal(t, attributes, messages[1].Attributes)
+		assert.Equal(t, "key1", messages[1].OrderingKey)
+	}
+}
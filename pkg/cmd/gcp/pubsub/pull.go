@@ -0,0 +1,112 @@
+/*
+Copyright © 2022 tchiunam
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package pubsub
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/signal"
+
+	"github.com/spf13/cobra"
+	"github.com/tchiunam/axolgo-cli/pkg/util"
+	"k8s.io/klog/v2"
+)
+
+var (
+	pullLong = `Pull messages from a Pub/Sub subscription and print them as JSON
+lines. The data of a message is printed as text, or in base64 if it is
+not valid UTF-8. The messages are not acknowledged unless --ack is
+given, so that they are delivered again after the ack deadline. Without
+--follow, messages are pulled until none is received. With --follow,
+messages are streamed until --max is reached or it is interrupted.
+`
+	pullExample = `  # Peek at the messages of a subscription
+  axolgo gcp pubsub pull --subscription orders-debug --max 10
+
+  # Stream and acknowledge messages and select the created orders
+  axolgo gcp pubsub pull -s orders-debug --ack --follow | jq 'select(.attributes.type == "created")'
+`
+)
+
+// PullOptions defines flags and other configuration parameters for the `pull` command
+type PullOptions struct {
+	Project      string
+	Subscription string
+	Ack          bool
+	Max          int
+	Follow       bool
+}
+
+// NewCmdPull creates the `pull` command
+func NewCmdPull(ctx *context.Context) *cobra.Command {
+	o := PullOptions{}
+
+	cmd := &cobra.Command{
+		Use:                   "pull -s SUBSCRIPTION [-p] [--ack] [--max] [--follow]",
+		DisableFlagsInUseLine: true,
+		Short:                 "Pull messages from a Pub/Sub subscription.",
+		Long:                  pullLong,
+		Example:               pullExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := o.complete(ctx, cmd, args); err != nil {
+				panic(err)
+			}
+		},
+	}
+
+	cmd.Flags().StringVarP(&o.Project, "project", "p", "", "Project ID. Default is the project in axolgo configuration.")
+	cmd.Flags().StringVarP(&o.Subscription, "subscription", "s", "", "Subscription ID or full name.")
+	cmd.Flags().BoolVar(&o.Ack, "ack", false, "Acknowledge the messages after they are printed.")
+	cmd.Flags().IntVar(&o.Max, "max", 0, "Max. no. of messages to pull. 0 is unlimited.")
+	cmd.Flags().BoolVar(&o.Follow, "follow", false, "Keep pulling messages until --max is reached or it is interrupted.")
+
+	cmd.MarkFlagRequired("subscription")
+
+	return cmd
+}
+
+// Complete takes the command arguments and execute.
+func (o *PullOptions) complete(ctx *context.Context, _ *cobra.Command, args []string) error {
+	if o.Max < 0 {
+		return errors.New("the max. no. of messages must not be negative")
+	}
+	project, err := util.GCPProject(o.Project)
+	if err != nil {
+		return err
+	}
+
+	s, err := NewService(context.TODO())
+	if err != nil {
+		return err
+	}
+	pullCtx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	n, err := Pull(pullCtx, s, SubscriptionName(project, o.Subscription), PullConfig{Max: o.Max, Ack: o.Ack, Follow: o.Follow}, os.Stdout)
+	if err != nil {
+		return err
+	}
+	klog.V(1).Infof("Pulled %d messages from %s", n, o.Subscription)
+
+	return nil
+}
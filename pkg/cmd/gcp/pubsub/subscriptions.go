@@ -0,0 +1,118 @@
+/*
+Copyright © 2022 tchiunam
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package pubsub
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/tchiunam/axolgo-cli/pkg/util"
+)
+
+var (
+	listSubscriptionsLong = `List the Pub/Sub subscriptions of a project, or of a topic, with
+their delivery type, ack deadline, ordering, filter and dead letter
+topic.
+`
+	listSubscriptionsExample = `  # List the subscriptions of a project
+  axolgo gcp pubsub subscriptions list --project proj1
+
+  # List the subscriptions of a topic
+  axolgo gcp pubsub subscriptions list --topic orders
+`
+)
+
+// NewSubscriptionsCmd creates the `subscriptions` command
+func NewSubscriptionsCmd(ctx *context.Context) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "subscriptions",
+		Short: "A set of Pub/Sub subscription commands.",
+		Long:  "A set of Pub/Sub subscription commands.",
+		Run: func(cmd *cobra.Command, args []string) {
+			cmd.Help()
+			os.Exit(1)
+		},
+	}
+
+	cmd.AddCommand(
+		NewCmdListSubscriptions(ctx),
+	)
+
+	return cmd
+}
+
+// ListSubscriptionsOptions defines flags and other configuration parameters for the `subscriptions list` command
+type ListSubscriptionsOptions struct {
+	Project string
+	Topic   string
+	Output  string
+}
+
+// NewCmdListSubscriptions creates the `subscriptions list` command
+func NewCmdListSubscriptions(ctx *context.Context) *cobra.Command {
+	o := ListSubscriptionsOptions{}
+
+	cmd := &cobra.Command{
+		Use:                   "list [-p] [-t] [-o]",
+		DisableFlagsInUseLine: true,
+		Short:                 "List Pub/Sub subscriptions.",
+		Long:                  listSubscriptionsLong,
+		Example:               listSubscriptionsExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := o.complete(ctx, cmd, args); err != nil {
+				panic(err)
+			}
+		},
+	}
+
+	cmd.Flags().StringVarP(&o.Project, "project", "p", "", "Project ID. Default is the project in axolgo configuration.")
+	cmd.Flags().StringVarP(&o.Topic, "topic", "t", "", "Topic ID or full name to list the subscriptions of.")
+	cmd.Flags().StringVarP(&o.Output, "output", "o", util.OutputTable, fmt.Sprintf("Output format. One of %v.", util.OutputFormats))
+
+	return cmd
+}
+
+// Complete takes the command arguments and execute.
+func (o *ListSubscriptionsOptions) complete(ctx *context.Context, _ *cobra.Command, args []string) error {
+	project, err := util.GCPProject(o.Project)
+	if err != nil {
+		return err
+	}
+	var topic string
+	if o.Topic != "" {
+		topic = TopicName(project, o.Topic)
+	}
+
+	s, err := NewService(context.TODO())
+	if err != nil {
+		return err
+	}
+	subscriptions, err := ListSubscriptions(context.TODO(), s, project, topic)
+	if err != nil {
+		return fmt.Errorf("failed to list subscriptions: %w", err)
+	}
+
+	return util.PrintRecords(os.Stdout, o.Output, subscriptionHeader, subscriptionRows(subscriptions))
+}
@@ -0,0 +1,108 @@
+/*
+Copyright © 2022 tchiunam
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package pubsub
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/tchiunam/axolgo-cli/pkg/util"
+)
+
+var (
+	listTopicsLong = `List the Pub/Sub topics of a project with their message retention,
+KMS key and labels.
+`
+	listTopicsExample = `  # List the topics of a project
+  axolgo gcp pubsub topics list --project proj1
+`
+)
+
+// NewTopicsCmd creates the `topics` command
+func NewTopicsCmd(ctx *context.Context) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "topics",
+		Short: "A set of Pub/Sub topic commands.",
+		Long:  "A set of Pub/Sub topic commands.",
+		Run: func(cmd *cobra.Command, args []string) {
+			cmd.Help()
+			os.Exit(1)
+		},
+	}
+
+	cmd.AddCommand(
+		NewCmdListTopics(ctx),
+	)
+
+	return cmd
+}
+
+// ListTopicsOptions defines flags and other configuration parameters for the `topics list` command
+type ListTopicsOptions struct {
+	Project string
+	Output  string
+}
+
+// NewCmdListTopics creates the `topics list` command
+func NewCmdListTopics(ctx *context.Context) *cobra.Command {
+	o := ListTopicsOptions{}
+
+	cmd := &cobra.Command{
+		Use:                   "list [-p] [-o]",
+		DisableFlagsInUseLine: true,
+		Short:                 "List Pub/Sub topics.",
+		Long:                  listTopicsLong,
+		Example:               listTopicsExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := o.complete(ctx, cmd, args); err != nil {
+				panic(err)
+			}
+		},
+	}
+
+	cmd.Flags().StringVarP(&o.Project, "project", "p", "", "Project ID. Default is the project in axolgo configuration.")
+	cmd.Flags().StringVarP(&o.Output, "output", "o", util.OutputTable, fmt.Sprintf("Output format. One of %v.", util.OutputFormats))
+
+	return cmd
+}
+
+// Complete takes the command arguments and execute.
+func (o *ListTopicsOptions) complete(ctx *context.Context, _ *cobra.Command, args []string) error {
+	project, err := util.GCPProject(o.Project)
+	if err != nil {
+		return err
+	}
+
+	s, err := NewService(context.TODO())
+	if err != nil {
+		return err
+	}
+	topics, err := ListTopics(context.TODO(), s, project)
+	if err != nil {
+		return fmt.Errorf("failed to list topics: %w", err)
+	}
+
+	return util.PrintRecords(os.Stdout, o.Output, topicHeader, topicRows(topics))
+}
@@ -0,0 +1,123 @@
+/*
+Copyright © 2022 tchiunam
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package sql
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/tchiunam/axolgo-cli/pkg/util"
+	sqladmin "google.golang.org/api/sqladmin/v1"
+	"k8s.io/klog/v2"
+)
+
+var (
+	exportFlagsLong = `Export the database flags of a Cloud SQL instance to a flag file
+in the same layout as the parameter files of the RDS commands. The
+flags which require a restart are static and the others are dynamic.
+The file can be given to patchFlags, e.g. to copy the flags to another
+instance.
+`
+	exportFlagsExample = `  # Print the database flags of an instance
+  axolgo gcp sql exportFlags --project proj1 --instance postgres-1
+
+  # Save the database flags of an instance to a file
+  axolgo gcp sql exportFlags --instance postgres-1 --output-file flags.yaml
+`
+)
+
+// ExportFlagsOptions defines flags and other configuration parameters for the `exportFlags` command
+type ExportFlagsOptions struct {
+	Project    string
+	Instance   string
+	OutputFile string
+}
+
+// NewCmdExportFlags creates the `exportFlags` command
+func NewCmdExportFlags(ctx *context.Context) *cobra.Command {
+	o := ExportFlagsOptions{}
+
+	cmd := &cobra.Command{
+		Use:                   "exportFlags -i INSTANCE [-p] [--output-file]",
+		DisableFlagsInUseLine: true,
+		Short:                 "Export the database flags of a Cloud SQL instance.",
+		Long:                  exportFlagsLong,
+		Example:               exportFlagsExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := o.complete(ctx, cmd, args); err != nil {
+				panic(err)
+			}
+		},
+	}
+
+	cmd.Flags().StringVarP(&o.Project, "project", "p", "", "Project ID. Default is the project in axolgo configuration.")
+	cmd.Flags().StringVarP(&o.Instance, "instance", "i", "", "Cloud SQL instance name.")
+	cmd.Flags().StringVar(&o.OutputFile, "output-file", "", "The file to write the flags to. Default is stdout.")
+
+	cmd.MarkFlagRequired("instance")
+
+	return cmd
+}
+
+// Complete takes the command arguments and execute.
+func (o *ExportFlagsOptions) complete(ctx *context.Context, _ *cobra.Command, args []string) error {
+	project, err := util.GCPProject(o.Project)
+	if err != nil {
+		return err
+	}
+
+	opts, err := util.GCPClientOptions(context.TODO())
+	if err != nil {
+		return err
+	}
+	s, err := sqladmin.NewService(context.TODO(), opts...)
+	if err != nil {
+		return fmt.Errorf("failed to create Cloud SQL client: %w", err)
+	}
+
+	instance, err := s.Instances.Get(project, o.Instance).Context(context.TODO()).Do()
+	if err != nil {
+		return fmt.Errorf("failed to get Cloud SQL instance %s: %w", o.Instance, err)
+	}
+	restart, err := RestartFlags(context.TODO(), s, instance.DatabaseVersion)
+	if err != nil {
+		return err
+	}
+	content, err := ExportFlags(InstanceFlags(instance), restart)
+	if err != nil {
+		return err
+	}
+
+	if o.OutputFile == "" {
+		_, err = os.Stdout.Write(content)
+		return err
+	}
+	if err := os.WriteFile(o.OutputFile, content, 0644); err != nil {
+		return err
+	}
+	klog.Infof("Exported the flags of %s to %s", o.Instance, o.OutputFile)
+
+	return nil
+}
@@ -0,0 +1,172 @@
+/*
+Copyright © 2022 tchiunam
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package sql
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	sqladmin "google.golang.org/api/sqladmin/v1"
+	"gopkg.in/yaml.v3"
+)
+
+// FlagChange is a database flag to add or modify
+type FlagChange struct {
+	Name    string
+	Current string
+	Desired string
+	// Added is true if the flag is not set on the instance
+	Added           bool
+	RequiresRestart bool
+}
+
+// FlagFile is the layout of a flag file which is the same as a
+// parameter file of RDS. Static flags require a restart.
+type FlagFile struct {
+	Static  map[string]string `yaml:"static,omitempty"`
+	Dynamic map[string]string `yaml:"dynamic,omitempty"`
+}
+
+// InstanceFlags returns the database flags of an instance keyed by name
+func InstanceFlags(instance *sqladmin.DatabaseInstance) map[string]string {
+	flags := make(map[string]string)
+	if instance.Settings == nil {
+		return flags
+	}
+	for _, f := range instance.Settings.DatabaseFlags {
+		flags[f.Name] = f.Value
+	}
+
+	return flags
+}
+
+// RestartFlags returns whether each flag which is supported by a
+// database version requires a restart
+func RestartFlags(ctx context.Context, s *sqladmin.Service, databaseVersion string) (map[string]bool, error) {
+	resp, err := s.Flags.List().DatabaseVersion(databaseVersion).Context(ctx).Do()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the flags of %s: %w", databaseVersion, err)
+	}
+	restart := make(map[string]bool, len(resp.Items))
+	for _, f := range resp.Items {
+		restart[f.Name] = f.RequiresRestart
+	}
+
+	return restart, nil
+}
+
+// PlanFlags returns the desired flags which are not set or have
+// different values on the instance, sorted by name. It returns an
+// error if a flag is not supported.
+func PlanFlags(current map[string]string, desired map[string]string, restart map[string]bool) ([]FlagChange, error) {
+	var unsupported []string
+	changes := make([]FlagChange, 0)
+	for name, value := range desired {
+		requiresRestart, supported := restart[name]
+		if !supported {
+			unsupported = append(unsupported, name)
+			continue
+		}
+		c, set := current[name]
+		if set && c == value {
+			continue
+		}
+		changes = append(changes, FlagChange{Name: name, Current: c, Desired: value, Added: !set, RequiresRestart: requiresRestart})
+	}
+	if len(unsupported) > 0 {
+		sort.Strings(unsupported)
+		return nil, fmt.Errorf("unsupported flag(s): %s", strings.Join(unsupported, ", "))
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Name < changes[j].Name })
+
+	return changes, nil
+}
+
+// MergeFlags returns the flags of the instance with the changes
+// applied, sorted by name. The flags which are not changed are kept
+// because a patch replaces all the flags of an instance.
+func MergeFlags(current map[string]string, changes []FlagChange) []*sqladmin.DatabaseFlags {
+	merged := make(map[string]string, len(current)+len(changes))
+	for k, v := range current {
+		merged[k] = v
+	}
+	for _, c := range changes {
+		merged[c.Name] = c.Desired
+	}
+
+	names := make([]string, 0, len(merged))
+	for k := range merged {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	flags := make([]*sqladmin.DatabaseFlags, 0, len(names))
+	for _, k := range names {
+		flags = append(flags, &sqladmin.DatabaseFlags{Name: k, Value: merged[k]})
+	}
+
+	return flags
+}
+
+// ExportFlags converts the flags of an instance to a flag file. A flag
+// which requires a restart or is unknown is static.
+func ExportFlags(current map[string]string, restart map[string]bool) ([]byte, error) {
+	file := FlagFile{}
+	for name, value := range current {
+		if requiresRestart, supported := restart[name]; requiresRestart || !supported {
+			if file.Static == nil {
+				file.Static = make(map[string]string)
+			}
+			file.Static[name] = value
+		} else {
+			if file.Dynamic == nil {
+				file.Dynamic = make(map[string]string)
+			}
+			file.Dynamic[name] = value
+		}
+	}
+
+	return yaml.Marshal(file)
+}
+
+// flagChangeRows formats the columns of the flag changes
+func flagChangeRows(changes []FlagChange) [][]string {
+	rows := make([][]string, 0, len(changes))
+	for _, c := range changes {
+		action, current := "modify", c.Current
+		if c.Added {
+			action, current = "add", "-"
+		}
+		restart := "no"
+		if c.RequiresRestart {
+			restart = "yes"
+		}
+		rows = append(rows, []string{action, c.Name, current, c.Desired, restart})
+	}
+
+	return rows
+}
+
+// The header of the flag change rows
+var flagChangeHeader = []string{"ACTION", "FLAG", "CURRENT", "DESIRED", "RESTART"}
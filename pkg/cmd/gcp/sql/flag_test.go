@@ -0,0 +1,186 @@
+/*
+Copyright © 2022 tchiunam
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package sql
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tchiunam/axolgo-cli/pkg/util"
+	sqladmin "google.golang.org/api/sqladmin/v1"
+)
+
+// The restart requirements of the flags in the tests
+var testRestartFlags = map[string]bool{
+	"shared_buffers":             true,
+	"max_connections":            true,
+	"log_min_duration_statement": false,
+	"work_mem":                   false,
+}
+
+// TestInstanceFlags tests the InstanceFlags function
+func TestInstanceFlags(t *testing.T) {
+	cases := map[string]struct {
+		instance *sqladmin.DatabaseInstance
+		flags    map[string]string
+	}{
+		"flags": {
+			instance: &sqladmin.DatabaseInstance{Settings: &sqladmin.Settings{DatabaseFlags: []*sqladmin.DatabaseFlags{
+				{Name: "max_connections", Value: "200"},
+				{Name: "work_mem", Value: "4096"},
+			}}},
+			flags: map[string]string{"max_connections": "200", "work_mem": "4096"},
+		},
+		"no settings": {
+			instance: &sqladmin.DatabaseInstance{},
+			flags:    map[string]string{},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.flags, InstanceFlags(tc.instance))
+		})
+	}
+}
+
+// TestRestartFlags tests the RestartFlags function
+func TestRestartFlags(t *testing.T) {
+	s := newTestService(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "POSTGRES_14", r.URL.Query().Get("databaseVersion"))
+		json.NewEncoder(w).Encode(sqladmin.FlagsListResponse{Items: []*sqladmin.Flag{
+			{Name: "max_connections", RequiresRestart: true},
+			{Name: "work_mem"},
+		}})
+	})
+
+	restart, err := RestartFlags(context.TODO(), s, "POSTGRES_14")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]bool{"max_connections": true, "work_mem": false}, restart)
+}
+
+// TestPlanFlags tests the PlanFlags function
+func TestPlanFlags(t *testing.T) {
+	current := map[string]string{"max_connections": "200", "work_mem": "4096"}
+	cases := map[string]struct {
+		desired map[string]string
+		changes []FlagChange
+		valid   bool
+	}{
+		"add and modify": {
+			desired: map[string]string{"max_connections": "500", "work_mem": "4096", "log_min_duration_statement": "1000"},
+			changes: []FlagChange{
+				{Name: "log_min_duration_statement", Desired: "1000", Added: true},
+				{Name: "max_connections", Current: "200", Desired: "500", RequiresRestart: true},
+			},
+			valid: true,
+		},
+		"up to date": {
+			desired: map[string]string{"work_mem": "4096"},
+			changes: []FlagChange{},
+			valid:   true,
+		},
+		"unsupported flag": {
+			desired: map[string]string{"work_mem": "8192", "innodb_buffer_pool_size": "1024"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			changes, err := PlanFlags(current, tc.desired, testRestartFlags)
+			if tc.valid {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.changes, changes)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
+
+// TestPlanFlagsFromFile tests the PlanFlags function with the flags
+// read from a flag file
+func TestPlanFlagsFromFile(t *testing.T) {
+	staticFlags, dynamicFlags, err := util.ReadParameterFiles(
+		[]string{"testdata/flags.yaml"},
+		util.ParameterFileData{Values: map[string]string{"maxConnections": "500"}},
+	)
+	assert.NoError(t, err)
+
+	changes, err := PlanFlags(map[string]string{"max_connections": "200"}, util.ParameterValues(staticFlags, dynamicFlags), testRestartFlags)
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{
+		{"add", "log_min_duration_statement", "-", "1000", "no"},
+		{"modify", "max_connections", "200", "500", "yes"},
+		{"add", "shared_buffers", "-", "262144", "yes"},
+	}, flagChangeRows(changes))
+}
+
+// TestMergeFlags tests the MergeFlags function
+func TestMergeFlags(t *testing.T) {
+	current := map[string]string{"max_connections": "200", "work_mem": "4096"}
+	changes := []FlagChange{
+		{Name: "max_connections", Current: "200", Desired: "500"},
+		{Name: "log_min_duration_statement", Desired: "1000", Added: true},
+	}
+
+	flags := MergeFlags(current, changes)
+	assert.Equal(t, []*sqladmin.DatabaseFlags{
+		{Name: "log_min_duration_statement", Value: "1000"},
+		{Name: "max_connections", Value: "500"},
+		{Name: "work_mem", Value: "4096"},
+	}, flags)
+	assert.Equal(t, "200", current["max_connections"])
+}
+
+// TestExportFlags tests the ExportFlags function
+func TestExportFlags(t *testing.T) {
+	cases := map[string]struct {
+		current map[string]string
+		yaml    string
+	}{
+		"static and dynamic": {
+			current: map[string]string{"max_connections": "200", "work_mem": "4096", "cloudsql.unknown": "on"},
+			yaml:    "static:\n    cloudsql.unknown: \"on\"\n    max_connections: \"200\"\ndynamic:\n    work_mem: \"4096\"\n",
+		},
+		"dynamic only": {
+			current: map[string]string{"work_mem": "4096"},
+			yaml:    "dynamic:\n    work_mem: \"4096\"\n",
+		},
+		"no flag": {
+			current: map[string]string{},
+			yaml:    "{}\n",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			content, err := ExportFlags(tc.current, testRestartFlags)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.yaml, string(content))
+		})
+	}
+}
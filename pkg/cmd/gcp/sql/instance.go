@@ -0,0 +1,128 @@
+/*
+Copyright © 2022 tchiunam
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package sql
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	sqladmin "google.golang.org/api/sqladmin/v1"
+)
+
+// The interval to poll an operation
+var operationPollInterval = 5 * time.Second
+
+// InstanceInfo is a Cloud SQL instance
+type InstanceInfo struct {
+	Project         string
+	Name            string
+	DatabaseVersion string
+	Region          string
+	Zone            string
+	Tier            string
+	State           string
+	// IPAddresses are in the format of TYPE:ADDRESS, e.g. PRIMARY:34.80.0.1
+	IPAddresses []string
+}
+
+// ListInstances lists the Cloud SQL instances of a project
+func ListInstances(ctx context.Context, s *sqladmin.Service, project string) ([]InstanceInfo, error) {
+	var instances []InstanceInfo
+	err := s.Instances.List(project).Pages(ctx, func(resp *sqladmin.InstancesListResponse) error {
+		for _, i := range resp.Items {
+			instances = append(instances, instanceInfo(i))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(instances, func(i, j int) bool {
+		return instances[i].Name < instances[j].Name
+	})
+
+	return instances, nil
+}
+
+// instanceInfo converts a Cloud SQL instance
+func instanceInfo(instance *sqladmin.DatabaseInstance) InstanceInfo {
+	info := InstanceInfo{
+		Project:         instance.Project,
+		Name:            instance.Name,
+		DatabaseVersion: instance.DatabaseVersion,
+		Region:          instance.Region,
+		Zone:            instance.GceZone,
+		State:           instance.State,
+	}
+	if instance.Settings != nil {
+		info.Tier = instance.Settings.Tier
+	}
+	for _, ip := range instance.IpAddresses {
+		info.IPAddresses = append(info.IPAddresses, fmt.Sprintf("%s:%s", ip.Type, ip.IpAddress))
+	}
+
+	return info
+}
+
+// instanceRows formats the columns of the instances
+func instanceRows(instances []InstanceInfo) [][]string {
+	rows := make([][]string, 0, len(instances))
+	for _, i := range instances {
+		ips := "-"
+		if len(i.IPAddresses) > 0 {
+			ips = strings.Join(i.IPAddresses, ",")
+		}
+		rows = append(rows, []string{i.Project, i.Name, i.DatabaseVersion, i.Region, i.Zone, i.Tier, i.State, ips})
+	}
+
+	return rows
+}
+
+// The header of the instance rows
+var instanceHeader = []string{"PROJECT", "NAME", "DATABASE VERSION", "REGION", "ZONE", "TIER", "STATE", "IP ADDRESSES"}
+
+// WaitOperation polls an operation until it is done within a timeout
+// and returns the error of the operation if it fails
+func WaitOperation(ctx context.Context, s *sqladmin.Service, project string, op *sqladmin.Operation, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	for op.Status != "DONE" {
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("operation %s is not done: %w", op.Name, ctx.Err())
+		case <-time.After(operationPollInterval):
+		}
+		var err error
+		if op, err = s.Operations.Get(project, op.Name).Context(ctx).Do(); err != nil {
+			return fmt.Errorf("failed to get operation: %w", err)
+		}
+	}
+	if op.Error != nil && len(op.Error.Errors) > 0 {
+		return fmt.Errorf("operation %s failed: %s", op.Name, op.Error.Errors[0].Message)
+	}
+
+	return nil
+}
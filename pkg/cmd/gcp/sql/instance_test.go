@@ -0,0 +1,142 @@
+/*
+Copyright © 2022 tchiunam
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package sql
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/api/option"
+	sqladmin "google.golang.org/api/sqladmin/v1"
+)
+
+// newTestService creates a Cloud SQL client of a test server
+func newTestService(t *testing.T, handler http.HandlerFunc) *sqladmin.Service {
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	s, err := sqladmin.NewService(context.TODO(), option.WithEndpoint(server.URL), option.WithoutAuthentication())
+	assert.NoError(t, err)
+
+	return s
+}
+
+// TestInstanceRows tests the instanceInfo and instanceRows functions
+func TestInstanceRows(t *testing.T) {
+	cases := map[string]struct {
+		instance *sqladmin.DatabaseInstance
+		row      []string
+	}{
+		"full instance": {
+			instance: &sqladmin.DatabaseInstance{
+				Project:         "proj1",
+				Name:            "postgres-1",
+				DatabaseVersion: "POSTGRES_14",
+				Region:          "asia-east1",
+				GceZone:         "asia-east1-a",
+				State:           "RUNNABLE",
+				Settings:        &sqladmin.Settings{Tier: "db-custom-2-7680"},
+				IpAddresses: []*sqladmin.IpMapping{
+					{Type: "PRIMARY", IpAddress: "34.80.0.1"},
+					{Type: "PRIVATE", IpAddress: "10.0.0.3"},
+				},
+			},
+			row: []string{"proj1", "postgres-1", "POSTGRES_14", "asia-east1", "asia-east1-a", "db-custom-2-7680", "RUNNABLE", "PRIMARY:34.80.0.1,PRIVATE:10.0.0.3"},
+		},
+		"no settings and address": {
+			instance: &sqladmin.DatabaseInstance{Project: "proj1", Name: "mysql-1", DatabaseVersion: "MYSQL_8_0", Region: "asia-east1", State: "SUSPENDED"},
+			row:      []string{"proj1", "mysql-1", "MYSQL_8_0", "asia-east1", "", "", "SUSPENDED", "-"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			rows := instanceRows([]InstanceInfo{instanceInfo(tc.instance)})
+			assert.Equal(t, [][]string{tc.row}, rows)
+			assert.Len(t, tc.row, len(instanceHeader))
+		})
+	}
+}
+
+// TestWaitOperation tests the WaitOperation function
+func TestWaitOperation(t *testing.T) {
+	cases := map[string]struct {
+		polls   int32
+		message string
+		timeout time.Duration
+		valid   bool
+	}{
+		"done after polls": {
+			polls:   2,
+			timeout: time.Minute,
+			valid:   true,
+		},
+		"failed operation": {
+			polls:   1,
+			message: "invalid flag value",
+			timeout: time.Minute,
+		},
+		"timeout": {
+			polls:   1000,
+			timeout: 50 * time.Millisecond,
+		},
+	}
+
+	oldInterval := operationPollInterval
+	defer func() { operationPollInterval = oldInterval }()
+	operationPollInterval = time.Millisecond
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var polls int32
+			s := newTestService(t, func(w http.ResponseWriter, r *http.Request) {
+				assert.True(t, strings.HasSuffix(r.URL.Path, "/projects/proj1/operations/op1"))
+				op := sqladmin.Operation{Name: "op1", Status: "RUNNING"}
+				if atomic.AddInt32(&polls, 1) >= tc.polls {
+					op.Status = "DONE"
+					if tc.message != "" {
+						op.Error = &sqladmin.OperationErrors{Errors: []*sqladmin.OperationError{{Message: tc.message}}}
+					}
+				}
+				json.NewEncoder(w).Encode(op)
+			})
+
+			err := WaitOperation(context.TODO(), s, "proj1", &sqladmin.Operation{Name: "op1", Status: "PENDING"}, tc.timeout)
+			if tc.valid {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.polls, atomic.LoadInt32(&polls))
+			} else {
+				assert.Error(t, err)
+				if tc.message != "" {
+					assert.Contains(t, err.Error(), tc.message)
+				}
+			}
+		})
+	}
+}
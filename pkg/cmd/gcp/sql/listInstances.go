@@ -0,0 +1,95 @@
+/*
+Copyright © 2022 tchiunam
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package sql
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/tchiunam/axolgo-cli/pkg/util"
+	sqladmin "google.golang.org/api/sqladmin/v1"
+)
+
+var (
+	listInstancesLong = `List the Cloud SQL instances of a project with their database
+version, location, tier, state and IP addresses.
+`
+	listInstancesExample = `  # List the Cloud SQL instances of a project
+  axolgo gcp sql listInstances --project proj1
+`
+)
+
+// ListInstancesOptions defines flags and other configuration parameters for the `listInstances` command
+type ListInstancesOptions struct {
+	Project string
+	Output  string
+}
+
+// NewCmdListInstances creates the `listInstances` command
+func NewCmdListInstances(ctx *context.Context) *cobra.Command {
+	o := ListInstancesOptions{}
+
+	cmd := &cobra.Command{
+		Use:                   "listInstances [-p] [-o]",
+		DisableFlagsInUseLine: true,
+		Short:                 "List Cloud SQL instances.",
+		Long:                  listInstancesLong,
+		Example:               listInstancesExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := o.complete(ctx, cmd, args); err != nil {
+				panic(err)
+			}
+		},
+	}
+
+	cmd.Flags().StringVarP(&o.Project, "project", "p", "", "Project ID. Default is the project in axolgo configuration.")
+	cmd.Flags().StringVarP(&o.Output, "output", "o", util.OutputTable, fmt.Sprintf("Output format. One of %v.", util.OutputFormats))
+
+	return cmd
+}
+
+// Complete takes the command arguments and execute.
+func (o *ListInstancesOptions) complete(ctx *context.Context, _ *cobra.Command, args []string) error {
+	project, err := util.GCPProject(o.Project)
+	if err != nil {
+		return err
+	}
+
+	opts, err := util.GCPClientOptions(context.TODO())
+	if err != nil {
+		return err
+	}
+	s, err := sqladmin.NewService(context.TODO(), opts...)
+	if err != nil {
+		return fmt.Errorf("failed to create Cloud SQL client: %w", err)
+	}
+
+	instances, err := ListInstances(context.TODO(), s, project)
+	if err != nil {
+		return fmt.Errorf("failed to list Cloud SQL instances: %w", err)
+	}
+
+	return util.PrintRecords(os.Stdout, o.Output, instanceHeader, instanceRows(instances))
+}
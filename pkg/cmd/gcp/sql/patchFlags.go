@@ -0,0 +1,218 @@
+/*
+Copyright © 2022 tchiunam
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package sql
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/tchiunam/axolgo-cli/pkg/types"
+	"github.com/tchiunam/axolgo-cli/pkg/util"
+	sqladmin "google.golang.org/api/sqladmin/v1"
+	"k8s.io/klog/v2"
+)
+
+var (
+	patchFlagsLong = `Patch the database flags of a Cloud SQL instance with the flags
+provided. Flags are read from a yaml file in the same layout as the
+parameter files of the RDS commands. Example:
+
+==============================
+static:
+  flag1: value1
+  ...
+dynamic:
+  flag2: value2
+  ...
+==============================
+
+More than one flag file can be given. They are merged in the given
+order and can contain Go template expressions with the values given
+with --set, in the same way as modifyDBParameterGroup.
+
+The flags are compared with the current flags of the instance and a
+plan of the flags to add or modify is printed. Flags of the instance
+which are not in the files are kept. Whether a flag requires a restart
+is looked up from Cloud SQL. A warning is given if the patch restarts
+the instance and if a dynamic flag requires a restart. A confirmation
+is asked unless --yes is given.
+`
+	patchFlagsExample = `  # Show the plan of patching the flags of an instance
+  axolgo gcp sql patchFlags -i postgres-1 -f flags.yaml --dry-run
+
+  # Patch the flags with a base file and an overlay for production
+  axolgo gcp sql patchFlags -i postgres-1 -f base.yaml -f prod.yaml --set maxConnections=500 --yes
+`
+)
+
+// PatchFlagsOptions defines flags and other configuration parameters for the `patchFlags` command
+type PatchFlagsOptions struct {
+	Project   string
+	Instance  string
+	FlagFiles []string
+	Values    []string
+	DryRun    bool
+	Yes       bool
+	Wait      time.Duration
+}
+
+// NewCmdPatchFlags creates the `patchFlags` command
+func NewCmdPatchFlags(ctx *context.Context) *cobra.Command {
+	o := PatchFlagsOptions{}
+
+	cmd := &cobra.Command{
+		Use:                   "patchFlags -i INSTANCE -f FILENAME [-f FILENAME] [--set KEY=VALUE] [-p] [--dry-run] [-y] [--wait]",
+		DisableFlagsInUseLine: true,
+		Short:                 "Patch the database flags of a Cloud SQL instance.",
+		Long:                  patchFlagsLong,
+		Example:               patchFlagsExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := o.complete(ctx, cmd, args); err != nil {
+				panic(err)
+			}
+		},
+	}
+
+	cmd.Flags().StringVarP(&o.Project, "project", "p", "", "Project ID. Default is the project in axolgo configuration.")
+	cmd.Flags().StringVarP(&o.Instance, "instance", "i", "", "Cloud SQL instance name.")
+	cmd.Flags().StringArrayVarP(&o.FlagFiles, "flag-file", "f", nil, "The file that contains flags. Files are merged in the given order.")
+	cmd.Flags().StringArrayVar(&o.Values, "set", nil, "Template value in the format of KEY=VALUE.")
+	cmd.Flags().BoolVar(&o.DryRun, "dry-run", false, "Print the plan without patching.")
+	cmd.Flags().BoolVarP(&o.Yes, "yes", "y", false, "Do not ask for confirmation.")
+	cmd.Flags().DurationVar(&o.Wait, "wait", 15*time.Minute, "Max. time to wait for the patch to be done. 0 is not to wait.")
+
+	cmd.MarkFlagRequired("instance")
+	cmd.MarkFlagRequired("flag-file")
+
+	return cmd
+}
+
+// Complete takes the command arguments and execute.
+func (o *PatchFlagsOptions) complete(ctx *context.Context, _ *cobra.Command, args []string) error {
+	if o.Wait < 0 {
+		return fmt.Errorf("invalid wait: %s", o.Wait)
+	}
+	values, err := util.ParseKeyValues(o.Values)
+	if err != nil {
+		return err
+	}
+
+	axolgoConfig := viper.Get("axolgo-config").(types.AxolgoConfig)
+	staticFlags, dynamicFlags, err := util.ReadParameterFiles(
+		o.FlagFiles,
+		util.ParameterFileData{Values: values, Config: axolgoConfig},
+	)
+	if err != nil {
+		return err
+	}
+	dynamic := util.ParameterValues(dynamicFlags)
+	desired := util.ParameterValues(staticFlags, dynamicFlags)
+
+	project, err := util.GCPProject(o.Project)
+	if err != nil {
+		return err
+	}
+
+	opts, err := util.GCPClientOptions(context.TODO())
+	if err != nil {
+		return err
+	}
+	s, err := sqladmin.NewService(context.TODO(), opts...)
+	if err != nil {
+		return fmt.Errorf("failed to create Cloud SQL client: %w", err)
+	}
+
+	instance, err := s.Instances.Get(project, o.Instance).Context(context.TODO()).Do()
+	if err != nil {
+		return fmt.Errorf("failed to get Cloud SQL instance %s: %w", o.Instance, err)
+	}
+	restart, err := RestartFlags(context.TODO(), s, instance.DatabaseVersion)
+	if err != nil {
+		return err
+	}
+	current := InstanceFlags(instance)
+	changes, err := PlanFlags(current, desired, restart)
+	if err != nil {
+		return err
+	}
+	if len(changes) == 0 {
+		klog.Infof("The flags of %s are up to date", o.Instance)
+		return nil
+	}
+	if err := util.PrintTable(os.Stdout, flagChangeHeader, flagChangeRows(changes)); err != nil {
+		return err
+	}
+
+	var restartFlags []string
+	for _, c := range changes {
+		if !c.RequiresRestart {
+			continue
+		}
+		restartFlags = append(restartFlags, c.Name)
+		if _, ok := dynamic[c.Name]; ok {
+			klog.Warningf("Flag %s is dynamic in the flag file but requires a restart", c.Name)
+		}
+	}
+	question := fmt.Sprintf("Do you want to patch %d flag(s) of %s?", len(changes), o.Instance)
+	if len(restartFlags) > 0 {
+		klog.Warningf("Instance %s will be restarted to apply flag(s): %s", o.Instance, strings.Join(restartFlags, ", "))
+		question = fmt.Sprintf("Do you want to patch %d flag(s) and restart %s?", len(changes), o.Instance)
+	}
+
+	if o.DryRun {
+		klog.Infof("Dry run: %d flag(s) would be patched", len(changes))
+		return nil
+	}
+	if !o.Yes {
+		confirmed, err := util.Confirm(os.Stdin, os.Stdout, question)
+		if err != nil {
+			return err
+		}
+		if !confirmed {
+			klog.Info("Cancelled")
+			return nil
+		}
+	}
+
+	op, err := s.Instances.Patch(project, o.Instance, &sqladmin.DatabaseInstance{
+		Settings: &sqladmin.Settings{DatabaseFlags: MergeFlags(current, changes)},
+	}).Context(context.TODO()).Do()
+	if err != nil {
+		return fmt.Errorf("failed to patch Cloud SQL instance %s: %w", o.Instance, err)
+	}
+	if o.Wait == 0 {
+		klog.Infof("Requested to patch the flags of %s", o.Instance)
+		return nil
+	}
+	if err := WaitOperation(context.TODO(), s, project, op, o.Wait); err != nil {
+		return err
+	}
+	klog.Infof("Patched %d flag(s) of %s", len(changes), o.Instance)
+
+	return nil
+}
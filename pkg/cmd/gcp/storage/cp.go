@@ -0,0 +1,228 @@
+/*
+Copyright © 2022 tchiunam
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"mime"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"k8s.io/klog/v2"
+)
+
+var (
+	cpLong = `Copy a file to Cloud Storage, an object to a file, or an object to
+another object. A - as the source or destination streams from stdin or
+to stdout. A file which is larger than the parallel threshold is
+uploaded in parts in parallel and the parts are composed into the
+object. With --encrypt-with, the content is encrypted with the key file
+on the client side before upload, or decrypted after download. It is
+encrypted as a stream in the format of "axolgo cryptography
+encryptFile", so a large file is encrypted and decrypted in constant
+memory.
+`
+	cpExample = `  # Upload a file
+  axolgo gcp storage cp backup.tar gs://bucket1/backups/
+
+  # Download an object to stdout
+  axolgo gcp storage cp gs://bucket1/report.csv -
+
+  # Upload a file encrypted with a key file and download it decrypted
+  axolgo gcp storage cp --encrypt-with secret.key secrets.env gs://bucket1/secrets.env
+  axolgo gcp storage cp --encrypt-with secret.key gs://bucket1/secrets.env secrets.env
+
+  # Upload a large file in 16 parts in parallel
+  axolgo gcp storage cp --parallel-threshold 100 --parallel-parts 16 disk.img gs://bucket1/
+`
+)
+
+// CpOptions defines flags and other configuration parameters for the `cp` command
+type CpOptions struct {
+	EncryptWith       string
+	ParallelThreshold int64
+	ParallelParts     int
+}
+
+// NewCmdCp creates the `cp` command
+func NewCmdCp(ctx *context.Context) *cobra.Command {
+	o := CpOptions{}
+
+	cmd := &cobra.Command{
+		Use:                   "cp SOURCE DESTINATION [--encrypt-with] [--parallel-threshold] [--parallel-parts]",
+		DisableFlagsInUseLine: true,
+		Short:                 "Copy files and objects.",
+		Long:                  cpLong,
+		Example:               cpExample,
+		Args:                  cobra.ExactArgs(2),
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := o.complete(ctx, cmd, args); err != nil {
+				panic(err)
+			}
+		},
+	}
+
+	cmd.Flags().StringVar(&o.EncryptWith, "encrypt-with", "", "Key file to encrypt the content before upload or decrypt it after download.")
+	cmd.Flags().Int64Var(&o.ParallelThreshold, "parallel-threshold", 150, "Size in MiB from which a file is uploaded in parts in parallel. 0 disables parallel upload.")
+	cmd.Flags().IntVar(&o.ParallelParts, "parallel-parts", 8, fmt.Sprintf("Max. no. of parts of a parallel upload, up to %d.", maxComposeParts))
+
+	return cmd
+}
+
+// Complete takes the command arguments and execute.
+func (o *CpOptions) complete(ctx *context.Context, _ *cobra.Command, args []string) error {
+	if o.ParallelParts < 1 || o.ParallelParts > maxComposeParts {
+		return fmt.Errorf("the no. of parallel parts must be between 1 and %d", maxComposeParts)
+	}
+	if o.ParallelThreshold < 0 {
+		return errors.New("the parallel threshold must not be negative")
+	}
+	src, dst := args[0], args[1]
+	if !IsURL(src) && !IsURL(dst) {
+		return errors.New("either the source or the destination must be a Cloud Storage URL")
+	}
+	if IsURL(src) && IsURL(dst) && o.EncryptWith != "" {
+		return errors.New("--encrypt-with is not supported to copy between objects")
+	}
+
+	var passphrase string
+	if o.EncryptWith != "" {
+		content, err := os.ReadFile(o.EncryptWith)
+		if err != nil {
+			return fmt.Errorf("failed to read key file: %w", err)
+		}
+		passphrase = string(content)
+	}
+
+	client, err := NewClient(context.TODO())
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	switch {
+	case IsURL(src) && IsURL(dst):
+		srcBucket, srcObject, err := ParseURL(src)
+		if err != nil {
+			return err
+		}
+		dstBucket, dstObject, err := ParseURL(dst)
+		if err != nil {
+			return err
+		}
+		dstObject = destinationObject(dstObject, srcObject)
+		copier := client.Bucket(dstBucket).Object(dstObject).CopierFrom(client.Bucket(srcBucket).Object(srcObject))
+		if _, err := copier.Run(context.TODO()); err != nil {
+			return fmt.Errorf("failed to copy %s: %w", src, err)
+		}
+		klog.Infof("Copied %s to %s%s/%s", src, urlScheme, dstBucket, dstObject)
+
+	case IsURL(src):
+		bucket, object, err := ParseURL(src)
+		if err != nil {
+			return err
+		}
+		if object == "" {
+			return fmt.Errorf("no object in %s", src)
+		}
+		if dst == "-" {
+			return Download(context.TODO(), client.Bucket(bucket).Object(object), os.Stdout, passphrase)
+		}
+		if info, err := os.Stat(dst); err == nil && info.IsDir() {
+			dst = filepath.Join(dst, path.Base(object))
+		}
+		f, err := os.Create(dst)
+		if err != nil {
+			return err
+		}
+		if err := Download(context.TODO(), client.Bucket(bucket).Object(object), f, passphrase); err != nil {
+			f.Close()
+			os.Remove(dst)
+			return fmt.Errorf("failed to download %s: %w", src, err)
+		}
+		if err := f.Close(); err != nil {
+			return err
+		}
+		klog.Infof("Copied %s to %s", src, dst)
+
+	default:
+		bucket, object, err := ParseURL(dst)
+		if err != nil {
+			return err
+		}
+		if src == "-" {
+			if object == "" || strings.HasSuffix(object, "/") {
+				return errors.New("the destination object must be named to upload from stdin")
+			}
+			if passphrase != "" {
+				return UploadEncrypted(context.TODO(), client.Bucket(bucket).Object(object), os.Stdin, passphrase)
+			}
+			return Upload(context.TODO(), client.Bucket(bucket).Object(object), os.Stdin, "", nil)
+		}
+
+		object = destinationObject(object, filepath.Base(src))
+		f, err := os.Open(src)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		info, err := f.Stat()
+		if err != nil {
+			return err
+		}
+		contentType := mime.TypeByExtension(filepath.Ext(src))
+
+		switch threshold := o.ParallelThreshold << 20; {
+		case passphrase != "":
+			err = UploadEncrypted(context.TODO(), client.Bucket(bucket).Object(object), f, passphrase)
+		case threshold > 0 && info.Size() >= threshold && o.ParallelParts > 1:
+			parts := PlanParts(info.Size(), o.ParallelParts, threshold/int64(o.ParallelParts))
+			klog.V(1).InfoS("Upload in parallel", "file", src, "size", info.Size(), "parts", len(parts))
+			err = UploadComposite(context.TODO(), client, bucket, object, f, parts, contentType)
+		default:
+			err = Upload(context.TODO(), client.Bucket(bucket).Object(object), f, contentType, nil)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to upload %s: %w", src, err)
+		}
+		klog.Infof("Copied %s to %s%s/%s", src, urlScheme, bucket, object)
+	}
+
+	return nil
+}
+
+// destinationObject returns the name of the destination object. If the
+// destination is a bucket or ends with a /, the base name of the source
+// is appended.
+func destinationObject(object string, source string) string {
+	if object == "" || strings.HasSuffix(object, "/") {
+		return object + path.Base(source)
+	}
+
+	return object
+}
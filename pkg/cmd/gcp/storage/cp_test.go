@@ -0,0 +1,121 @@
+/*
+Copyright © 2022 tchiunam
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package storage
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestNewCmdCp tests the NewCmdCp function
+// to make sure it returns a valid command.
+func TestNewCmdCp(t *testing.T) {
+	cases := map[string]struct {
+		use      string
+		short    string
+		hasFlags bool
+	}{
+		"valid command": {
+			use:      "cp SOURCE DESTINATION [--encrypt-with] [--parallel-threshold] [--parallel-parts]",
+			short:    "Copy files and objects.",
+			hasFlags: true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cmd := NewCmdCp(nil)
+			assert.Equal(t, tc.use, cmd.Use)
+			assert.Equal(t, tc.short, cmd.Short)
+			assert.Equal(t, tc.hasFlags, cmd.Flags().HasFlags())
+		})
+	}
+}
+
+// TestNewCmdCpInvalid calls the NewCmdCp function
+// with invalid input and makes sure it returns an error.
+func TestNewCmdCpInvalid(t *testing.T) {
+	cases := map[string]struct {
+		args []string
+	}{
+		"no Cloud Storage URL": {
+			args: []string{"a.txt", "b.txt"},
+		},
+		"too many parallel parts": {
+			args: []string{"--parallel-parts", "33", "a.txt", "gs://bucket1/"},
+		},
+		"negative parallel threshold": {
+			args: []string{"--parallel-threshold", "-1", "a.txt", "gs://bucket1/"},
+		},
+		"encrypt between objects": {
+			args: []string{"--encrypt-with", "secret.key", "gs://bucket1/a.txt", "gs://bucket2/"},
+		},
+		"key file not found": {
+			args: []string{"--encrypt-with", "testdata/notfound.key", "a.txt", "gs://bucket1/"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			oldArgs := os.Args
+			defer func() { os.Args = oldArgs }()
+			os.Args = append([]string{"cmd"}, tc.args...)
+
+			cmd := NewCmdCp(nil)
+			assert.Panics(t, func() { cmd.Execute() })
+		})
+	}
+}
+
+// TestDestinationObject tests the destinationObject function
+func TestDestinationObject(t *testing.T) {
+	cases := map[string]struct {
+		object string
+		source string
+		want   string
+	}{
+		"bucket": {
+			object: "",
+			source: "backup.tar",
+			want:   "backup.tar",
+		},
+		"prefix": {
+			object: "backups/",
+			source: "logs/backup.tar",
+			want:   "backups/backup.tar",
+		},
+		"object": {
+			object: "backups/2023.tar",
+			source: "backup.tar",
+			want:   "backups/2023.tar",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.want, destinationObject(tc.object, tc.source))
+		})
+	}
+}
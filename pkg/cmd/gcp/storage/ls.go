@@ -0,0 +1,135 @@
+/*
+Copyright © 2022 tchiunam
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package storage
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"cloud.google.com/go/storage"
+	"github.com/spf13/cobra"
+	"github.com/tchiunam/axolgo-cli/pkg/util"
+	"google.golang.org/api/iterator"
+)
+
+var (
+	lsLong = `List the buckets of a project, or the objects of a bucket under a
+prefix. The objects under a / after the prefix are shown as a prefix
+unless the listing is recursive.
+`
+	lsExample = `  # List the buckets of a project
+  axolgo gcp storage ls --project proj1
+
+  # List the objects and prefixes at the top of a bucket
+  axolgo gcp storage ls gs://bucket1
+
+  # List all the objects under a prefix
+  axolgo gcp storage ls -r gs://bucket1/logs/
+`
+)
+
+// LsOptions defines flags and other configuration parameters for the `ls` command
+type LsOptions struct {
+	Project   string
+	Recursive bool
+	Output    string
+}
+
+// NewCmdLs creates the `ls` command
+func NewCmdLs(ctx *context.Context) *cobra.Command {
+	o := LsOptions{}
+
+	cmd := &cobra.Command{
+		Use:                   "ls [gs://BUCKET[/PREFIX]] [-p] [-r] [-o]",
+		DisableFlagsInUseLine: true,
+		Short:                 "List Cloud Storage buckets or objects.",
+		Long:                  lsLong,
+		Example:               lsExample,
+		Args:                  cobra.MaximumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := o.complete(ctx, cmd, args); err != nil {
+				panic(err)
+			}
+		},
+	}
+
+	cmd.Flags().StringVarP(&o.Project, "project", "p", "", "Project ID of the buckets. Default is the project in axolgo configuration.")
+	cmd.Flags().BoolVarP(&o.Recursive, "recursive", "r", false, "List all the objects under the prefix.")
+	cmd.Flags().StringVarP(&o.Output, "output", "o", util.OutputTable, fmt.Sprintf("Output format. One of %v.", util.OutputFormats))
+
+	return cmd
+}
+
+// Complete takes the command arguments and execute.
+func (o *LsOptions) complete(ctx *context.Context, _ *cobra.Command, args []string) error {
+	var bucket, prefix string
+	if len(args) == 1 {
+		var err error
+		if bucket, prefix, err = ParseURL(args[0]); err != nil {
+			return err
+		}
+	}
+
+	client, err := NewClient(context.TODO())
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	if bucket == "" {
+		project, err := util.GCPProject(o.Project)
+		if err != nil {
+			return err
+		}
+		var rows [][]string
+		it := client.Buckets(context.TODO(), project)
+		for {
+			attrs, err := it.Next()
+			if err == iterator.Done {
+				break
+			}
+			if err != nil {
+				return fmt.Errorf("failed to list buckets: %w", err)
+			}
+			rows = append(rows, bucketRow(attrs))
+		}
+		return util.PrintRecords(os.Stdout, o.Output, bucketHeader, rows)
+	}
+
+	objects, err := ListObjects(context.TODO(), client, bucket, prefix, o.Recursive)
+	if err != nil {
+		return fmt.Errorf("failed to list objects: %w", err)
+	}
+
+	return util.PrintRecords(os.Stdout, o.Output, objectHeader, objectRows(objects))
+}
+
+// bucketRow formats the columns of a bucket
+func bucketRow(attrs *storage.BucketAttrs) []string {
+	return []string{urlScheme + attrs.Name, attrs.Location, attrs.StorageClass, attrs.Created.Format(time.RFC3339)}
+}
+
+// The header of the bucket rows
+var bucketHeader = []string{"URL", "LOCATION", "STORAGE CLASS", "CREATED"}
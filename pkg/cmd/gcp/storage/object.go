@@ -0,0 +1,296 @@
+/*
+Copyright © 2022 tchiunam
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"cloud.google.com/go/storage"
+	cmdcryptography "github.com/tchiunam/axolgo-cli/pkg/cmd/cryptography"
+	"github.com/tchiunam/axolgo-cli/pkg/util"
+	"google.golang.org/api/iterator"
+	"google.golang.org/api/option"
+	"k8s.io/klog/v2"
+)
+
+// The scheme of a Cloud Storage URL
+const urlScheme = "gs://"
+
+// The max. no. of objects which can be composed at a time
+const maxComposeParts = 32
+
+// The metadata of an object which is encrypted by axolgo
+const encryptedMetadata = "axolgo-encrypted"
+
+// ObjectInfo is an object, or a prefix which is like a directory
+type ObjectInfo struct {
+	Bucket      string
+	Name        string
+	Size        int64
+	ContentType string
+	Updated     time.Time
+	// Prefix is true if it is a common prefix of objects
+	Prefix bool
+}
+
+// Part is a part of a file to upload in parallel
+type Part struct {
+	Offset int64
+	Length int64
+}
+
+// IsURL checks if a path is a Cloud Storage URL
+func IsURL(path string) bool {
+	return strings.HasPrefix(path, urlScheme)
+}
+
+// ParseURL parses a Cloud Storage URL in the format of
+// gs://BUCKET[/OBJECT] into the bucket and the object name
+func ParseURL(url string) (string, string, error) {
+	if !IsURL(url) {
+		return "", "", fmt.Errorf("invalid Cloud Storage URL: %s", url)
+	}
+	bucket, object, _ := strings.Cut(strings.TrimPrefix(url, urlScheme), "/")
+	if bucket == "" {
+		return "", "", fmt.Errorf("no bucket in Cloud Storage URL: %s", url)
+	}
+
+	return bucket, object, nil
+}
+
+// NewClient creates a Cloud Storage client. If STORAGE_EMULATOR_HOST is
+// set, e.g. to a fake-gcs-server, the client connects to it without
+// authentication.
+func NewClient(ctx context.Context) (*storage.Client, error) {
+	var opts []option.ClientOption
+	if os.Getenv("STORAGE_EMULATOR_HOST") == "" {
+		var err error
+		if opts, err = util.GCPClientOptions(ctx); err != nil {
+			return nil, err
+		}
+	}
+	client, err := storage.NewClient(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Cloud Storage client: %w", err)
+	}
+
+	return client, nil
+}
+
+// ListObjects lists the objects of a bucket with a prefix. Unless
+// recursive is true, the objects under a / after the prefix are
+// grouped into a prefix.
+func ListObjects(ctx context.Context, client *storage.Client, bucket string, prefix string, recursive bool) ([]ObjectInfo, error) {
+	q := &storage.Query{Prefix: prefix}
+	if !recursive {
+		q.Delimiter = "/"
+	}
+
+	var objects []ObjectInfo
+	it := client.Bucket(bucket).Objects(ctx, q)
+	for {
+		attrs, err := it.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if attrs.Prefix != "" {
+			objects = append(objects, ObjectInfo{Bucket: bucket, Name: attrs.Prefix, Prefix: true})
+			continue
+		}
+		objects = append(objects, ObjectInfo{
+			Bucket:      bucket,
+			Name:        attrs.Name,
+			Size:        attrs.Size,
+			ContentType: attrs.ContentType,
+			Updated:     attrs.Updated,
+		})
+	}
+
+	return objects, nil
+}
+
+// PlanParts splits a file into at most maxParts parts of about the
+// same size to upload in parallel. A part is at least minPartSize.
+func PlanParts(size int64, maxParts int, minPartSize int64) []Part {
+	if maxParts > maxComposeParts {
+		maxParts = maxComposeParts
+	}
+	n := int64(maxParts)
+	if minPartSize > 0 && size/minPartSize < n {
+		n = size / minPartSize
+	}
+	if n < 1 {
+		n = 1
+	}
+
+	parts := make([]Part, 0, n)
+	partSize := (size + n - 1) / n
+	for offset := int64(0); offset < size || len(parts) == 0; offset += partSize {
+		length := partSize
+		if offset+length > size {
+			length = size - offset
+		}
+		parts = append(parts, Part{Offset: offset, Length: length})
+	}
+
+	return parts
+}
+
+// partName returns the name of the temporary object of a part
+func partName(object string, i int) string {
+	return object + "_axolgo_part_" + strconv.Itoa(i)
+}
+
+// Upload streams a reader to an object. The upload is aborted if the
+// reader fails so that no partial object is written.
+func Upload(ctx context.Context, obj *storage.ObjectHandle, r io.Reader, contentType string, metadata map[string]string) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	w := obj.NewWriter(ctx)
+	w.ContentType = contentType
+	w.Metadata = metadata
+	if _, err := io.Copy(w, r); err != nil {
+		cancel()
+		w.Close()
+		return err
+	}
+
+	return w.Close()
+}
+
+// UploadComposite uploads the parts of a file to temporary objects in
+// parallel and composes them into the object. The temporary objects are
+// deleted afterwards.
+func UploadComposite(ctx context.Context, client *storage.Client, bucket string, object string, f io.ReaderAt, parts []Part, contentType string) error {
+	b := client.Bucket(bucket)
+	handles := make([]*storage.ObjectHandle, len(parts))
+	errs := make([]error, len(parts))
+	var wg sync.WaitGroup
+	for i, p := range parts {
+		handles[i] = b.Object(partName(object, i))
+		wg.Add(1)
+		go func(i int, p Part) {
+			defer wg.Done()
+			klog.V(3).InfoS("Upload part", "object", object, "part", i, "offset", p.Offset, "length", p.Length)
+			errs[i] = Upload(ctx, handles[i], io.NewSectionReader(f, p.Offset, p.Length), "", nil)
+		}(i, p)
+	}
+	wg.Wait()
+
+	defer func() {
+		for _, h := range handles {
+			if err := h.Delete(context.Background()); err != nil && !errors.Is(err, storage.ErrObjectNotExist) {
+				klog.Warningf("Failed to delete temporary object %s: %v", h.ObjectName(), err)
+			}
+		}
+	}()
+	for i, err := range errs {
+		if err != nil {
+			return fmt.Errorf("failed to upload part %d: %w", i, err)
+		}
+	}
+
+	composer := b.Object(object).ComposerFrom(handles...)
+	composer.ContentType = contentType
+	if _, err := composer.Run(ctx); err != nil {
+		return fmt.Errorf("failed to compose object: %w", err)
+	}
+
+	return nil
+}
+
+// UploadEncrypted encrypts a reader with a passphrase as a stream, in
+// the format of "axolgo cryptography encryptFile", and uploads it to an
+// object in constant memory.
+func UploadEncrypted(ctx context.Context, obj *storage.ObjectHandle, r io.Reader, passphrase string) error {
+	kdf, err := cmdcryptography.DefaultKDF(cmdcryptography.KDFArgon2id)
+	if err != nil {
+		return err
+	}
+	config := cmdcryptography.StreamConfig{ChunkSize: cmdcryptography.DefaultChunkSize, KDF: kdf}
+	pr, pw := io.Pipe()
+	go func() {
+		if err := cmdcryptography.EncryptStream(pw, r, []byte(passphrase), config); err != nil {
+			pw.CloseWithError(fmt.Errorf("failed to encrypt: %w", err))
+			return
+		}
+		pw.Close()
+	}()
+	defer pr.Close()
+
+	return Upload(ctx, obj, pr, "application/octet-stream", map[string]string{encryptedMetadata: "true"})
+}
+
+// Download streams an object to a writer. If passphrase is not empty,
+// the object is decrypted as a stream, or as a whole if it is of the
+// former format.
+func Download(ctx context.Context, obj *storage.ObjectHandle, w io.Writer, passphrase string) error {
+	r, err := obj.NewReader(ctx)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	if passphrase == "" {
+		_, err = io.Copy(w, r)
+		return err
+	}
+	if err := cmdcryptography.DecryptStream(w, r, []byte(passphrase)); err != nil {
+		return fmt.Errorf("failed to decrypt: %w", err)
+	}
+
+	return nil
+}
+
+// objectRows formats the columns of the objects
+func objectRows(objects []ObjectInfo) [][]string {
+	rows := make([][]string, 0, len(objects))
+	for _, o := range objects {
+		if o.Prefix {
+			rows = append(rows, []string{urlScheme + o.Bucket + "/" + o.Name, "-", "-", "-"})
+			continue
+		}
+		rows = append(rows, []string{
+			urlScheme + o.Bucket + "/" + o.Name,
+			strconv.FormatInt(o.Size, 10),
+			o.ContentType,
+			o.Updated.Format(time.RFC3339),
+		})
+	}
+
+	return rows
+}
+
+// The header of the object rows
+var objectHeader = []string{"URL", "SIZE", "CONTENT TYPE", "UPDATED"}
@@ -0,0 +1,373 @@
+/*
+Copyright © 2022 tchiunam
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package storage
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"testing/iotest"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	cmdcryptography "github.com/tchiunam/axolgo-cli/pkg/cmd/cryptography"
+	"github.com/tchiunam/axolgo-lib/cryptography"
+	raw "google.golang.org/api/storage/v1"
+)
+
+// testServer is an in-memory Cloud Storage server which supports the
+// requests to upload, compose, read and delete objects.
+type testServer struct {
+	mu      sync.Mutex
+	objects map[string][]byte
+	// The metadata of the uploaded objects
+	metadata map[string]map[string]string
+	// The no. of uploads
+	uploads int
+}
+
+// newTestServer starts a test server and points STORAGE_EMULATOR_HOST
+// to it so that NewClient connects to it.
+func newTestServer(t *testing.T) *testServer {
+	ts := &testServer{objects: make(map[string][]byte), metadata: make(map[string]map[string]string)}
+	server := httptest.NewServer(ts)
+	t.Cleanup(server.Close)
+	t.Setenv("STORAGE_EMULATOR_HOST", server.URL)
+
+	return ts
+}
+
+// names returns the names of the objects in the server
+func (ts *testServer) names() []string {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	names := make([]string, 0, len(ts.objects))
+	for k := range ts.objects {
+		names = append(names, k)
+	}
+
+	return names
+}
+
+func (ts *testServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	switch {
+	case r.Method == http.MethodPost && r.URL.Path == "/upload/storage/v1/b/bucket1/o":
+		_, params, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		mr := multipart.NewReader(r.Body, params["boundary"])
+		var obj raw.Object
+		part, err := mr.NextPart()
+		if err == nil {
+			err = json.NewDecoder(part).Decode(&obj)
+		}
+		if err == nil {
+			part, err = mr.NextPart()
+		}
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		ts.objects[obj.Name], _ = io.ReadAll(part)
+		ts.metadata[obj.Name] = obj.Metadata
+		ts.uploads++
+		obj.Bucket = "bucket1"
+		json.NewEncoder(w).Encode(obj)
+	case r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/compose"):
+		name := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/storage/v1/b/bucket1/o/"), "/compose")
+		var req raw.ComposeRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		var content []byte
+		for _, src := range req.SourceObjects {
+			content = append(content, ts.objects[src.Name]...)
+		}
+		ts.objects[name] = content
+		json.NewEncoder(w).Encode(raw.Object{Bucket: "bucket1", Name: name})
+	case r.Method == http.MethodDelete:
+		name := strings.TrimPrefix(r.URL.Path, "/storage/v1/b/bucket1/o/")
+		if _, ok := ts.objects[name]; !ok {
+			http.Error(w, "not found", http.StatusNotFound)
+			return
+		}
+		delete(ts.objects, name)
+		w.WriteHeader(http.StatusNoContent)
+	case r.Method == http.MethodGet && strings.HasPrefix(r.URL.Path, "/bucket1/"):
+		content, ok := ts.objects[strings.TrimPrefix(r.URL.Path, "/bucket1/")]
+		if !ok {
+			http.Error(w, "not found", http.StatusNotFound)
+			return
+		}
+		w.Write(content)
+	default:
+		http.Error(w, "unexpected request: "+r.Method+" "+r.URL.Path, http.StatusNotImplemented)
+	}
+}
+
+// TestParseURL tests the ParseURL function
+func TestParseURL(t *testing.T) {
+	cases := map[string]struct {
+		url    string
+		bucket string
+		object string
+		valid  bool
+	}{
+		"bucket": {
+			url:    "gs://bucket1",
+			bucket: "bucket1",
+			valid:  true,
+		},
+		"bucket with slash": {
+			url:    "gs://bucket1/",
+			bucket: "bucket1",
+			valid:  true,
+		},
+		"object": {
+			url:    "gs://bucket1/logs/a.txt",
+			bucket: "bucket1",
+			object: "logs/a.txt",
+			valid:  true,
+		},
+		"no bucket": {
+			url: "gs:///a.txt",
+		},
+		"local file": {
+			url: "logs/a.txt",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			bucket, object, err := ParseURL(tc.url)
+			if tc.valid {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.bucket, bucket)
+				assert.Equal(t, tc.object, object)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
+
+// TestPlanParts tests the PlanParts function
+func TestPlanParts(t *testing.T) {
+	cases := map[string]struct {
+		size        int64
+		maxParts    int
+		minPartSize int64
+		parts       []Part
+	}{
+		"even parts": {
+			size:     300,
+			maxParts: 3,
+			parts:    []Part{{0, 100}, {100, 100}, {200, 100}},
+		},
+		"last part is shorter": {
+			size:     10,
+			maxParts: 3,
+			parts:    []Part{{0, 4}, {4, 4}, {8, 2}},
+		},
+		"min. part size": {
+			size:        300,
+			maxParts:    8,
+			minPartSize: 100,
+			parts:       []Part{{0, 100}, {100, 100}, {200, 100}},
+		},
+		"smaller than min. part size": {
+			size:        50,
+			maxParts:    8,
+			minPartSize: 100,
+			parts:       []Part{{0, 50}},
+		},
+		"empty file": {
+			size:     0,
+			maxParts: 8,
+			parts:    []Part{{0, 0}},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.parts, PlanParts(tc.size, tc.maxParts, tc.minPartSize))
+		})
+	}
+
+	assert.Len(t, PlanParts(1000, 100, 0), maxComposeParts)
+}
+
+// TestObjectRows tests the objectRows function
+func TestObjectRows(t *testing.T) {
+	objects := []ObjectInfo{
+		{Bucket: "bucket1", Name: "logs/", Prefix: true},
+		{Bucket: "bucket1", Name: "a.txt", Size: 12, ContentType: "text/plain", Updated: time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)},
+	}
+
+	rows := objectRows(objects)
+	assert.Equal(t, []string{"gs://bucket1/logs/", "-", "-", "-"}, rows[0])
+	assert.Equal(t, []string{"gs://bucket1/a.txt", "12", "text/plain", "2023-01-01T00:00:00Z"}, rows[1])
+	assert.Len(t, rows[1], len(objectHeader))
+}
+
+// TestUploadComposite tests the UploadComposite function to make sure
+// the parts are composed in order and the temporary objects are deleted.
+func TestUploadComposite(t *testing.T) {
+	ts := newTestServer(t)
+	ctx := context.Background()
+	client, err := NewClient(ctx)
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer client.Close()
+
+	content := strings.Repeat("axolgo", 100)
+	parts := PlanParts(int64(len(content)), 4, 0)
+	assert.Len(t, parts, 4)
+	assert.NoError(t, UploadComposite(ctx, client, "bucket1", "test/composite.txt", strings.NewReader(content), parts, "text/plain"))
+	assert.Equal(t, len(parts), ts.uploads)
+	assert.Equal(t, []string{"test/composite.txt"}, ts.names())
+
+	var buf bytes.Buffer
+	assert.NoError(t, Download(ctx, client.Bucket("bucket1").Object("test/composite.txt"), &buf, ""))
+	assert.Equal(t, content, buf.String())
+}
+
+// TestUploadAbort tests that no object is written if the reader fails
+func TestUploadAbort(t *testing.T) {
+	ts := newTestServer(t)
+	ctx := context.Background()
+	client, err := NewClient(ctx)
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer client.Close()
+
+	r := io.MultiReader(strings.NewReader("axolgo"), iotest.ErrReader(errors.New("read error")))
+	assert.Error(t, Upload(ctx, client.Bucket("bucket1").Object("test/partial.txt"), r, "text/plain", nil))
+	assert.Empty(t, ts.names())
+}
+
+// TestUploadEncrypted tests the UploadEncrypted function to make sure
+// the object is stored as an encrypted stream and decrypted on download.
+func TestUploadEncrypted(t *testing.T) {
+	ts := newTestServer(t)
+	ctx := context.Background()
+	client, err := NewClient(ctx)
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer client.Close()
+
+	content := strings.Repeat("axolgo", 100)
+	obj := client.Bucket("bucket1").Object("test/encrypted.txt")
+	assert.NoError(t, UploadEncrypted(ctx, obj, strings.NewReader(content), "secret"))
+	assert.Equal(t, map[string]string{encryptedMetadata: "true"}, ts.metadata["test/encrypted.txt"])
+	assert.True(t, cmdcryptography.IsStream(ts.objects["test/encrypted.txt"]))
+
+	var buf bytes.Buffer
+	assert.NoError(t, Download(ctx, obj, &buf, ""))
+	assert.NotEqual(t, content, buf.String())
+	buf.Reset()
+	assert.NoError(t, Download(ctx, obj, &buf, "secret"))
+	assert.Equal(t, content, buf.String())
+	assert.Error(t, Download(ctx, obj, &buf, "wrong"))
+
+	// An object encrypted in the former format is decrypted as a whole
+	encrypted, err := cryptography.Encrypt([]byte(content), "secret")
+	assert.NoError(t, err)
+	ts.objects["test/former.txt"] = encrypted
+	buf.Reset()
+	assert.NoError(t, Download(ctx, client.Bucket("bucket1").Object("test/former.txt"), &buf, "secret"))
+	assert.Equal(t, content, buf.String())
+}
+
+// TestEmulator uploads and downloads objects with a Cloud Storage
+// emulator, e.g. fake-gcs-server. The bucket in STORAGE_TEST_BUCKET
+// must exist. It is skipped if STORAGE_EMULATOR_HOST is not set.
+func TestEmulator(t *testing.T) {
+	bucket := os.Getenv("STORAGE_TEST_BUCKET")
+	if os.Getenv("STORAGE_EMULATOR_HOST") == "" || bucket == "" {
+		t.Skip("STORAGE_EMULATOR_HOST and STORAGE_TEST_BUCKET are not set")
+	}
+	ctx := context.Background()
+	client, err := NewClient(ctx)
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer client.Close()
+	content := strings.Repeat("axolgo", 100)
+
+	t.Run("upload and download", func(t *testing.T) {
+		obj := client.Bucket(bucket).Object("test/plain.txt")
+		assert.NoError(t, Upload(ctx, obj, strings.NewReader(content), "text/plain", nil))
+		var buf bytes.Buffer
+		assert.NoError(t, Download(ctx, obj, &buf, ""))
+		assert.Equal(t, content, buf.String())
+	})
+
+	t.Run("composite upload", func(t *testing.T) {
+		parts := PlanParts(int64(len(content)), 4, 0)
+		assert.NoError(t, UploadComposite(ctx, client, bucket, "test/composite.txt", strings.NewReader(content), parts, "text/plain"))
+		var buf bytes.Buffer
+		assert.NoError(t, Download(ctx, client.Bucket(bucket).Object("test/composite.txt"), &buf, ""))
+		assert.Equal(t, content, buf.String())
+
+		objects, err := ListObjects(ctx, client, bucket, "test/composite.txt_axolgo_part_", true)
+		assert.NoError(t, err)
+		assert.Empty(t, objects)
+	})
+
+	t.Run("encrypted upload", func(t *testing.T) {
+		obj := client.Bucket(bucket).Object("test/encrypted.txt")
+		assert.NoError(t, UploadEncrypted(ctx, obj, strings.NewReader(content), "secret"))
+		var buf bytes.Buffer
+		assert.NoError(t, Download(ctx, obj, &buf, ""))
+		assert.NotEqual(t, content, buf.String())
+		buf.Reset()
+		assert.NoError(t, Download(ctx, obj, &buf, "secret"))
+		assert.Equal(t, content, buf.String())
+	})
+
+	t.Run("list", func(t *testing.T) {
+		objects, err := ListObjects(ctx, client, bucket, "", false)
+		assert.NoError(t, err)
+		assert.Contains(t, objects, ObjectInfo{Bucket: bucket, Name: "test/", Prefix: true})
+	})
+}
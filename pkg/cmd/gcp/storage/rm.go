@@ -0,0 +1,138 @@
+/*
+Copyright © 2022 tchiunam
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"cloud.google.com/go/storage"
+	"github.com/spf13/cobra"
+	"github.com/tchiunam/axolgo-cli/pkg/util"
+	"k8s.io/klog/v2"
+)
+
+var (
+	rmLong = `Delete an object, or all the objects under a prefix if the removal
+is recursive. A recursive removal lists the objects and asks for
+confirmation unless --yes is given.
+`
+	rmExample = `  # Delete an object
+  axolgo gcp storage rm gs://bucket1/report.csv
+
+  # Delete all the objects under a prefix without confirmation
+  axolgo gcp storage rm -r -y gs://bucket1/tmp/
+`
+)
+
+// RmOptions defines flags and other configuration parameters for the `rm` command
+type RmOptions struct {
+	Recursive bool
+	Yes       bool
+}
+
+// NewCmdRm creates the `rm` command
+func NewCmdRm(ctx *context.Context) *cobra.Command {
+	o := RmOptions{}
+
+	cmd := &cobra.Command{
+		Use:                   "rm gs://BUCKET/OBJECT [-r] [-y]",
+		DisableFlagsInUseLine: true,
+		Short:                 "Delete Cloud Storage objects.",
+		Long:                  rmLong,
+		Example:               rmExample,
+		Args:                  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := o.complete(ctx, cmd, args); err != nil {
+				panic(err)
+			}
+		},
+	}
+
+	cmd.Flags().BoolVarP(&o.Recursive, "recursive", "r", false, "Delete all the objects under the prefix.")
+	cmd.Flags().BoolVarP(&o.Yes, "yes", "y", false, "Delete without confirmation.")
+
+	return cmd
+}
+
+// Complete takes the command arguments and execute.
+func (o *RmOptions) complete(ctx *context.Context, _ *cobra.Command, args []string) error {
+	bucket, object, err := ParseURL(args[0])
+	if err != nil {
+		return err
+	}
+	if object == "" {
+		return fmt.Errorf("no object or prefix in %s", args[0])
+	}
+
+	client, err := NewClient(context.TODO())
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	if !o.Recursive {
+		if err := client.Bucket(bucket).Object(object).Delete(context.TODO()); err != nil {
+			return fmt.Errorf("failed to delete %s: %w", args[0], err)
+		}
+		klog.Infof("Deleted %s", args[0])
+		return nil
+	}
+
+	objects, err := ListObjects(context.TODO(), client, bucket, object, true)
+	if err != nil {
+		return fmt.Errorf("failed to list objects: %w", err)
+	}
+	if len(objects) == 0 {
+		klog.Infof("No objects under %s", args[0])
+		return nil
+	}
+	if !o.Yes {
+		names := make([]string, len(objects))
+		for i, obj := range objects {
+			names[i] = "  " + urlScheme + bucket + "/" + obj.Name
+		}
+		fmt.Println(strings.Join(names, "\n"))
+		ok, err := util.Confirm(os.Stdin, os.Stdout, fmt.Sprintf("Delete %d objects?", len(objects)))
+		if err != nil {
+			return err
+		}
+		if !ok {
+			klog.Info("Aborted")
+			return nil
+		}
+	}
+
+	for _, obj := range objects {
+		err := client.Bucket(bucket).Object(obj.Name).Delete(context.TODO())
+		if err != nil && !errors.Is(err, storage.ErrObjectNotExist) {
+			return fmt.Errorf("failed to delete %s: %w", obj.Name, err)
+		}
+	}
+	klog.Infof("Deleted %d objects under %s", len(objects), args[0])
+
+	return nil
+}
@@ -0,0 +1,149 @@
+/*
+Copyright © 2022 tchiunam
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/storage"
+	"github.com/spf13/cobra"
+	"github.com/tchiunam/axolgo-cli/pkg/util"
+)
+
+// The max. duration of a V4 signed URL
+const maxSignedURLDuration = 7 * 24 * time.Hour
+
+var (
+	signURLLong = `Generate a V4 signed URL of an object which gives access to anyone
+with the URL until it expires. The URL is signed offline with the
+private key of a service account key file, which is the configured
+credentials file by default. The max. duration is 7 days.
+`
+	signURLExample = `  # Generate a URL to download an object in an hour
+  axolgo gcp storage signUrl gs://bucket1/report.csv
+
+  # Generate a URL to upload an object in 10 minutes with a key file
+  axolgo gcp storage signUrl --method PUT --duration 10m --key-file sa.json gs://bucket1/upload.bin
+`
+)
+
+// SignURLOptions defines flags and other configuration parameters for the `signUrl` command
+type SignURLOptions struct {
+	Duration time.Duration
+	Method   string
+	KeyFile  string
+}
+
+// NewCmdSignURL creates the `signUrl` command
+func NewCmdSignURL(ctx *context.Context) *cobra.Command {
+	o := SignURLOptions{}
+
+	cmd := &cobra.Command{
+		Use:                   "signUrl gs://BUCKET/OBJECT [-d] [-m] [--key-file]",
+		DisableFlagsInUseLine: true,
+		Short:                 "Generate a signed URL of an object.",
+		Long:                  signURLLong,
+		Example:               signURLExample,
+		Args:                  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := o.complete(ctx, cmd, args); err != nil {
+				panic(err)
+			}
+		},
+	}
+
+	cmd.Flags().DurationVarP(&o.Duration, "duration", "d", time.Hour, "Duration until the URL expires, up to 7 days.")
+	cmd.Flags().StringVarP(&o.Method, "method", "m", http.MethodGet, "HTTP method of the URL.")
+	cmd.Flags().StringVar(&o.KeyFile, "key-file", "", "Service account key file. Default is the credentials file in axolgo configuration.")
+
+	return cmd
+}
+
+// Complete takes the command arguments and execute.
+func (o *SignURLOptions) complete(ctx *context.Context, _ *cobra.Command, args []string) error {
+	bucket, object, err := ParseURL(args[0])
+	if err != nil {
+		return err
+	}
+	if object == "" {
+		return fmt.Errorf("no object in %s", args[0])
+	}
+	if o.Duration <= 0 || o.Duration > maxSignedURLDuration {
+		return fmt.Errorf("the duration must be positive and at most %s", maxSignedURLDuration)
+	}
+
+	keyFile := o.KeyFile
+	if keyFile == "" {
+		if keyFile = util.LoadGCPAuth().CredentialsFile; keyFile == "" {
+			return errors.New("no key file is given or configured to sign the URL")
+		}
+	}
+	keyJSON, err := os.ReadFile(keyFile)
+	if err != nil {
+		return fmt.Errorf("failed to read key file: %w", err)
+	}
+
+	url, err := SignURL(keyJSON, bucket, object, strings.ToUpper(o.Method), time.Now().Add(o.Duration))
+	if err != nil {
+		return err
+	}
+	fmt.Println(url)
+
+	return nil
+}
+
+// SignURL generates a V4 signed URL of an object with the private key
+// of a service account key file
+func SignURL(keyJSON []byte, bucket string, object string, method string, expires time.Time) (string, error) {
+	var key struct {
+		Type        string `json:"type"`
+		ClientEmail string `json:"client_email"`
+		PrivateKey  string `json:"private_key"`
+	}
+	if err := json.Unmarshal(keyJSON, &key); err != nil {
+		return "", fmt.Errorf("failed to parse key file: %w", err)
+	}
+	if key.Type != "service_account" || key.ClientEmail == "" || key.PrivateKey == "" {
+		return "", errors.New("the key file is not a service account key with a private key")
+	}
+
+	url, err := storage.SignedURL(bucket, object, &storage.SignedURLOptions{
+		GoogleAccessID: key.ClientEmail,
+		PrivateKey:     []byte(key.PrivateKey),
+		Method:         method,
+		Expires:        expires,
+		Scheme:         storage.SigningSchemeV4,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to sign URL: %w", err)
+	}
+
+	return url, nil
+}
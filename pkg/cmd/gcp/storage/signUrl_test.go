@@ -0,0 +1,144 @@
+/*
+Copyright © 2022 tchiunam
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package storage
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"net/url"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestNewCmdSignURL tests the NewCmdSignURL function
+// to make sure it returns a valid command.
+func TestNewCmdSignURL(t *testing.T) {
+	cases := map[string]struct {
+		use      string
+		short    string
+		hasFlags bool
+	}{
+		"valid command": {
+			use:      "signUrl gs://BUCKET/OBJECT [-d] [-m] [--key-file]",
+			short:    "Generate a signed URL of an object.",
+			hasFlags: true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cmd := NewCmdSignURL(nil)
+			assert.Equal(t, tc.use, cmd.Use)
+			assert.Equal(t, tc.short, cmd.Short)
+			assert.Equal(t, tc.hasFlags, cmd.Flags().HasFlags())
+		})
+	}
+}
+
+// TestNewCmdSignURLInvalid calls the NewCmdSignURL function
+// with invalid input and makes sure it returns an error.
+func TestNewCmdSignURLInvalid(t *testing.T) {
+	cases := map[string]struct {
+		args []string
+	}{
+		"no object": {
+			args: []string{"gs://bucket1"},
+		},
+		"duration too long": {
+			args: []string{"--duration", "169h", "gs://bucket1/a.txt"},
+		},
+		"key file not found": {
+			args: []string{"--key-file", "testdata/notfound.json", "gs://bucket1/a.txt"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			oldArgs := os.Args
+			defer func() { os.Args = oldArgs }()
+			os.Args = append([]string{"cmd"}, tc.args...)
+
+			cmd := NewCmdSignURL(nil)
+			assert.Panics(t, func() { cmd.Execute() })
+		})
+	}
+}
+
+// TestSignURL tests the SignURL function with a generated key
+func TestSignURL(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	privateKey := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+	keyJSON, err := json.Marshal(map[string]string{
+		"type":         "service_account",
+		"client_email": "sa1@proj1.iam.gserviceaccount.com",
+		"private_key":  string(privateKey),
+	})
+	assert.NoError(t, err)
+
+	signed, err := SignURL(keyJSON, "bucket1", "logs/a.txt", "GET", time.Now().Add(time.Hour))
+	assert.NoError(t, err)
+	u, err := url.Parse(signed)
+	assert.NoError(t, err)
+	assert.True(t, strings.HasSuffix(u.Path, "/bucket1/logs/a.txt"))
+	q := u.Query()
+	assert.Equal(t, "GOOG4-RSA-SHA256", q.Get("X-Goog-Algorithm"))
+	assert.True(t, strings.HasPrefix(q.Get("X-Goog-Credential"), "sa1@proj1.iam.gserviceaccount.com/"))
+	assert.Contains(t, []string{"3599", "3600"}, q.Get("X-Goog-Expires"))
+	assert.NotEmpty(t, q.Get("X-Goog-Signature"))
+}
+
+// TestSignURLInvalid calls the SignURL function
+// with invalid keys and makes sure it returns an error.
+func TestSignURLInvalid(t *testing.T) {
+	cases := map[string]struct {
+		keyJSON string
+	}{
+		"not JSON": {
+			keyJSON: "secret",
+		},
+		"authorized user": {
+			keyJSON: `{"type": "authorized_user", "client_id": "id1"}`,
+		},
+		"no private key": {
+			keyJSON: `{"type": "service_account", "client_email": "sa1@proj1.iam.gserviceaccount.com"}`,
+		},
+		"invalid private key": {
+			keyJSON: `{"type": "service_account", "client_email": "sa1@proj1.iam.gserviceaccount.com", "private_key": "key"}`,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := SignURL([]byte(tc.keyJSON), "bucket1", "a.txt", "GET", time.Now().Add(time.Hour))
+			assert.Error(t, err)
+		})
+	}
+}
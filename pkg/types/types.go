@@ -10,13 +10,24 @@ type AxolgoConfigLogging struct {
 type AxolgoConfigAWS struct {
 	// AWS region
 	Region string `mapstructure:"region"`
+	// AWS shared config profile
+	Profile string `mapstructure:"profile"`
 }
 
 // Structure of GCP configuration
 type AxolgoConfigGCP struct {
 	// Google application credentials file
+	// Application Default Credentials are used if it is empty
 	GoogleApplicationCredentials string `mapstructure:"google-application-credentials"`
-	Zone                         string `mapstructure:"zone"`
+	// Service account to impersonate. A comma separated list is a
+	// delegation chain in which the last one is impersonated.
+	ImpersonateServiceAccount string `mapstructure:"impersonate-service-account"`
+	// Default project ID
+	Project string `mapstructure:"project"`
+	// Default region
+	Region string `mapstructure:"region"`
+	// Default zone
+	Zone string `mapstructure:"zone"`
 }
 
 // Structure of axolgo configuration
@@ -36,14 +36,20 @@ func TestAxolgoConfig(t *testing.T) {
 		configFilePath               string
 		logLevelverbosity            int
 		awsRegion                    string
+		awsProfile                   string
 		googleApplicationCredentials string
+		gcpProject                   string
+		gcpRegion                    string
 		gcpZone                      string
 	}{
 		"normal config file": {
 			configFilePath:               "./testdata",
 			logLevelverbosity:            0,
 			awsRegion:                    "ap-east-1",
+			awsProfile:                   "default",
 			googleApplicationCredentials: "~/.gcp_credentials",
+			gcpProject:                   "proj1",
+			gcpRegion:                    "asia-east1",
 			gcpZone:                      "asia-east1-a",
 		},
 	}
@@ -61,7 +67,10 @@ func TestAxolgoConfig(t *testing.T) {
 			assert.NoError(t, viper.Unmarshal(&axolgoConfig), "Failed to unmarshal config file")
 			assert.Equal(t, tc.logLevelverbosity, axolgoConfig.Logging.LogLevelVerbosity, "Expected log level verbosity %d, got %d", tc.logLevelverbosity, axolgoConfig.Logging.LogLevelVerbosity)
 			assert.Equal(t, tc.awsRegion, axolgoConfig.AWS.Region, "Expected aws region %s, got %s", tc.awsRegion, axolgoConfig.AWS.Region)
+			assert.Equal(t, tc.awsProfile, axolgoConfig.AWS.Profile, "Expected aws profile %s, got %s", tc.awsProfile, axolgoConfig.AWS.Profile)
 			assert.Equal(t, tc.googleApplicationCredentials, axolgoConfig.GCP.GoogleApplicationCredentials, "Expected google application credentials %s, got %s", tc.googleApplicationCredentials, axolgoConfig.GCP.GoogleApplicationCredentials)
+			assert.Equal(t, tc.gcpProject, axolgoConfig.GCP.Project, "Expected gcp project %s, got %s", tc.gcpProject, axolgoConfig.GCP.Project)
+			assert.Equal(t, tc.gcpRegion, axolgoConfig.GCP.Region, "Expected gcp region %s, got %s", tc.gcpRegion, axolgoConfig.GCP.Region)
 			assert.Equal(t, tc.gcpZone, axolgoConfig.GCP.Zone, "Expected gcp zone %s, got %s", tc.gcpZone, axolgoConfig.GCP.Zone)
 		})
 	}
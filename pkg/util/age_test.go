@@ -0,0 +1,76 @@
+/*
+Copyright © 2022 tchiunam
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package util
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestParseAge tests the ParseAge function
+func TestParseAge(t *testing.T) {
+	cases := map[string]struct {
+		age      string
+		duration time.Duration
+		valid    bool
+	}{
+		"empty":    {age: "", duration: 0, valid: true},
+		"days":     {age: "30d", duration: 30 * 24 * time.Hour, valid: true},
+		"duration": {age: "1h30m", duration: 90 * time.Minute, valid: true},
+		"invalid":  {age: "30days"},
+		"negative": {age: "-1d"},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			d, err := ParseAge(c.age)
+			if c.valid {
+				assert.NoError(t, err)
+				assert.Equal(t, c.duration, d)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
+
+// TestFormatAge tests the FormatAge function
+func TestFormatAge(t *testing.T) {
+	cases := map[string]struct {
+		duration time.Duration
+		age      string
+	}{
+		"minutes":  {duration: 5 * time.Minute, age: "5m"},
+		"hours":    {duration: 3*time.Hour + 20*time.Minute, age: "3h20m"},
+		"days":     {duration: 50 * time.Hour, age: "2d2h"},
+		"negative": {duration: -time.Hour, age: "0m"},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, c.age, FormatAge(c.duration))
+		})
+	}
+}
@@ -0,0 +1,54 @@
+/*
+Copyright © 2022 tchiunam
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package util
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/spf13/viper"
+	"github.com/tchiunam/axolgo-cli/pkg/types"
+	"k8s.io/klog/v2"
+)
+
+// LoadAWSConfig loads the AWS configuration with the given region and
+// shared config profile. The ones in the axolgo configuration are used
+// if they are empty.
+func LoadAWSConfig(ctx context.Context, region string, profile string) (aws.Config, error) {
+	axolgoConfig := viper.Get("axolgo-config").(types.AxolgoConfig)
+	if region == "" {
+		region = axolgoConfig.AWS.Region
+	}
+	if profile == "" {
+		profile = axolgoConfig.AWS.Profile
+	}
+	klog.V(3).InfoS("Load AWS config", "region", region, "profile", profile)
+
+	optFns := []func(*config.LoadOptions) error{config.WithRegion(region)}
+	if profile != "" {
+		optFns = append(optFns, config.WithSharedConfigProfile(profile))
+	}
+
+	return config.LoadDefaultConfig(ctx, optFns...)
+}
@@ -0,0 +1,207 @@
+/*
+Copyright © 2022 tchiunam
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package util
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/viper"
+	"github.com/tchiunam/axolgo-cli/pkg/types"
+	axolgolibutil "github.com/tchiunam/axolgo-lib/util"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/google"
+	"google.golang.org/api/impersonate"
+	"google.golang.org/api/option"
+	"k8s.io/klog/v2"
+)
+
+// GCPScope is the OAuth scope of Google Cloud clients
+const GCPScope = "https://www.googleapis.com/auth/cloud-platform"
+
+// GCPAuth is the authentication of Google Cloud clients
+type GCPAuth struct {
+	// Credentials file. Application Default Credentials are used if it
+	// is empty.
+	CredentialsFile string
+	// Service account to impersonate
+	ImpersonateServiceAccount string
+	// Service accounts in the delegation chain to the impersonated one
+	Delegates []string
+}
+
+// LoadGCPAuth returns the authentication in the axolgo configuration
+func LoadGCPAuth() GCPAuth {
+	axolgoConfig := viper.Get("axolgo-config").(types.AxolgoConfig)
+	klog.V(3).InfoS("axolgoConfig",
+		"GCP.GoogleApplicationCredentials", axolgoConfig.GCP.GoogleApplicationCredentials,
+		"GCP.ImpersonateServiceAccount", axolgoConfig.GCP.ImpersonateServiceAccount)
+
+	auth := GCPAuth{}
+	if axolgoConfig.GCP.GoogleApplicationCredentials != "" {
+		auth.CredentialsFile = axolgolibutil.ExpandPath(axolgoConfig.GCP.GoogleApplicationCredentials)
+	}
+	auth.ImpersonateServiceAccount, auth.Delegates = ParseImpersonationChain(axolgoConfig.GCP.ImpersonateServiceAccount)
+
+	return auth
+}
+
+// ParseImpersonationChain splits a comma separated list of service
+// accounts. The last one is impersonated through the others in order.
+func ParseImpersonationChain(chain string) (string, []string) {
+	var accounts []string
+	for _, a := range strings.Split(chain, ",") {
+		if a = strings.TrimSpace(a); a != "" {
+			accounts = append(accounts, a)
+		}
+	}
+	if len(accounts) == 0 {
+		return "", nil
+	}
+
+	return accounts[len(accounts)-1], accounts[:len(accounts)-1]
+}
+
+// Credentials returns the base credentials, which are read from the
+// credentials file or found as Application Default Credentials
+func (a GCPAuth) Credentials(ctx context.Context) (*google.Credentials, error) {
+	if a.CredentialsFile != "" {
+		data, err := os.ReadFile(a.CredentialsFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read credentials file: %w", err)
+		}
+		creds, err := google.CredentialsFromJSON(ctx, data, GCPScope)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse credentials file %s: %w", a.CredentialsFile, err)
+		}
+		return creds, nil
+	}
+
+	creds, err := google.FindDefaultCredentials(ctx, GCPScope)
+	if err != nil {
+		return nil, fmt.Errorf("no credentials file is configured and Application Default Credentials are not found: %w", err)
+	}
+	klog.V(3).Info("Use Application Default Credentials")
+
+	return creds, nil
+}
+
+// TokenSource returns the source of the tokens of the effective
+// principal, which is the impersonated service account if any
+func (a GCPAuth) TokenSource(ctx context.Context) (oauth2.TokenSource, error) {
+	creds, err := a.Credentials(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if a.ImpersonateServiceAccount == "" {
+		return creds.TokenSource, nil
+	}
+
+	klog.V(3).InfoS("Impersonate service account", "serviceAccount", a.ImpersonateServiceAccount, "delegates", a.Delegates)
+	ts, err := impersonate.CredentialsTokenSource(ctx, impersonate.CredentialsConfig{
+		TargetPrincipal: a.ImpersonateServiceAccount,
+		Scopes:          []string{GCPScope},
+		Delegates:       a.Delegates,
+	}, option.WithCredentials(creds))
+	if err != nil {
+		return nil, fmt.Errorf("failed to impersonate service account %s: %w", a.ImpersonateServiceAccount, err)
+	}
+
+	return ts, nil
+}
+
+// ClientOptions returns the options of Google Cloud clients
+func (a GCPAuth) ClientOptions(ctx context.Context) ([]option.ClientOption, error) {
+	if a.ImpersonateServiceAccount == "" {
+		creds, err := a.Credentials(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return []option.ClientOption{option.WithCredentials(creds)}, nil
+	}
+
+	ts, err := a.TokenSource(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return []option.ClientOption{option.WithTokenSource(ts)}, nil
+}
+
+// GCPClientOptions returns the options of Google Cloud clients with the
+// authentication in the axolgo configuration
+func GCPClientOptions(ctx context.Context) ([]option.ClientOption, error) {
+	return LoadGCPAuth().ClientOptions(ctx)
+}
+
+// GCPProject returns the given project, or the project in the axolgo
+// configuration, or the project of the credentials in order
+func GCPProject(project string) (string, error) {
+	if project != "" {
+		return project, nil
+	}
+	axolgoConfig := viper.Get("axolgo-config").(types.AxolgoConfig)
+	if axolgoConfig.GCP.Project != "" {
+		return axolgoConfig.GCP.Project, nil
+	}
+
+	creds, err := LoadGCPAuth().Credentials(context.TODO())
+	if err != nil {
+		return "", fmt.Errorf("a project is required with --project or the project in axolgo configuration: %w", err)
+	}
+	if creds.ProjectID == "" {
+		return "", fmt.Errorf("a project is required with --project or the project in axolgo configuration as the credentials have no project")
+	}
+	klog.V(3).InfoS("Use project of credentials", "project", creds.ProjectID)
+
+	return creds.ProjectID, nil
+}
+
+// GCPRegion returns the given region or the region in the axolgo configuration
+func GCPRegion(region string) string {
+	if region != "" {
+		return region
+	}
+
+	return viper.Get("axolgo-config").(types.AxolgoConfig).GCP.Region
+}
+
+// GCPZone returns the given zone or the zone in the axolgo configuration
+func GCPZone(zone string) string {
+	if zone != "" {
+		return zone
+	}
+
+	return viper.Get("axolgo-config").(types.AxolgoConfig).GCP.Zone
+}
+
+// GCPZoneRegion returns the region of a zone, e.g. asia-east1 of asia-east1-a
+func GCPZoneRegion(zone string) string {
+	if i := strings.LastIndex(zone, "-"); i > 0 {
+		return zone[:i]
+	}
+
+	return zone
+}
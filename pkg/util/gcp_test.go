@@ -0,0 +1,167 @@
+/*
+Copyright © 2022 tchiunam
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package util
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/tchiunam/axolgo-cli/pkg/types"
+)
+
+// TestGCPProject tests the GCPProject function
+func TestGCPProject(t *testing.T) {
+	testdata := filepath.Join("..", "testdata", "gcp")
+	cases := map[string]struct {
+		project string
+		config  types.AxolgoConfigGCP
+		adc     string
+		result  string
+		valid   bool
+	}{
+		"given project": {
+			project: "proj1",
+			config:  types.AxolgoConfigGCP{Project: "proj2"},
+			result:  "proj1",
+			valid:   true,
+		},
+		"project in configuration": {
+			config: types.AxolgoConfigGCP{Project: "proj2", GoogleApplicationCredentials: filepath.Join(testdata, "credentials.json")},
+			result: "proj2",
+			valid:  true,
+		},
+		"project of credentials file": {
+			config: types.AxolgoConfigGCP{GoogleApplicationCredentials: filepath.Join(testdata, "credentials.json")},
+			result: "proj-from-credentials",
+			valid:  true,
+		},
+		"credentials file without project": {
+			config: types.AxolgoConfigGCP{GoogleApplicationCredentials: filepath.Join(testdata, "credentials_no_project.json")},
+		},
+		"missing credentials file": {
+			config: types.AxolgoConfigGCP{GoogleApplicationCredentials: filepath.Join(testdata, "missing.json")},
+		},
+		"application default credentials": {
+			adc:    filepath.Join(testdata, "credentials.json"),
+			result: "proj-from-credentials",
+			valid:  true,
+		},
+		"no project": {
+			adc: filepath.Join(testdata, "credentials_no_project.json"),
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			t.Setenv("GOOGLE_APPLICATION_CREDENTIALS", c.adc)
+			viper.Set("axolgo-config", types.AxolgoConfig{GCP: c.config})
+			project, err := GCPProject(c.project)
+			if c.valid {
+				assert.NoError(t, err)
+				assert.Equal(t, c.result, project)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
+
+// TestParseImpersonationChain tests the ParseImpersonationChain function
+func TestParseImpersonationChain(t *testing.T) {
+	cases := map[string]struct {
+		chain          string
+		serviceAccount string
+		delegates      []string
+	}{
+		"no impersonation": {
+			chain: "",
+		},
+		"a service account": {
+			chain:          "deployer@proj1.iam.gserviceaccount.com",
+			serviceAccount: "deployer@proj1.iam.gserviceaccount.com",
+			delegates:      []string{},
+		},
+		"delegation chain": {
+			chain:          "a@proj1.iam.gserviceaccount.com, b@proj1.iam.gserviceaccount.com,deployer@proj2.iam.gserviceaccount.com",
+			serviceAccount: "deployer@proj2.iam.gserviceaccount.com",
+			delegates:      []string{"a@proj1.iam.gserviceaccount.com", "b@proj1.iam.gserviceaccount.com"},
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			serviceAccount, delegates := ParseImpersonationChain(c.chain)
+			assert.Equal(t, c.serviceAccount, serviceAccount)
+			assert.Equal(t, c.delegates, delegates)
+		})
+	}
+}
+
+// TestLoadGCPAuth tests the LoadGCPAuth function
+func TestLoadGCPAuth(t *testing.T) {
+	viper.Set("axolgo-config", types.AxolgoConfig{GCP: types.AxolgoConfigGCP{
+		ImpersonateServiceAccount: "a@proj1.iam.gserviceaccount.com,deployer@proj1.iam.gserviceaccount.com",
+	}})
+
+	auth := LoadGCPAuth()
+	assert.Equal(t, "", auth.CredentialsFile)
+	assert.Equal(t, "deployer@proj1.iam.gserviceaccount.com", auth.ImpersonateServiceAccount)
+	assert.Equal(t, []string{"a@proj1.iam.gserviceaccount.com"}, auth.Delegates)
+}
+
+// TestGCPRegionAndZone tests the GCPRegion and GCPZone functions
+func TestGCPRegionAndZone(t *testing.T) {
+	viper.Set("axolgo-config", types.AxolgoConfig{GCP: types.AxolgoConfigGCP{Region: "asia-east1", Zone: "asia-east1-a"}})
+
+	assert.Equal(t, "asia-east1", GCPRegion(""))
+	assert.Equal(t, "us-central1", GCPRegion("us-central1"))
+	assert.Equal(t, "asia-east1-a", GCPZone(""))
+	assert.Equal(t, "us-central1-b", GCPZone("us-central1-b"))
+}
+
+func TestGCPZoneRegion(t *testing.T) {
+	cases := map[string]struct {
+		zone   string
+		region string
+	}{
+		"zone": {
+			zone:   "asia-east1-a",
+			region: "asia-east1",
+		},
+		"region": {
+			zone:   "us-central1",
+			region: "us",
+		},
+		"no dash": {
+			zone:   "global",
+			region: "global",
+		},
+	}
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, c.region, GCPZoneRegion(c.zone))
+		})
+	}
+}
@@ -0,0 +1,235 @@
+/*
+Copyright © 2022 tchiunam
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package util
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+	axolgolibutil "github.com/tchiunam/axolgo-lib/util"
+	"gopkg.in/yaml.v3"
+)
+
+// The API version of the exec credential plugin
+const execCredentialAPIVersion = "client.authentication.k8s.io/v1beta1"
+
+// Kubeconfig is a kubeconfig file. The fields which are not managed by
+// axolgo are kept as they are.
+type Kubeconfig struct {
+	APIVersion     string                 `yaml:"apiVersion"`
+	Kind           string                 `yaml:"kind"`
+	Clusters       []KubeconfigEntry      `yaml:"clusters"`
+	Contexts       []KubeconfigEntry      `yaml:"contexts"`
+	CurrentContext string                 `yaml:"current-context"`
+	Users          []KubeconfigEntry      `yaml:"users"`
+	Extra          map[string]interface{} `yaml:",inline"`
+}
+
+// KubeconfigEntry is a named cluster, context or user of a kubeconfig
+type KubeconfigEntry struct {
+	Name    string                 `yaml:"name"`
+	Cluster map[string]interface{} `yaml:"cluster,omitempty"`
+	Context map[string]interface{} `yaml:"context,omitempty"`
+	User    map[string]interface{} `yaml:"user,omitempty"`
+}
+
+// KubeExec is an exec credential plugin of a kubeconfig user
+type KubeExec struct {
+	Command     string
+	Args        []string
+	Env         map[string]string
+	InstallHint string
+}
+
+// KubeconfigPath returns the path of the kubeconfig file. If path is
+// empty, it is the first file in KUBECONFIG or ~/.kube/config.
+func KubeconfigPath(path string) string {
+	if path != "" {
+		return axolgolibutil.ExpandPath(path)
+	}
+	if paths := filepath.SplitList(os.Getenv("KUBECONFIG")); len(paths) > 0 && paths[0] != "" {
+		return paths[0]
+	}
+
+	return axolgolibutil.ExpandPath(filepath.Join("~", ".kube", "config"))
+}
+
+// LoadKubeconfig reads a kubeconfig file. An empty kubeconfig is
+// returned if the file does not exist.
+func LoadKubeconfig(path string) (*Kubeconfig, error) {
+	content, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return &Kubeconfig{APIVersion: "v1", Kind: "Config"}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	k := &Kubeconfig{}
+	if err := yaml.Unmarshal(content, k); err != nil {
+		return nil, fmt.Errorf("failed to parse kubeconfig %s: %w", path, err)
+	}
+	if k.APIVersion == "" {
+		k.APIVersion = "v1"
+	}
+	if k.Kind == "" {
+		k.Kind = "Config"
+	}
+
+	return k, nil
+}
+
+// Save writes the kubeconfig to a file which is readable by the owner only
+func (k *Kubeconfig) Save(path string) error {
+	content, err := yaml.Marshal(k)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, content, 0600)
+}
+
+// SetCluster adds or replaces a cluster
+func (k *Kubeconfig) SetCluster(name string, server string, caData string) {
+	k.Clusters = setKubeconfigEntry(k.Clusters, KubeconfigEntry{
+		Name: name,
+		Cluster: map[string]interface{}{
+			"server":                     server,
+			"certificate-authority-data": caData,
+		},
+	})
+}
+
+// SetUser adds or replaces a user which gets credentials from an exec
+// plugin
+func (k *Kubeconfig) SetUser(name string, exec KubeExec) {
+	config := map[string]interface{}{
+		"apiVersion":         execCredentialAPIVersion,
+		"command":            exec.Command,
+		"args":               exec.Args,
+		"interactiveMode":    "Never",
+		"provideClusterInfo": false,
+	}
+	if exec.InstallHint != "" {
+		config["installHint"] = exec.InstallHint
+	}
+	if len(exec.Env) > 0 {
+		names := make([]string, 0, len(exec.Env))
+		for name := range exec.Env {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		var env []map[string]string
+		for _, name := range names {
+			env = append(env, map[string]string{"name": name, "value": exec.Env[name]})
+		}
+		config["env"] = env
+	}
+	k.Users = setKubeconfigEntry(k.Users, KubeconfigEntry{
+		Name: name,
+		User: map[string]interface{}{"exec": config},
+	})
+}
+
+// SetContext adds or replaces a context of a cluster and a user
+func (k *Kubeconfig) SetContext(name string, cluster string, user string) {
+	k.Contexts = setKubeconfigEntry(k.Contexts, KubeconfigEntry{
+		Name:    name,
+		Context: map[string]interface{}{"cluster": cluster, "user": user},
+	})
+}
+
+// setKubeconfigEntry replaces the entry of the same name or appends it
+func setKubeconfigEntry(entries []KubeconfigEntry, entry KubeconfigEntry) []KubeconfigEntry {
+	for i, e := range entries {
+		if e.Name == entry.Name {
+			entries[i] = entry
+			return entries
+		}
+	}
+
+	return append(entries, entry)
+}
+
+// AxolgoExec returns an exec credential plugin which runs this axolgo
+// binary with the arguments. The axolgo configuration in use is passed
+// in AXOLGO_CONFIG_PATH so that it is found in any working directory.
+func AxolgoExec(args ...string) (KubeExec, error) {
+	command, err := os.Executable()
+	if err != nil {
+		return KubeExec{}, fmt.Errorf("failed to find the axolgo binary: %w", err)
+	}
+	exec := KubeExec{
+		Command:     command,
+		Args:        args,
+		InstallHint: "Install axolgo from https://github.com/tchiunam/axolgo-cli",
+	}
+	if f := viper.ConfigFileUsed(); f != "" {
+		if dir, err := filepath.Abs(filepath.Dir(f)); err == nil {
+			exec.Env = map[string]string{"AXOLGO_CONFIG_PATH": dir}
+		}
+	}
+
+	return exec, nil
+}
+
+// GCPExec returns an exec credential plugin which runs the gcp command
+// of this axolgo binary with the arguments. The service account
+// impersonation in use, which may be given by the
+// --impersonate-service-account flag, is passed to the plugin so that
+// the token is of the same principal.
+func GCPExec(args ...string) (KubeExec, error) {
+	execArgs := append([]string{"gcp"}, args...)
+	if auth := LoadGCPAuth(); auth.ImpersonateServiceAccount != "" {
+		chain := append(append([]string{}, auth.Delegates...), auth.ImpersonateServiceAccount)
+		execArgs = append(execArgs, "--impersonate-service-account", strings.Join(chain, ","))
+	}
+
+	return AxolgoExec(execArgs...)
+}
+
+// PrintExecCredential prints the ExecCredential of a token for kubectl
+func PrintExecCredential(w io.Writer, token string, expiry time.Time) error {
+	status := map[string]string{"token": token}
+	if !expiry.IsZero() {
+		status["expirationTimestamp"] = expiry.UTC().Format(time.RFC3339)
+	}
+
+	return json.NewEncoder(w).Encode(map[string]interface{}{
+		"apiVersion": execCredentialAPIVersion,
+		"kind":       "ExecCredential",
+		"spec":       map[string]interface{}{},
+		"status":     status,
+	})
+}
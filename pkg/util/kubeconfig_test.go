@@ -0,0 +1,205 @@
+/*
+Copyright © 2022 tchiunam
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package util
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/tchiunam/axolgo-cli/pkg/types"
+)
+
+// The kubeconfig with an existing cluster and preferences
+const existingKubeconfig = `apiVersion: v1
+kind: Config
+clusters:
+- name: kind-dev
+  cluster:
+    server: https://127.0.0.1:6443
+contexts:
+- name: kind-dev
+  context:
+    cluster: kind-dev
+    user: kind-dev
+current-context: kind-dev
+users:
+- name: kind-dev
+  user:
+    token: secret
+preferences:
+  colors: true
+`
+
+// TestKubeconfigPath tests the KubeconfigPath function
+func TestKubeconfigPath(t *testing.T) {
+	home, _ := os.UserHomeDir()
+	cases := map[string]struct {
+		path       string
+		kubeconfig string
+		want       string
+	}{
+		"given path": {
+			path:       "/tmp/kubeconfig",
+			kubeconfig: "/tmp/other",
+			want:       "/tmp/kubeconfig",
+		},
+		"KUBECONFIG": {
+			kubeconfig: "/tmp/a" + string(filepath.ListSeparator) + "/tmp/b",
+			want:       "/tmp/a",
+		},
+		"default": {
+			want: filepath.Join(home, ".kube", "config"),
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			t.Setenv("KUBECONFIG", c.kubeconfig)
+			assert.Equal(t, c.want, KubeconfigPath(c.path))
+		})
+	}
+}
+
+// TestKubeconfigMerge tests that the entries are added to or replaced
+// in a kubeconfig file and the other entries are kept
+func TestKubeconfigMerge(t *testing.T) {
+	cases := map[string]struct {
+		existing string
+		clusters int
+	}{
+		"new file": {
+			clusters: 1,
+		},
+		"existing file": {
+			existing: existingKubeconfig,
+			clusters: 2,
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), ".kube", "config")
+			if c.existing != "" {
+				assert.NoError(t, os.MkdirAll(filepath.Dir(path), 0700))
+				assert.NoError(t, os.WriteFile(path, []byte(c.existing), 0600))
+			}
+
+			// Set the entries twice to make sure they are replaced
+			for _, server := range []string{"https://10.0.0.1", "https://10.0.0.2"} {
+				k, err := LoadKubeconfig(path)
+				assert.NoError(t, err)
+				k.SetCluster("gke_proj1_asia-east1_web", server, "Q0E=")
+				k.SetUser("gke_proj1_asia-east1_web", KubeExec{
+					Command: "/usr/local/bin/axolgo",
+					Args:    []string{"gcp", "container", "getToken"},
+					Env:     map[string]string{"AXOLGO_CONFIG_PATH": "/etc/axolgo"},
+				})
+				k.SetContext("gke_proj1_asia-east1_web", "gke_proj1_asia-east1_web", "gke_proj1_asia-east1_web")
+				k.CurrentContext = "gke_proj1_asia-east1_web"
+				assert.NoError(t, k.Save(path))
+			}
+
+			info, err := os.Stat(path)
+			assert.NoError(t, err)
+			assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+			k, err := LoadKubeconfig(path)
+			assert.NoError(t, err)
+			assert.Equal(t, "v1", k.APIVersion)
+			assert.Equal(t, "Config", k.Kind)
+			assert.Len(t, k.Clusters, c.clusters)
+			assert.Len(t, k.Users, c.clusters)
+			assert.Len(t, k.Contexts, c.clusters)
+			assert.Equal(t, "gke_proj1_asia-east1_web", k.CurrentContext)
+			cluster := k.Clusters[len(k.Clusters)-1]
+			assert.Equal(t, "https://10.0.0.2", cluster.Cluster["server"])
+			assert.Equal(t, "Q0E=", cluster.Cluster["certificate-authority-data"])
+			exec := k.Users[len(k.Users)-1].User["exec"].(map[string]interface{})
+			assert.Equal(t, "client.authentication.k8s.io/v1beta1", exec["apiVersion"])
+			assert.Equal(t, "/usr/local/bin/axolgo", exec["command"])
+			assert.Equal(t, []interface{}{"gcp", "container", "getToken"}, exec["args"])
+			assert.Equal(t, []interface{}{map[string]interface{}{"name": "AXOLGO_CONFIG_PATH", "value": "/etc/axolgo"}}, exec["env"])
+			if c.existing != "" {
+				assert.Equal(t, "secret", k.Users[0].User["token"])
+				assert.Equal(t, map[string]interface{}{"colors": true}, k.Extra["preferences"])
+			}
+		})
+	}
+}
+
+// TestLoadKubeconfigInvalid calls the LoadKubeconfig function
+// with an invalid file and makes sure it returns an error.
+func TestLoadKubeconfigInvalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config")
+	assert.NoError(t, os.WriteFile(path, []byte("clusters: {"), 0600))
+	_, err := LoadKubeconfig(path)
+	assert.Error(t, err)
+}
+
+// TestGCPExec tests the GCPExec function to make sure the service
+// account impersonation is passed to the plugin.
+func TestGCPExec(t *testing.T) {
+	cases := map[string]struct {
+		impersonate string
+		args        []string
+	}{
+		"no impersonation": {
+			args: []string{"gcp", "container", "getToken"},
+		},
+		"impersonation": {
+			impersonate: "deployer@proj1.iam.gserviceaccount.com",
+			args:        []string{"gcp", "container", "getToken", "--impersonate-service-account", "deployer@proj1.iam.gserviceaccount.com"},
+		},
+		"delegation chain": {
+			impersonate: "a@proj1.iam.gserviceaccount.com, deployer@proj2.iam.gserviceaccount.com",
+			args:        []string{"gcp", "container", "getToken", "--impersonate-service-account", "a@proj1.iam.gserviceaccount.com,deployer@proj2.iam.gserviceaccount.com"},
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			viper.Set("axolgo-config", types.AxolgoConfig{GCP: types.AxolgoConfigGCP{ImpersonateServiceAccount: c.impersonate}})
+			exec, err := GCPExec("container", "getToken")
+			assert.NoError(t, err)
+			assert.Equal(t, c.args, exec.Args)
+		})
+	}
+}
+
+// TestPrintExecCredential tests the PrintExecCredential function
+func TestPrintExecCredential(t *testing.T) {
+	var buf bytes.Buffer
+	expiry := time.Date(2023, 1, 1, 8, 0, 0, 0, time.FixedZone("HKT", 8*3600))
+	assert.NoError(t, PrintExecCredential(&buf, "token1", expiry))
+	assert.JSONEq(t, `{
+		"apiVersion": "client.authentication.k8s.io/v1beta1",
+		"kind": "ExecCredential",
+		"spec": {},
+		"status": {"token": "token1", "expirationTimestamp": "2023-01-01T00:00:00Z"}
+	}`, buf.String())
+}
@@ -0,0 +1,260 @@
+/*
+Copyright © 2022 tchiunam
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package util
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/spf13/viper"
+	"github.com/tchiunam/axolgo-cli/pkg/types"
+	axolgolibtypes "github.com/tchiunam/axolgo-lib/types"
+	"k8s.io/klog/v2"
+)
+
+// The sections of a parameter file. Static parameters are applied
+// on reboot and dynamic parameters are applied immediately.
+var parameterSections = []string{"static", "dynamic"}
+
+// Memory in GiB of each vCPU for the instance class families
+// which have a fixed ratio.
+var instanceFamilyMemoryPerVCPU = map[byte]int64{
+	'm': 4,
+	'r': 8,
+	'x': 16,
+}
+
+// Memory in GiB of the burstable instance classes
+var burstableInstanceSizeMemory = map[string]int64{
+	"micro":   1,
+	"small":   2,
+	"medium":  4,
+	"large":   8,
+	"xlarge":  16,
+	"2xlarge": 32,
+}
+
+// ParameterFileData is the data available to the template
+// expressions in a parameter file.
+type ParameterFileData struct {
+	// Values given with the --set flag
+	Values map[string]string
+	// The active axolgo configuration
+	Config types.AxolgoConfig
+}
+
+// ReadParameterFiles renders each parameter file as a Go template, merges
+// them in the given order and returns the static and dynamic parameters.
+// A file merged later overrides the values of the files before it.
+func ReadParameterFiles(files []string, data ParameterFileData) ([]axolgolibtypes.Parameter, []axolgolibtypes.Parameter, error) {
+	if len(files) == 0 {
+		return nil, nil, fmt.Errorf("no parameter file is given")
+	}
+
+	v := viper.New()
+	v.SetConfigType("yaml")
+	for i, f := range files {
+		content, err := renderParameterFile(f, data)
+		if err != nil {
+			return nil, nil, err
+		}
+		if i == 0 {
+			err = v.ReadConfig(bytes.NewReader(content))
+		} else {
+			err = v.MergeConfig(bytes.NewReader(content))
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to parse parameter file %s: %w", f, err)
+		}
+		klog.V(3).InfoS("Merged parameter file", "file", f)
+	}
+
+	parameters := [][]axolgolibtypes.Parameter{make([]axolgolibtypes.Parameter, 0), make([]axolgolibtypes.Parameter, 0)}
+	seen := make(map[string]string)
+	for i, t := range parameterSections {
+		section := v.GetStringMap(t)
+		// Sort the names so that the order of the parameters is deterministic
+		names := make([]string, 0, len(section))
+		for k := range section {
+			names = append(names, k)
+		}
+		sort.Strings(names)
+		for _, k := range names {
+			if other, ok := seen[k]; ok {
+				return nil, nil, fmt.Errorf("parameter %s is defined as both %s and %s", k, other, t)
+			}
+			seen[k] = t
+			parameters[i] = append(
+				parameters[i],
+				axolgolibtypes.Parameter{
+					Name:  aws.String(k),
+					Value: aws.String(fmt.Sprintf("%v", section[k])),
+				})
+		}
+	}
+
+	return parameters[0], parameters[1], nil
+}
+
+// ParameterValues converts the parameters read from parameter files to a
+// map of values keyed by the parameter names in lower case.
+func ParameterValues(parameterSets ...[]axolgolibtypes.Parameter) map[string]string {
+	values := make(map[string]string)
+	for _, parameters := range parameterSets {
+		for _, p := range parameters {
+			values[strings.ToLower(aws.ToString(p.Name))] = aws.ToString(p.Value)
+		}
+	}
+
+	return values
+}
+
+// renderParameterFile executes the template expressions in a parameter file
+func renderParameterFile(file string, data ParameterFileData) ([]byte, error) {
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	tmpl, err := template.New(filepath.Base(file)).
+		Option("missingkey=error").
+		Funcs(parameterFileFuncs).
+		Parse(string(content))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse template in parameter file %s: %w", file, err)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return nil, fmt.Errorf("failed to render parameter file %s: %w", file, err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// Functions available to the template expressions in a parameter file
+var parameterFileFuncs = template.FuncMap{
+	"dbInstanceClassMemory": DBInstanceClassMemory,
+	"add": func(a, b interface{}) (int64, error) {
+		return applyInt64(a, b, func(x, y int64) int64 { return x + y })
+	},
+	"sub": func(a, b interface{}) (int64, error) {
+		return applyInt64(a, b, func(x, y int64) int64 { return x - y })
+	},
+	"mul": func(a, b interface{}) (int64, error) {
+		return applyInt64(a, b, func(x, y int64) int64 { return x * y })
+	},
+	"div": func(a, b interface{}) (int64, error) {
+		if y, err := toInt64(b); err != nil {
+			return 0, err
+		} else if y == 0 {
+			return 0, fmt.Errorf("division by zero")
+		}
+		return applyInt64(a, b, func(x, y int64) int64 { return x / y })
+	},
+}
+
+// DBInstanceClassMemory returns the memory in bytes of a DB instance
+// class, e.g. db.r6g.large. It is the counterpart of the
+// DBInstanceClassMemory variable in the RDS parameter formulas.
+func DBInstanceClassMemory(instanceClass string) (int64, error) {
+	parts := strings.Split(instanceClass, ".")
+	if len(parts) != 3 || parts[0] != "db" || parts[1] == "" {
+		return 0, fmt.Errorf("invalid DB instance class: %s", instanceClass)
+	}
+	family, size := parts[1], parts[2]
+
+	var gib int64
+	if family[0] == 't' {
+		var ok bool
+		if gib, ok = burstableInstanceSizeMemory[size]; !ok {
+			return 0, fmt.Errorf("unknown size of DB instance class: %s", instanceClass)
+		}
+	} else {
+		perVCPU, ok := instanceFamilyMemoryPerVCPU[family[0]]
+		if !ok {
+			return 0, fmt.Errorf("unknown family of DB instance class: %s", instanceClass)
+		}
+		vCPU, err := instanceSizeVCPU(size)
+		if err != nil {
+			return 0, fmt.Errorf("unknown size of DB instance class: %s", instanceClass)
+		}
+		gib = perVCPU * vCPU
+	}
+
+	return gib << 30, nil
+}
+
+// instanceSizeVCPU returns the no. of vCPU of an instance size
+func instanceSizeVCPU(size string) (int64, error) {
+	switch size {
+	case "large":
+		return 2, nil
+	case "xlarge":
+		return 4, nil
+	}
+	n, err := strconv.ParseInt(strings.TrimSuffix(size, "xlarge"), 10, 64)
+	if err != nil || !strings.HasSuffix(size, "xlarge") || n <= 0 {
+		return 0, fmt.Errorf("invalid instance size: %s", size)
+	}
+
+	return n * 4, nil
+}
+
+// applyInt64 converts both operands to int64 and applies the operation
+func applyInt64(a, b interface{}, op func(int64, int64) int64) (int64, error) {
+	x, err := toInt64(a)
+	if err != nil {
+		return 0, err
+	}
+	y, err := toInt64(b)
+	if err != nil {
+		return 0, err
+	}
+
+	return op(x, y), nil
+}
+
+// toInt64 converts a template value to int64. Values from
+// the --set flag are strings so they are parsed as well.
+func toInt64(v interface{}) (int64, error) {
+	switch n := v.(type) {
+	case int:
+		return int64(n), nil
+	case int32:
+		return int64(n), nil
+	case int64:
+		return n, nil
+	case string:
+		return strconv.ParseInt(n, 10, 64)
+	}
+
+	return 0, fmt.Errorf("expected a number, got %v (%T)", v, v)
+}
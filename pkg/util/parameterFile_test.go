@@ -0,0 +1,179 @@
+/*
+Copyright © 2022 tchiunam
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package util
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/stretchr/testify/assert"
+	"github.com/tchiunam/axolgo-cli/pkg/types"
+	axolgolibtypes "github.com/tchiunam/axolgo-lib/types"
+)
+
+// The directory of the parameter files for testing
+var parameterTestdata = filepath.Join("..", "testdata", "parameters")
+
+// _parameterMap converts a list of parameters to a map for comparison
+func _parameterMap(parameters []axolgolibtypes.Parameter) map[string]string {
+	m := make(map[string]string, len(parameters))
+	for _, p := range parameters {
+		m[aws.ToString(p.Name)] = aws.ToString(p.Value)
+	}
+	return m
+}
+
+// TestReadParameterFiles tests the ReadParameterFiles function
+// to make sure the files are rendered and merged in order.
+func TestReadParameterFiles(t *testing.T) {
+	cases := map[string]struct {
+		files   []string
+		data    ParameterFileData
+		static  map[string]string
+		dynamic map[string]string
+	}{
+		"single file": {
+			files:   []string{filepath.Join(parameterTestdata, "db_parameters.yaml")},
+			static:  map[string]string{"pglogical.batch_inserts": "1"},
+			dynamic: map[string]string{"tcp_keepalives_interval": "300"},
+		},
+		"base file with overlay": {
+			files: []string{
+				filepath.Join(parameterTestdata, "db_parameters.yaml"),
+				filepath.Join(parameterTestdata, "db_parameters_prod.yaml"),
+			},
+			data: ParameterFileData{Values: map[string]string{"instanceClass": "db.r6g.large"}},
+			static: map[string]string{
+				"pglogical.batch_inserts": "1",
+				"shared_buffers":          "524288",
+			},
+			dynamic: map[string]string{
+				"tcp_keepalives_interval": "600",
+				"rds.force_ssl":           "1",
+			},
+		},
+		"value from axolgo config": {
+			files: []string{filepath.Join(parameterTestdata, "db_parameters_region.yaml")},
+			data: ParameterFileData{
+				Config: types.AxolgoConfig{AWS: types.AxolgoConfigAWS{Region: "ap-east-1"}},
+			},
+			static:  map[string]string{},
+			dynamic: map[string]string{"application_name": "axolgo-ap-east-1"},
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			static, dynamic, err := ReadParameterFiles(c.files, c.data)
+			assert.NoError(t, err)
+			assert.Equal(t, c.static, _parameterMap(static))
+			assert.Equal(t, c.dynamic, _parameterMap(dynamic))
+		})
+	}
+}
+
+// TestReadParameterFilesInvalid calls the ReadParameterFiles function with
+// invalid input and makes sure it returns an error.
+func TestReadParameterFilesInvalid(t *testing.T) {
+	cases := map[string]struct {
+		files []string
+		data  ParameterFileData
+	}{
+		"no file": {
+			files: nil,
+		},
+		"missing file": {
+			files: []string{filepath.Join(parameterTestdata, "missing.yaml")},
+		},
+		"missing template value": {
+			files: []string{filepath.Join(parameterTestdata, "db_parameters_prod.yaml")},
+			data:  ParameterFileData{Values: map[string]string{}},
+		},
+		"unknown instance class": {
+			files: []string{filepath.Join(parameterTestdata, "db_parameters_prod.yaml")},
+			data:  ParameterFileData{Values: map[string]string{"instanceClass": "db.q1.large"}},
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, _, err := ReadParameterFiles(c.files, c.data)
+			assert.Error(t, err)
+		})
+	}
+}
+
+// TestParameterValues tests the ParameterValues function
+func TestParameterValues(t *testing.T) {
+	cases := map[string]struct {
+		parameterSets [][]axolgolibtypes.Parameter
+		values        map[string]string
+	}{
+		"static and dynamic": {
+			parameterSets: [][]axolgolibtypes.Parameter{
+				{{Name: aws.String("shared_buffers"), Value: aws.String("524288")}},
+				{{Name: aws.String("Work_Mem"), Value: aws.String("4096")}},
+			},
+			values: map[string]string{"shared_buffers": "524288", "work_mem": "4096"},
+		},
+		"no parameter": {
+			values: map[string]string{},
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, c.values, ParameterValues(c.parameterSets...))
+		})
+	}
+}
+
+// TestDBInstanceClassMemory tests the DBInstanceClassMemory function
+func TestDBInstanceClassMemory(t *testing.T) {
+	cases := map[string]struct {
+		instanceClass string
+		memory        int64
+		isError       bool
+	}{
+		"burstable":        {instanceClass: "db.t3.medium", memory: 4 << 30},
+		"general purpose":  {instanceClass: "db.m6g.2xlarge", memory: 32 << 30},
+		"memory optimized": {instanceClass: "db.r6g.large", memory: 16 << 30},
+		"memory x2g":       {instanceClass: "db.x2g.xlarge", memory: 64 << 30},
+		"unknown family":   {instanceClass: "db.q1.large", isError: true},
+		"unknown size":     {instanceClass: "db.r6g.huge", isError: true},
+		"invalid format":   {instanceClass: "r6g.large", isError: true},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			memory, err := DBInstanceClassMemory(c.instanceClass)
+			if c.isError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, c.memory, memory)
+			}
+		})
+	}
+}
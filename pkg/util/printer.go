@@ -0,0 +1,109 @@
+/*
+Copyright © 2022 tchiunam
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package util
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"gopkg.in/yaml.v3"
+)
+
+// The supported output formats
+const (
+	OutputTable = "table"
+	OutputCSV   = "csv"
+	OutputJSON  = "json"
+	OutputYAML  = "yaml"
+)
+
+// OutputFormats is the list of supported output formats
+var OutputFormats = []string{OutputTable, OutputCSV, OutputJSON, OutputYAML}
+
+// PrintTable prints the rows as a table with aligned columns
+func PrintTable(w io.Writer, header []string, rows [][]string) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 3, ' ', 0)
+	if _, err := fmt.Fprintln(tw, strings.Join(header, "\t")); err != nil {
+		return err
+	}
+	for _, row := range rows {
+		if _, err := fmt.Fprintln(tw, strings.Join(row, "\t")); err != nil {
+			return err
+		}
+	}
+
+	return tw.Flush()
+}
+
+// PrintRecords prints the rows in the output format. In JSON and YAML,
+// each row is an object keyed by the lower case header.
+func PrintRecords(w io.Writer, format string, header []string, rows [][]string) error {
+	switch format {
+	case OutputTable:
+		return PrintTable(w, header, rows)
+	case OutputCSV:
+		cw := csv.NewWriter(w)
+		if err := cw.Write(header); err != nil {
+			return err
+		}
+		if err := cw.WriteAll(rows); err != nil {
+			return err
+		}
+		return cw.Error()
+	case OutputJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(records(header, rows))
+	case OutputYAML:
+		enc := yaml.NewEncoder(w)
+		defer enc.Close()
+		return enc.Encode(records(header, rows))
+	}
+
+	return fmt.Errorf("unsupported output format %q, expected one of %s", format, strings.Join(OutputFormats, ", "))
+}
+
+// records converts the rows to objects keyed by the lower case header
+func records(header []string, rows [][]string) []map[string]string {
+	keys := make([]string, len(header))
+	for i, h := range header {
+		keys[i] = strings.ReplaceAll(strings.ToLower(h), " ", "_")
+	}
+
+	result := make([]map[string]string, 0, len(rows))
+	for _, row := range rows {
+		r := make(map[string]string, len(keys))
+		for i, k := range keys {
+			if i < len(row) {
+				r[k] = row[i]
+			}
+		}
+		result = append(result, r)
+	}
+
+	return result
+}
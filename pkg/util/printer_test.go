@@ -0,0 +1,106 @@
+/*
+Copyright © 2022 tchiunam
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package util
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestPrintTable tests the PrintTable function
+func TestPrintTable(t *testing.T) {
+	cases := map[string]struct {
+		header []string
+		rows   [][]string
+		output string
+	}{
+		"header only": {
+			header: []string{"NAME", "VALUE"},
+			output: "NAME   VALUE\n",
+		},
+		"aligned columns": {
+			header: []string{"NAME", "VALUE"},
+			rows: [][]string{
+				{"shared_buffers", "524288"},
+				{"work_mem", "4096"},
+			},
+			output: "NAME             VALUE\nshared_buffers   524288\nwork_mem         4096\n",
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			var buf bytes.Buffer
+			assert.NoError(t, PrintTable(&buf, c.header, c.rows))
+			assert.Equal(t, c.output, buf.String())
+		})
+	}
+}
+
+// TestPrintRecords tests the PrintRecords function
+func TestPrintRecords(t *testing.T) {
+	header := []string{"NAME", "MULTI AZ"}
+	rows := [][]string{{"db-1", "true"}}
+
+	cases := map[string]struct {
+		format  string
+		output  string
+		isError bool
+	}{
+		"table": {
+			format: OutputTable,
+			output: "NAME   MULTI AZ\ndb-1   true\n",
+		},
+		"csv": {
+			format: OutputCSV,
+			output: "NAME,MULTI AZ\ndb-1,true\n",
+		},
+		"json": {
+			format: OutputJSON,
+			output: "[\n  {\n    \"multi_az\": \"true\",\n    \"name\": \"db-1\"\n  }\n]\n",
+		},
+		"yaml": {
+			format: OutputYAML,
+			output: "- multi_az: \"true\"\n  name: db-1\n",
+		},
+		"unsupported format": {
+			format:  "xml",
+			isError: true,
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			var buf bytes.Buffer
+			err := PrintRecords(&buf, c.format, header, rows)
+			if c.isError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, c.output, buf.String())
+			}
+		})
+	}
+}
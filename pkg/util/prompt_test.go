@@ -0,0 +1,56 @@
+/*
+Copyright © 2022 tchiunam
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package util
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestConfirm tests the Confirm function
+func TestConfirm(t *testing.T) {
+	cases := map[string]struct {
+		answer    string
+		confirmed bool
+	}{
+		"yes":             {answer: "yes\n", confirmed: true},
+		"y in upper case": {answer: "Y\n", confirmed: true},
+		"no":              {answer: "n\n", confirmed: false},
+		"empty":           {answer: "\n", confirmed: false},
+		"end of input":    {answer: "", confirmed: false},
+		"no line break":   {answer: "y", confirmed: true},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			var out bytes.Buffer
+			confirmed, err := Confirm(strings.NewReader(c.answer), &out, "Stop 2 instance(s)?")
+			assert.NoError(t, err)
+			assert.Equal(t, c.confirmed, confirmed)
+			assert.Equal(t, "Stop 2 instance(s)? [y/N]: ", out.String())
+		})
+	}
+}